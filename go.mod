@@ -7,6 +7,7 @@ require (
 	codev42-diagram v0.0.0
 	codev42-implementation v0.0.0
 	codev42-plan v0.0.0
+	github.com/gin-contrib/sse v0.1.0
 	github.com/gin-gonic/gin v1.10.0
 	google.golang.org/grpc v1.77.0
 )
@@ -15,16 +16,17 @@ replace (
 	codev42-analyzer => ./services/analyzer
 	codev42-diagram => ./services/diagram
 	codev42-implementation => ./services/implementation
+	codev42-llm => ./services/llm
 	codev42-plan => ./services/plan
 )
 
 require (
+	codev42-llm v0.0.0 // indirect
 	github.com/bytedance/sonic v1.11.6 // indirect
 	github.com/bytedance/sonic/loader v0.1.1 // indirect
 	github.com/cloudwego/base64x v0.1.4 // indirect
 	github.com/cloudwego/iasm v0.2.0 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.3 // indirect
-	github.com/gin-contrib/sse v0.1.0 // indirect
 	github.com/go-playground/locales v0.14.1 // indirect
 	github.com/go-playground/universal-translator v0.18.1 // indirect
 	github.com/go-playground/validator/v10 v10.20.0 // indirect
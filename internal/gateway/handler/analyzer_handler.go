@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"context"
 	"net/http"
 
 	analyzerpb "codev42-analyzer/proto/analyzer"
@@ -25,7 +24,7 @@ func (h *AnalyzerHandler) CombineCode(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.CombineCode(context.Background(), &req)
+	resp, err := h.grpcClient.CombineCode(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -42,11 +41,11 @@ func (h *AnalyzerHandler) AnalyzeCodeSegments(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.AnalyzeCodeSegments(context.Background(), &req)
+	resp, err := h.grpcClient.AnalyzeCodeSegments(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
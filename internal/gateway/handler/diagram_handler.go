@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"context"
 	"net/http"
 
 	diagrampb "codev42-diagram/proto/diagram"
@@ -25,7 +24,7 @@ func (h *DiagramHandler) GenerateDiagrams(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GenerateDiagrams(context.Background(), &req)
+	resp, err := h.grpcClient.GenerateDiagrams(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -42,7 +41,7 @@ func (h *DiagramHandler) GenerateClassDiagram(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GenerateClassDiagram(context.Background(), &req)
+	resp, err := h.grpcClient.GenerateClassDiagram(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -59,7 +58,7 @@ func (h *DiagramHandler) GenerateSequenceDiagram(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GenerateSequenceDiagram(context.Background(), &req)
+	resp, err := h.grpcClient.GenerateSequenceDiagram(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -76,11 +75,11 @@ func (h *DiagramHandler) GenerateFlowchartDiagram(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GenerateFlowchartDiagram(context.Background(), &req)
+	resp, err := h.grpcClient.GenerateFlowchartDiagram(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
-	"context"
+	"io"
 	"net/http"
+	"strconv"
 
 	implpb "codev42-implementation/proto/implementation"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,7 +27,7 @@ func (h *ImplementationHandler) ImplementPlan(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.ImplementPlan(context.Background(), &req)
+	resp, err := h.grpcClient.ImplementPlan(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -42,7 +44,24 @@ func (h *ImplementationHandler) GetImplementationStatus(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GetImplementationStatus(context.Background(), &req)
+	resp, err := h.grpcClient.GetImplementationStatus(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListJobs 구현 Job 목록 조회
+func (h *ImplementationHandler) ListJobs(c *gin.Context) {
+	var req implpb.ListJobsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ListJobs(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -59,11 +78,230 @@ func (h *ImplementationHandler) GetImplementationResult(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GetImplementationResult(context.Background(), &req)
+	resp, err := h.grpcClient.GetImplementationResult(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
+
+// CancelImplementation 구현 작업 취소
+func (h *ImplementationHandler) CancelImplementation(c *gin.Context) {
+	var req implpb.CancelImplementationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.CancelImplementation(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RetryPlans 실패했거나 계획과 시그니처가 맞지 않는 계획만 다시 구현
+func (h *ImplementationHandler) RetryPlans(c *gin.Context) {
+	var req implpb.RetryPlansRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.RetryPlans(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ApproveJob 승인 게이트에서 멈춘 구현 작업 승인
+func (h *ImplementationHandler) ApproveJob(c *gin.Context) {
+	var req implpb.ApproveJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ApproveJob(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RejectJob 승인 게이트에서 멈춘 구현 작업을 거절하고 피드백과 함께 다시 생성
+func (h *ImplementationHandler) RejectJob(c *gin.Context) {
+	var req implpb.RejectJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.RejectJob(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// WatchImplementation 구현 진행 이벤트를 Server-Sent Events로 전달
+// 재연결 시 브라우저가 보내는 Last-Event-ID 이후의 이벤트부터 이어서 전송
+func (h *ImplementationHandler) WatchImplementation(c *gin.Context) {
+	var req implpb.WatchImplementationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if lastEventID := c.GetHeader("Last-Event-ID"); lastEventID != "" {
+		seq, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+		req.AfterSeq = seq
+	}
+
+	stream, err := h.grpcClient.WatchImplementation(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// 첫 이벤트 전에 Job 조회 실패 등을 일반 에러 응답으로 돌려주기 위해 먼저 수신
+	event, err := stream.Recv()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		c.Render(-1, sse.Event{
+			Id:    strconv.FormatInt(event.Seq, 10),
+			Event: event.Type,
+			Data:  event,
+		})
+		event, err = stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				c.Render(-1, sse.Event{Event: "error", Data: gin.H{"error": err.Error()}})
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// ListImplementations 개발 계획의 구현 실행 기록 목록 조회
+func (h *ImplementationHandler) ListImplementations(c *gin.Context) {
+	var req implpb.ListImplementationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ListImplementations(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetImplementation 구현 실행 기록 조회
+func (h *ImplementationHandler) GetImplementation(c *gin.Context) {
+	var req implpb.GetImplementationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.GetImplementation(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DiffImplementations 두 구현 실행 기록 비교
+func (h *ImplementationHandler) DiffImplementations(c *gin.Context) {
+	var req implpb.DiffImplementationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.DiffImplementations(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetPipeline 프로젝트의 구현 파이프라인 정의(YAML) 저장
+func (h *ImplementationHandler) SetPipeline(c *gin.Context) {
+	var req implpb.SetPipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.SetPipeline(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPipeline 프로젝트에 적용되는 구현 파이프라인 정의 조회
+func (h *ImplementationHandler) GetPipeline(c *gin.Context) {
+	var req implpb.GetPipelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.GetPipeline(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePipeline 저장된 프로젝트의 구현 파이프라인 정의 삭제
+func (h *ImplementationHandler) DeletePipeline(c *gin.Context) {
+	var req implpb.DeletePipelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.DeletePipeline(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
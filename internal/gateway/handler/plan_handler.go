@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 
@@ -26,7 +25,7 @@ func (h *PlanHandler) GeneratePlan(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GeneratePlan(context.Background(), &req)
+	resp, err := h.grpcClient.GeneratePlan(tenantContext(c), &req)
 	if err != nil {
 		fmt.Printf("gRPC 호출 오류: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -44,7 +43,7 @@ func (h *PlanHandler) ModifyPlan(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.ModifyPlan(context.Background(), &req)
+	resp, err := h.grpcClient.ModifyPlan(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -61,7 +60,7 @@ func (h *PlanHandler) GetPlanList(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GetPlanList(context.Background(), &req)
+	resp, err := h.grpcClient.GetPlanList(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -78,11 +77,225 @@ func (h *PlanHandler) GetPlanById(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.grpcClient.GetPlanById(context.Background(), &req)
+	resp, err := h.grpcClient.GetPlanById(tenantContext(c), &req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
-}
\ No newline at end of file
+}
+
+// RegisterProviderCredential 테넌트별 LLM 자격 증명 등록
+func (h *PlanHandler) RegisterProviderCredential(c *gin.Context) {
+	var req planpb.RegisterProviderCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// 인증된 테넌트 자신의 자격 증명만 관리할 수 있음
+	if req.TenantId != "" && req.TenantId != authenticatedTenant(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "cannot manage credentials of another tenant"})
+		return
+	}
+
+	resp, err := h.grpcClient.RegisterProviderCredential(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteProviderCredential 테넌트 LLM 자격 증명 삭제
+func (h *PlanHandler) DeleteProviderCredential(c *gin.Context) {
+	var req planpb.DeleteProviderCredentialRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// 인증된 테넌트 자신의 자격 증명만 관리할 수 있음
+	if req.TenantId != "" && req.TenantId != authenticatedTenant(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "cannot manage credentials of another tenant"})
+		return
+	}
+
+	resp, err := h.grpcClient.DeleteProviderCredential(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefinePlan 피드백으로 계획 변경 사항 제안
+func (h *PlanHandler) RefinePlan(c *gin.Context) {
+	var req planpb.RefinePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.RefinePlan(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AcceptPlanRefinement 제안된 계획 변경 사항 수락
+func (h *PlanHandler) AcceptPlanRefinement(c *gin.Context) {
+	var req planpb.AcceptPlanRefinementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.AcceptPlanRefinement(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ExportPlan 개발 계획을 Markdown/JSON/YAML 문서로 내보내기
+func (h *PlanHandler) ExportPlan(c *gin.Context) {
+	var req planpb.ExportPlanRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ExportPlan(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ImportPlan 문서에서 새 개발 계획 가져오기
+func (h *PlanHandler) ImportPlan(c *gin.Context) {
+	var req planpb.ImportPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ImportPlan(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePlan 개발 계획 삭제 (구현 Job/결과 포함)
+func (h *PlanHandler) DeletePlan(c *gin.Context) {
+	var req planpb.DeletePlanRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.DeletePlan(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePlansByBranch 프로젝트 브랜치의 모든 개발 계획 삭제
+func (h *PlanHandler) DeletePlansByBranch(c *gin.Context) {
+	var req planpb.DeletePlansByBranchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.DeletePlansByBranch(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListPlanRevisions 계획 리비전 목록 조회
+func (h *PlanHandler) ListPlanRevisions(c *gin.Context) {
+	var req planpb.ListPlanRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.ListPlanRevisions(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPlanRevision 특정 리비전 조회
+func (h *PlanHandler) GetPlanRevision(c *gin.Context) {
+	var req planpb.GetPlanRevisionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.GetPlanRevision(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DiffPlanRevisions 두 리비전 비교
+func (h *PlanHandler) DiffPlanRevisions(c *gin.Context) {
+	var req planpb.DiffPlanRevisionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.DiffPlanRevisions(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevertPlan 계획을 특정 리비전으로 되돌림
+func (h *PlanHandler) RevertPlan(c *gin.Context) {
+	var req planpb.RevertPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.grpcClient.RevertPlan(tenantContext(c), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// tenantKey 인증된 테넌트 ID를 저장하는 gin context 키
+const tenantKey = "tenantID"
+
+// tenantExpiresKey 인증된 토큰의 만료 시각(Unix 초)을 저장하는 gin context 키
+const tenantExpiresKey = "tenantExpiresAt"
+
+var (
+	errInvalidTenantToken = errors.New("invalid tenant token")
+	errExpiredTenantToken = errors.New("tenant token has expired")
+)
+
+// tenantClaims 토큰에 서명하여 담는 내용
+type tenantClaims struct {
+	TenantID  string `json:"tid"`
+	KeyID     string `json:"kid"` // 서명한 키 ID (키 교체 시 이전 키로 발급한 토큰도 검증)
+	ExpiresAt int64  `json:"exp"` // 만료 시각 (Unix 초)
+}
+
+// TenantAuth Authorization: Bearer <내용>.<서명> 토큰으로 요청 테넌트를 인증.
+// 내용은 테넌트 ID, 키 ID, 만료 시각의 JSON (base64url), 서명은 키 ID의 키로 만든 내용의 HMAC-SHA256 (base64url)이며 운영자가 IssueToken으로 발급.
+// 만료된 토큰과 등록되지 않은 키 ID의 토큰은 거부하므로, 키를 목록에서 빼면 그 키로 발급한 토큰을 모두 폐기할 수 있음
+type TenantAuth struct {
+	keyID string            // 발급에 사용할 키 ID
+	keys  map[string][]byte // 키 ID별 검증 키 (발급 키 포함)
+	now   func() time.Time
+}
+
+// NewTenantAuth secret이 비어 있으면 테넌트 인증을 사용하지 않음 (모든 요청은 서비스 기본 자격 증명 사용).
+// secret은 keyID로 발급/검증하고, previousKeys(키 ID별 키)는 교체 전 키로 발급한 토큰의 검증에만 사용
+func NewTenantAuth(keyID string, secret string, previousKeys map[string]string) *TenantAuth {
+	auth := &TenantAuth{keyID: keyID, keys: make(map[string][]byte), now: time.Now}
+	if secret == "" {
+		return auth
+	}
+	for id, key := range previousKeys {
+		auth.keys[id] = []byte(key)
+	}
+	auth.keys[keyID] = []byte(secret)
+	return auth
+}
+
+// ParseTenantKeys 쉼표로 구분된 "키 ID=키" 목록 (예: "2025-01=...,2025-06=...")
+func ParseTenantKeys(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || key == "" {
+			return nil, errors.New("invalid tenant token key (expected <key id>=<key>)")
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// IssueToken 테넌트에게 발급할 토큰 생성 (ttl 후 만료)
+func (a *TenantAuth) IssueToken(tenantID string, ttl time.Duration) (string, error) {
+	if len(a.keys) == 0 {
+		return "", errors.New("TENANT_TOKEN_SECRET is not configured")
+	}
+	if tenantID == "" {
+		return "", errors.New("tenant id is required")
+	}
+	if ttl <= 0 {
+		return "", errors.New("token lifetime must be positive")
+	}
+	claims, err := json.Marshal(tenantClaims{TenantID: tenantID, KeyID: a.keyID, ExpiresAt: a.now().Add(ttl).Unix()})
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + sign(a.keys[a.keyID], payload), nil
+}
+
+// Authenticate 토큰이 있으면 검증하여 요청 테넌트로 지정 (유효하지 않거나 만료되면 401).
+// 토큰이 없는 요청은 테넌트 없이 서비스 기본 자격 증명으로 처리
+func (a *TenantAuth) Authenticate(c *gin.Context) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		c.Next()
+		return
+	}
+
+	claims, err := a.verify(header)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	c.Set(tenantKey, claims.TenantID)
+	c.Set(tenantExpiresKey, claims.ExpiresAt)
+	c.Next()
+}
+
+// RequireTenant 인증된 테넌트만 호출할 수 있는 요청 (테넌트 자격 증명 관리 등). 만료된 토큰은 거부
+func RequireTenant(c *gin.Context) {
+	if authenticatedTenant(c) == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "tenant authentication required"})
+		return
+	}
+	if time.Now().Unix() >= c.GetInt64(tenantExpiresKey) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": errExpiredTenantToken.Error()})
+		return
+	}
+	c.Next()
+}
+
+func (a *TenantAuth) verify(header string) (*tenantClaims, error) {
+	if len(a.keys) == 0 {
+		return nil, errors.New("tenant authentication is not configured")
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return nil, errInvalidTenantToken
+	}
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidTenantToken
+	}
+	content, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, errInvalidTenantToken
+	}
+	var claims tenantClaims
+	if err := json.Unmarshal(content, &claims); err != nil || claims.TenantID == "" {
+		return nil, errInvalidTenantToken
+	}
+	// 서명 확인 전에는 내용을 신뢰하지 않음 (키 ID로 검증 키만 선택)
+	key, ok := a.keys[claims.KeyID]
+	if !ok || !hmac.Equal([]byte(signature), []byte(sign(key, payload))) {
+		return nil, errInvalidTenantToken
+	}
+	if a.now().Unix() >= claims.ExpiresAt {
+		return nil, errExpiredTenantToken
+	}
+	return &claims, nil
+}
+
+func sign(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// authenticatedTenant Authenticate가 검증한 테넌트 ID (없으면 빈 문자열)
+func authenticatedTenant(c *gin.Context) string {
+	return c.GetString(tenantKey)
+}
+
+// tenantContext 요청 context에 인증된 테넌트를 gRPC metadata로 담아 반환 (클라이언트가 보낸 테넌트 헤더는 사용하지 않음)
+// 클라이언트가 연결을 끊으면 context가 취소되어 진행 중인 gRPC 호출(및 LLM 호출)도 중단
+func tenantContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if tenantID := authenticatedTenant(c); tenantID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-tenant-id", tenantID)
+	}
+	return ctx
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTenantRouter 인증된 테넌트를 그대로 돌려주는 테스트 라우터
+func newTenantRouter(auth *TenantAuth) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(auth.Authenticate)
+	router.GET("/tenant", func(c *gin.Context) {
+		c.String(http.StatusOK, authenticatedTenant(c))
+	})
+	router.GET("/credential", RequireTenant, func(c *gin.Context) {
+		c.String(http.StatusOK, authenticatedTenant(c))
+	})
+	return router
+}
+
+func serveTenant(router *gin.Engine, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestTenantAuthAuthenticatesIssuedToken(t *testing.T) {
+	auth := NewTenantAuth("k1", "secret", nil)
+	token, err := auth.IssueToken("team.a", time.Hour)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	router := newTenantRouter(auth)
+
+	resp := serveTenant(router, "/credential", map[string]string{"Authorization": "Bearer " + token})
+	if resp.Code != http.StatusOK || resp.Body.String() != "team.a" {
+		t.Fatalf("expected tenant team.a, got %d %q", resp.Code, resp.Body.String())
+	}
+}
+
+func TestTenantAuthRejectsInvalidTokens(t *testing.T) {
+	auth := NewTenantAuth("k1", "secret", nil)
+	other, _ := NewTenantAuth("k1", "other", nil).IssueToken("team-a", time.Hour)
+	unknownKey, _ := NewTenantAuth("k2", "secret", nil).IssueToken("team-a", time.Hour)
+	router := newTenantRouter(auth)
+
+	for name, header := range map[string]string{
+		"other secret": "Bearer " + other,
+		"unknown key":  "Bearer " + unknownKey,
+		"no signature": "Bearer team-a",
+		"legacy token": "Bearer team-a." + sign([]byte("secret"), "team-a"),
+		"not bearer":   "team-a.signature",
+	} {
+		if resp := serveTenant(router, "/tenant", map[string]string{"Authorization": header}); resp.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", name, resp.Code)
+		}
+	}
+
+	// 인증이 설정되지 않은 게이트웨이는 어떤 토큰도 받지 않음
+	if resp := serveTenant(newTenantRouter(NewTenantAuth("k1", "", nil)), "/tenant", map[string]string{"Authorization": "Bearer " + other}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without secret, got %d", resp.Code)
+	}
+}
+
+func TestTenantHeaderIsNotTrusted(t *testing.T) {
+	router := newTenantRouter(NewTenantAuth("k1", "secret", nil))
+
+	// 토큰 없는 요청은 X-Tenant-Id를 보내도 테넌트 없이 처리
+	resp := serveTenant(router, "/tenant", map[string]string{"X-Tenant-Id": "team-a"})
+	if resp.Code != http.StatusOK || resp.Body.String() != "" {
+		t.Fatalf("expected no tenant, got %d %q", resp.Code, resp.Body.String())
+	}
+	if resp := serveTenant(router, "/credential", map[string]string{"X-Tenant-Id": "team-a"}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected credential route to require tenant auth, got %d", resp.Code)
+	}
+}
+
+func TestTenantAuthRejectsExpiredTokens(t *testing.T) {
+	auth := NewTenantAuth("k1", "secret", nil)
+	issuedAt := time.Now().Add(-2 * time.Hour)
+	auth.now = func() time.Time { return issuedAt }
+	token, err := auth.IssueToken("team-a", time.Hour)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+	router := newTenantRouter(auth)
+
+	// 게이트웨이 시계로 만료 전에는 유효하지만, RequireTenant는 현재 시각으로 만료를 다시 확인
+	if resp := serveTenant(router, "/tenant", map[string]string{"Authorization": "Bearer " + token}); resp.Code != http.StatusOK || resp.Body.String() != "team-a" {
+		t.Fatalf("expected tenant team-a before expiry, got %d %q", resp.Code, resp.Body.String())
+	}
+	if resp := serveTenant(router, "/credential", map[string]string{"Authorization": "Bearer " + token}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected RequireTenant to reject expired token, got %d", resp.Code)
+	}
+
+	auth.now = time.Now
+	if resp := serveTenant(router, "/tenant", map[string]string{"Authorization": "Bearer " + token}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for expired token, got %d", resp.Code)
+	}
+}
+
+func TestTenantAuthAcceptsPreviousKeys(t *testing.T) {
+	oldToken, _ := NewTenantAuth("k1", "old", nil).IssueToken("team-a", time.Hour)
+
+	// 키를 교체해도 이전 키를 남겨 두면 기존 토큰을 사용할 수 있고, 이전 키를 빼면 그 키의 토큰은 모두 거부
+	rotated := newTenantRouter(NewTenantAuth("k2", "new", map[string]string{"k1": "old"}))
+	if resp := serveTenant(rotated, "/credential", map[string]string{"Authorization": "Bearer " + oldToken}); resp.Code != http.StatusOK {
+		t.Fatalf("expected token of previous key to be accepted, got %d", resp.Code)
+	}
+	revoked := newTenantRouter(NewTenantAuth("k2", "new", nil))
+	if resp := serveTenant(revoked, "/credential", map[string]string{"Authorization": "Bearer " + oldToken}); resp.Code != http.StatusUnauthorized {
+		t.Fatalf("expected token of removed key to be rejected, got %d", resp.Code)
+	}
+}
+
+func TestParseTenantKeys(t *testing.T) {
+	keys, err := ParseTenantKeys(" k1=a, k2=b=c ,")
+	if err != nil || len(keys) != 2 || keys["k1"] != "a" || keys["k2"] != "b=c" {
+		t.Fatalf("unexpected keys %v %v", keys, err)
+	}
+	if _, err := ParseTenantKeys("k1"); err == nil {
+		t.Fatal("expected error for key without id")
+	}
+}
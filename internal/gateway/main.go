@@ -1,11 +1,42 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"codev42/internal/gateway/handler"
 	"codev42/internal/gateway/routes"
 )
 
 func main() {
-	conns, router := routes.SetupRoutes()
+	issueToken := flag.String("issue-tenant-token", "", "print an Authorization token for the given tenant and exit")
+	tokenTTL := flag.Duration("tenant-token-ttl", 30*24*time.Hour, "lifetime of the token printed by -issue-tenant-token")
+	flag.Parse()
+
+	// 테넌트 토큰 서명 키 (비어 있으면 테넌트 인증 없이 서비스 기본 자격 증명만 사용)
+	// 키 교체 시 새 키를 TENANT_TOKEN_SECRET/TENANT_TOKEN_KEY_ID로, 이전 키를 TENANT_TOKEN_PREVIOUS_KEYS에 두고 토큰이 만료되면 제거
+	previousKeys, err := handler.ParseTenantKeys(os.Getenv("TENANT_TOKEN_PREVIOUS_KEYS"))
+	if err != nil {
+		log.Fatalf("Invalid TENANT_TOKEN_PREVIOUS_KEYS: %v", err)
+	}
+	keyID := os.Getenv("TENANT_TOKEN_KEY_ID")
+	if keyID == "" {
+		keyID = "default"
+	}
+	tenantAuth := handler.NewTenantAuth(keyID, os.Getenv("TENANT_TOKEN_SECRET"), previousKeys)
+	if *issueToken != "" {
+		token, err := tenantAuth.IssueToken(*issueToken, *tokenTTL)
+		if err != nil {
+			log.Fatalf("Failed to issue tenant token: %v", err)
+		}
+		fmt.Println(token)
+		return
+	}
+
+	conns, router := routes.SetupRoutes(tenantAuth)
 	router.Run(":8080")
 
 	for _, conn := range conns {
@@ -14,8 +14,9 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-func SetupRoutes() ([]*grpc.ClientConn, *gin.Engine) {
+func SetupRoutes(tenantAuth *handler.TenantAuth) ([]*grpc.ClientConn, *gin.Engine) {
 	router := gin.Default()
+	router.Use(tenantAuth.Authenticate)
 
 	planConn, err := grpc.NewClient("localhost:9091", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -52,13 +53,40 @@ func SetupRoutes() ([]*grpc.ClientConn, *gin.Engine) {
 	// Plan endpoints
 	router.POST("/generate-plan", planHandler.GeneratePlan)
 	router.POST("/modify-plan", planHandler.ModifyPlan)
+	router.POST("/refine-plan", planHandler.RefinePlan)
+	router.POST("/accept-plan-refinement", planHandler.AcceptPlanRefinement)
 	router.GET("/get-plan-list", planHandler.GetPlanList)
 	router.GET("/get-plan-by-id", planHandler.GetPlanById)
+	router.DELETE("/delete-plan", planHandler.DeletePlan)
+	router.DELETE("/delete-plans-by-branch", planHandler.DeletePlansByBranch)
+	router.GET("/list-plan-revisions", planHandler.ListPlanRevisions)
+	router.GET("/get-plan-revision", planHandler.GetPlanRevision)
+	router.GET("/diff-plan-revisions", planHandler.DiffPlanRevisions)
+	router.POST("/revert-plan", planHandler.RevertPlan)
+	router.GET("/export-plan", planHandler.ExportPlan)
+	router.POST("/import-plan", planHandler.ImportPlan)
+
+	// 테넌트 자격 증명은 인증된 테넌트만 자신의 것을 관리
+	tenantOnly := router.Group("/", handler.RequireTenant)
+	tenantOnly.POST("/register-provider-credential", planHandler.RegisterProviderCredential)
+	tenantOnly.DELETE("/delete-provider-credential", planHandler.DeleteProviderCredential)
 
 	// Implementation endpoints
 	router.POST("/implement-plan", implHandler.ImplementPlan)
 	router.GET("/implementation-status", implHandler.GetImplementationStatus)
+	router.GET("/list-jobs", implHandler.ListJobs)
 	router.GET("/implementation-result", implHandler.GetImplementationResult)
+	router.GET("/watch-implementation", implHandler.WatchImplementation)
+	router.POST("/cancel-implementation", implHandler.CancelImplementation)
+	router.POST("/retry-plans", implHandler.RetryPlans)
+	router.POST("/approve-job", implHandler.ApproveJob)
+	router.POST("/reject-job", implHandler.RejectJob)
+	router.GET("/list-implementations", implHandler.ListImplementations)
+	router.GET("/get-implementation", implHandler.GetImplementation)
+	router.GET("/diff-implementations", implHandler.DiffImplementations)
+	router.POST("/set-pipeline", implHandler.SetPipeline)
+	router.GET("/get-pipeline", implHandler.GetPipeline)
+	router.DELETE("/delete-pipeline", implHandler.DeletePipeline)
 
 	// Diagram endpoints
 	router.POST("/generate-diagrams", diagramHandler.GenerateDiagrams)
@@ -71,4 +99,4 @@ func SetupRoutes() ([]*grpc.ClientConn, *gin.Engine) {
 	router.POST("/analyze-code-segments", analyzerHandler.AnalyzeCodeSegments)
 
 	return []*grpc.ClientConn{planConn, implConn, diagramConn, analyzerConn}, router
-}
\ No newline at end of file
+}
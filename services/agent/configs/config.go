@@ -3,11 +3,24 @@ package configs
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 type Config struct {
 	OpenAiKey string
 
+	// LLM provider 설정 (openai, fake)
+	LLMProvider       string
+	OpenAIBaseURL     string
+	LLMChatModel      string
+	LLMEmbeddingModel string
+	FakeLLMScript     string
+
+	// LLM 호출 제한 (0이면 제한 없음). 한도를 넘은 호출은 대기
+	LLMMaxConcurrency    int
+	LLMRequestsPerMinute int
+	LLMTokensPerMinute   int
+
 	MySQLUser     string
 	MySQLPassword string
 	MySQLHost     string
@@ -30,10 +43,28 @@ func GetEnv(key, defaultValue string) string {
 	return value
 }
 
+func GetEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s must be an integer: %v", key, err)
+	}
+	return parsed, nil
+}
+
 func GetConfig() (*Config, error) {
 	config := &Config{
 		OpenAiKey: GetEnv("OPENAI_API_KEY", ""),
 
+		LLMProvider:       GetEnv("LLM_PROVIDER", "openai"),
+		OpenAIBaseURL:     GetEnv("OPENAI_BASE_URL", ""),
+		LLMChatModel:      GetEnv("LLM_CHAT_MODEL", ""),
+		LLMEmbeddingModel: GetEnv("LLM_EMBEDDING_MODEL", ""),
+		FakeLLMScript:     GetEnv("FAKE_LLM_SCRIPT", ""),
+
 		MySQLUser:     GetEnv("MYSQL_USER", "mainuser"),
 		MySQLPassword: GetEnv("MYSQL_PASSWORD", "user123"),
 		MySQLHost:     GetEnv("MYSQL_HOST", "localhost"),
@@ -48,7 +79,24 @@ func GetConfig() (*Config, error) {
 		GRPCPort: GetEnv("GRPC_PORT", "9090"),
 	}
 
-	if config.OpenAiKey == "" || config.MySQLPassword == "" || config.PineconeApiKey == "" {
+	intSettings := []struct {
+		key          string
+		defaultValue int
+		target       *int
+	}{
+		{"LLM_MAX_CONCURRENCY", 16, &config.LLMMaxConcurrency},
+		{"LLM_REQUESTS_PER_MINUTE", 0, &config.LLMRequestsPerMinute},
+		{"LLM_TOKENS_PER_MINUTE", 0, &config.LLMTokensPerMinute},
+	}
+	for _, setting := range intSettings {
+		value, err := GetEnvInt(setting.key, setting.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*setting.target = value
+	}
+
+	if (config.OpenAiKey == "" && config.LLMProvider != "fake") || config.MySQLPassword == "" || config.PineconeApiKey == "" {
 		fmt.Println("environment variable OPENAI_API_KEY, MYSQL_PASSWORD, PINECONE_API_KEY is required but not set", config.OpenAiKey, config.MySQLPassword, config.PineconeApiKey)
 		return nil, fmt.Errorf("environment variable OPENAI_API_KEY is required but not set")
 	}
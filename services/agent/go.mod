@@ -3,6 +3,7 @@ module codev42-agent
 go 1.25
 
 require (
+	codev42-llm v0.0.0
 	ariga.io/atlas-provider-gorm v0.5.1
 	github.com/invopop/jsonschema v0.13.0
 	github.com/milvus-io/milvus-sdk-go/v2 v2.4.2
@@ -65,3 +66,5 @@ require (
 	gorm.io/driver/sqlite v1.5.7 // indirect
 	gorm.io/driver/sqlserver v1.5.4 // indirect
 )
+
+replace codev42-llm => ../llm
@@ -11,6 +11,7 @@ import (
 	"codev42-agent/service"
 	"codev42-agent/storage"
 	"codev42-agent/storage/repo"
+	"codev42-llm/client"
 )
 
 type VectorDB interface {
@@ -23,6 +24,7 @@ type VectorDB interface {
 type AgentHandler struct {
 	pb.UnimplementedAgentServiceServer
 	Config        configs.Config
+	LLM           client.LLMProvider
 	VectorDB      VectorDB
 	RdbConnection *storage.RDBConnection
 }
@@ -91,8 +93,8 @@ func createPBResponse(devPlan *model.DevPlan) *pb.GeneratePlanResponse {
 }
 
 func (a *AgentHandler) GeneratePlan(ctx context.Context, request *pb.GeneratePlanRequest) (*pb.GeneratePlanResponse, error) {
-	masterAgent := service.NewMasterAgent(a.Config.OpenAiKey)
-	devPlan, err := masterAgent.Call(request.Prompt)
+	masterAgent := service.NewMasterAgent(a.LLM)
+	devPlan, err := masterAgent.Call(ctx, request.Prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate plan: %v", err)
 	}
@@ -178,15 +180,15 @@ func (a *AgentHandler) ModifyPlan(ctx context.Context, request *pb.ModifyPlanReq
 }
 
 func (a *AgentHandler) ImplementPlan(ctx context.Context, request *pb.ImplementPlanRequest) (*pb.ImplementPlanResponse, error) {
-	workerAgent := service.NewWorkerAgent(a.Config.OpenAiKey)
-	analyserAgent := service.NewAnalyserAgent(a.Config.OpenAiKey)
-	diagramAgent := service.NewDiagramAgent(a.Config.OpenAiKey)
+	workerAgent := service.NewWorkerAgent(a.LLM)
+	analyserAgent := service.NewAnalyserAgent(a.LLM)
+	diagramAgent := service.NewDiagramAgent(a.LLM)
 	planService := a.createPlanService()
 	existingPlan, err := planService.GetDevPlanByID(ctx, request.DevPlanId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get existing dev plan: %v", err)
 	}
-	results, err := workerAgent.ImplementPlan(existingPlan.Language, existingPlan.Plans)
+	results, err := workerAgent.ImplementPlan(ctx, existingPlan.Language, existingPlan.Plans)
 	if err != nil {
 		return nil, fmt.Errorf("failed to implement plan: %v", err)
 	}
@@ -194,11 +196,11 @@ func (a *AgentHandler) ImplementPlan(ctx context.Context, request *pb.ImplementP
 	for _, result := range results {
 		code += result.Code + "\n"
 	}
-	combinedResult, err := analyserAgent.CombineImplementation(results, existingPlan.Prompt)
+	combinedResult, err := analyserAgent.CombineImplementation(ctx, results, existingPlan.Prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to combine implementation: %v", err)
 	}
-	explainedSegments, err := analyserAgent.AnalyzeCodeSegments(combinedResult.Code, existingPlan.Language)
+	explainedSegments, err := analyserAgent.AnalyzeCodeSegments(ctx, combinedResult.Code, existingPlan.Language)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze code segments: %v", err)
 	}
@@ -210,7 +212,7 @@ func (a *AgentHandler) ImplementPlan(ctx context.Context, request *pb.ImplementP
 			Explanation: segment.Explanation,
 		}
 	}
-	diagrams, err := diagramAgent.ImplementDiagrams(combinedResult.Code, existingPlan.Prompt)
+	diagrams, err := diagramAgent.ImplementDiagrams(ctx, combinedResult.Code, existingPlan.Prompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to implement diagram: %v", err)
 	}
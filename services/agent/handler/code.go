@@ -9,17 +9,19 @@ import (
 	"codev42-agent/pb"
 	"codev42-agent/service"
 	"codev42-agent/storage"
+	"codev42-llm/client"
 )
 
 type CodeHandler struct {
 	pb.UnimplementedCodeServiceServer
 	Config        configs.Config
+	LLM           client.LLMProvider
 	VectorDB      VectorDB
 	RdbConnection *storage.RDBConnection
 }
 
 func (c *CodeHandler) SaveCode(ctx context.Context, request *pb.SaveCodeRequest) (*pb.SaveCodeResponse, error) {
-	agent := service.NewEmbeddingAgent(c.Config.OpenAiKey)
+	agent := service.NewEmbeddingAgent(c.LLM)
 
 	saveCodeResult, err := service.SaveCode(request.Code, request.FilePath, c.RdbConnection)
 	if err != nil {
@@ -35,7 +37,7 @@ func (c *CodeHandler) SaveCode(ctx context.Context, request *pb.SaveCodeRequest)
 			c.VectorDB.DeleteByID(ctx, "code", strconv.FormatInt(id, 10))
 		}
 	}
-	embeddings, err := agent.GenerateEmbedding(codes)
+	embeddings, err := agent.GenerateEmbedding(ctx, codes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get embedding: %v", err)
 	}
@@ -15,6 +15,7 @@ import (
 	pb "codev42-agent/pb"
 	"codev42-agent/storage"
 	"codev42-agent/storage/repo"
+	"codev42-llm/client"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -87,13 +88,33 @@ func main() {
 		log.Fatalf("Couldn't get config %v", err)
 	}
 
+	llmProvider, err := client.NewLLMProvider(client.ProviderConfig{
+		Provider:       config.LLMProvider,
+		APIKey:         config.OpenAiKey,
+		BaseURL:        config.OpenAIBaseURL,
+		ChatModel:      config.LLMChatModel,
+		EmbeddingModel: config.LLMEmbeddingModel,
+		FakeScriptPath: config.FakeLLMScript,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+	log.Printf("Using %s LLM provider", config.LLMProvider)
+
+	// 호출 한도를 넘은 LLM 호출(임베딩 포함)은 대기열에서 차례를 기다림
+	llmProvider = client.NewRateLimitedProvider(llmProvider, client.NewLimiter(client.LimitConfig{
+		MaxConcurrency:    config.LLMMaxConcurrency,
+		RequestsPerMinute: config.LLMRequestsPerMinute,
+		TokensPerMinute:   config.LLMTokensPerMinute,
+	}, client.LimitConfig{})) // 에이전트는 테넌트별 한도를 사용하지 않음
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", config.GRPCPort))
 	if err != nil {
 		log.Fatalf("Couldn't create connection tcp %v", err)
 	}
 
-	agentHandler := &handler.AgentHandler{Config: *config, VectorDB: vectorDB, RdbConnection: rdbConnection}
-	codeHandler := &handler.CodeHandler{Config: *config, VectorDB: vectorDB, RdbConnection: rdbConnection}
+	agentHandler := &handler.AgentHandler{Config: *config, LLM: llmProvider, VectorDB: vectorDB, RdbConnection: rdbConnection}
+	codeHandler := &handler.CodeHandler{Config: *config, LLM: llmProvider, VectorDB: vectorDB, RdbConnection: rdbConnection}
 	planHandler := &handler.PlanHandler{Config: *config, RdbConnection: rdbConnection}
 	grpcServer := grpc.NewServer()
 	pb.RegisterCodeServiceServer(grpcServer, codeHandler)
@@ -5,19 +5,16 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/shared"
+	"codev42-llm/client"
 )
 
 type embeddingAgent struct {
-	Client *openai.Client
+	LLM client.LLMProvider
 }
 
-func NewEmbeddingAgent(apiKey string) *embeddingAgent {
-	openaiClient := GetClient(apiKey)
-
+func NewEmbeddingAgent(llm client.LLMProvider) *embeddingAgent {
 	return &embeddingAgent{
-		Client: openaiClient.Client(),
+		LLM: llm,
 	}
 }
 
@@ -29,7 +26,7 @@ func float64ToFloat32(input []float64) []float32 {
 	return output
 }
 
-func (agent embeddingAgent) GenerateEmbedding(codes map[int64]string) (map[int64][]float32, error) {
+func (agent embeddingAgent) GenerateEmbedding(ctx context.Context, codes map[int64]string) (map[int64][]float32, error) {
 	type embeddingResult struct {
 		ID        int64
 		Embedding []float32
@@ -42,12 +39,11 @@ func (agent embeddingAgent) GenerateEmbedding(codes map[int64]string) (map[int64
 		wg.Add(1)
 		go func(chunk string, id int64) {
 			defer wg.Done()
-			response, err := agent.Client.Embeddings.New(context.TODO(), openai.EmbeddingNewParams{
-				Input:          openai.F[openai.EmbeddingNewParamsInputUnion](shared.UnionString(chunk)),
-				Model:          openai.F(openai.EmbeddingModelTextEmbedding3Small),
-				Dimensions:     openai.F(int64(128)),
-				EncodingFormat: openai.F(openai.EmbeddingNewParamsEncodingFormatFloat),
-				User:           openai.F("hado_coder"),
+			embedding, err := agent.LLM.Embed(ctx, client.EmbeddingRequest{
+				Input:      chunk,
+				Model:      client.ModelTextEmbedding3Small,
+				Dimensions: 128,
+				User:       "hado_coder",
 			})
 			if err != nil {
 				errorChan <- err
@@ -55,7 +51,7 @@ func (agent embeddingAgent) GenerateEmbedding(codes map[int64]string) (map[int64
 			}
 			resultChan <- embeddingResult{
 				ID:        id,
-				Embedding: float64ToFloat32(response.Data[0].Embedding),
+				Embedding: float64ToFloat32(embedding),
 			}
 		}(chunk, id)
 	}
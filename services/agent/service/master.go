@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"codev42-llm/client"
+
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go"
 )
 
 type Annotation struct {
@@ -27,14 +28,12 @@ type DevPlan struct {
 }
 
 type MasterAgent struct {
-	Client *openai.Client
+	LLM client.LLMProvider
 }
 
-func NewMasterAgent(apiKey string) *MasterAgent {
-	openaiClient := GetClient(apiKey)
-
+func NewMasterAgent(llm client.LLMProvider) *MasterAgent {
 	return &MasterAgent{
-		Client: openaiClient.Client(),
+		LLM: llm,
 	}
 }
 
@@ -50,7 +49,7 @@ func GenerateDevPlanSchema[T any]() interface{} {
 
 var DevPlanResponseSchema = GenerateDevPlanSchema[DevPlan]()
 
-func (agent MasterAgent) Call(prompt string) (*DevPlan, error) {
+func (agent MasterAgent) Call(ctx context.Context, prompt string) (*DevPlan, error) {
 	prompt = "프롬프트: " + prompt
 	prompt += `
 	다음 규칙에 따라 개발 계획을 수립해야 합니다
@@ -62,24 +61,12 @@ func (agent MasterAgent) Call(prompt string) (*DevPlan, error) {
 	print("> ")
 	println(prompt)
 
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        openai.F("development_plan"),
-		Description: openai.F("A development plan with annotations of functions and classes"),
-		Schema:      openai.F(DevPlanResponseSchema),
-		Strict:      openai.Bool(true),
-	}
-
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type:       openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(schemaParam),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4o2024_11_20),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "development_plan",
+		SchemaDescription: "A development plan with annotations of functions and classes",
+		Schema:            DevPlanResponseSchema,
 	})
 
 	if err != nil {
@@ -87,8 +74,8 @@ func (agent MasterAgent) Call(prompt string) (*DevPlan, error) {
 	}
 
 	devPlan := &DevPlan{}
-	fmt.Printf("Chat: %v\n", chat)
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), devPlan)
+	fmt.Printf("Chat: %v\n", content)
+	err = json.Unmarshal([]byte(content), devPlan)
 	if err != nil {
 		return nil, err
 	}
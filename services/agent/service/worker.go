@@ -2,6 +2,7 @@ package service
 
 import (
 	"codev42-agent/model"
+	"codev42-llm/client"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,7 +10,6 @@ import (
 	"time"
 
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go"
 )
 
 type ImplementResult struct {
@@ -17,14 +17,12 @@ type ImplementResult struct {
 }
 
 type WorkerAgent struct {
-	Client *openai.Client
+	LLM client.LLMProvider
 }
 
-func NewWorkerAgent(apiKey string) *WorkerAgent {
-	openaiClient := GetClient(apiKey)
-
+func NewWorkerAgent(llm client.LLMProvider) *WorkerAgent {
 	return &WorkerAgent{
-		Client: openaiClient.Client(),
+		LLM: llm,
 	}
 }
 
@@ -38,7 +36,7 @@ func GenerateImplementResultSchema[T any]() interface{} {
 	return schema
 }
 
-func (agent WorkerAgent) call(language string, devPlan string) (*ImplementResult, error) {
+func (agent WorkerAgent) call(ctx context.Context, language string, devPlan string) (*ImplementResult, error) {
 	prompt := "개발 계획: " + devPlan
 	prompt += "언어: " + language
 	prompt += `
@@ -51,24 +49,12 @@ func (agent WorkerAgent) call(language string, devPlan string) (*ImplementResult
 
 	var ImplementResultResponseSchema = GenerateImplementResultSchema[ImplementResult]()
 
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        openai.F("development_result"),
-		Description: openai.F("code and description of development result from the dev plan"),
-		Schema:      openai.F(ImplementResultResponseSchema),
-		Strict:      openai.Bool(true),
-	}
-
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type:       openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(schemaParam),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4oMini),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4oMini,
+		Prompt:            prompt,
+		SchemaName:        "development_result",
+		SchemaDescription: "code and description of development result from the dev plan",
+		Schema:            ImplementResultResponseSchema,
 	})
 
 	if err != nil {
@@ -77,15 +63,15 @@ func (agent WorkerAgent) call(language string, devPlan string) (*ImplementResult
 	}
 
 	ImplementResult := &ImplementResult{}
-	fmt.Println("chat.Choices[0].Message.Content: ", chat.Choices[0].Message)
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), ImplementResult)
+	fmt.Println("content: ", content)
+	err = json.Unmarshal([]byte(content), ImplementResult)
 	if err != nil {
 		return nil, err
 	}
 	return ImplementResult, nil
 }
 
-func (agent WorkerAgent) ImplementPlan(language string, plans []model.Plan) ([]*ImplementResult, error) {
+func (agent WorkerAgent) ImplementPlan(ctx context.Context, language string, plans []model.Plan) ([]*ImplementResult, error) {
 	var wg sync.WaitGroup
 	resultChan := make(chan *ImplementResult, len(plans))
 	errorChan := make(chan error, len(plans))
@@ -104,7 +90,7 @@ func (agent WorkerAgent) ImplementPlan(language string, plans []model.Plan) ([]*
 			}
 			fmt.Printf("Plan %d started\n", index)
 			startTime := time.Now()
-			ImplementResult, err := agent.call(language, planString)
+			ImplementResult, err := agent.call(ctx, language, planString)
 			fmt.Println("ImplementResult: ", ImplementResult)
 			endTime := time.Now()
 			elapsedTime := endTime.Sub(startTime)
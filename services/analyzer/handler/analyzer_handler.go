@@ -7,6 +7,7 @@ import (
 	"codev42-analyzer/configs"
 	"codev42-analyzer/proto/analyzer"
 	"codev42-analyzer/service"
+	"codev42-llm/client"
 )
 
 type AnalyzerHandler struct {
@@ -15,8 +16,8 @@ type AnalyzerHandler struct {
 	analyserAgent *service.AnalyserAgent
 }
 
-func NewAnalyzerHandler(config configs.Config) *AnalyzerHandler {
-	analyserAgent := service.NewAnalyserAgent(config.OpenAiKey)
+func NewAnalyzerHandler(config configs.Config, llm client.LLMProvider) *AnalyzerHandler {
+	analyserAgent := service.NewAnalyserAgent(llm)
 
 	return &AnalyzerHandler{
 		Config:        config,
@@ -33,7 +34,7 @@ func (h *AnalyzerHandler) CombineCode(ctx context.Context, req *analyzer.Combine
 		})
 	}
 
-	result, err := h.analyserAgent.CombineImplementation(implementResults, req.Purpose)
+	result, err := h.analyserAgent.CombineImplementation(ctx, implementResults, req.Purpose)
 	if err != nil {
 		return &analyzer.CombineCodeResponse{
 			Code:    "",
@@ -51,7 +52,7 @@ func (h *AnalyzerHandler) CombineCode(ctx context.Context, req *analyzer.Combine
 
 // AnalyzeCodeSegments 코드 분석 및 설명 생성
 func (h *AnalyzerHandler) AnalyzeCodeSegments(ctx context.Context, req *analyzer.AnalyzeCodeSegmentsRequest) (*analyzer.AnalyzeCodeSegmentsResponse, error) {
-	segments, err := h.analyserAgent.AnalyzeCodeSegments(req.Code, req.Language)
+	segments, err := h.analyserAgent.AnalyzeCodeSegments(ctx, req.Code, req.Language)
 	if err != nil {
 		return &analyzer.AnalyzeCodeSegmentsResponse{
 			CodeSegments: nil,
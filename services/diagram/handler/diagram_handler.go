@@ -7,6 +7,7 @@ import (
 	"codev42-diagram/configs"
 	"codev42-diagram/proto/diagram"
 	"codev42-diagram/service"
+	"codev42-llm/client"
 )
 
 type DiagramHandler struct {
@@ -15,8 +16,8 @@ type DiagramHandler struct {
 	diagramAgent *service.DiagramAgent
 }
 
-func NewDiagramHandler(config configs.Config) *DiagramHandler {
-	diagramAgent := service.NewDiagramAgent(config.OpenAiKey)
+func NewDiagramHandler(config configs.Config, llm client.LLMProvider) *DiagramHandler {
+	diagramAgent := service.NewDiagramAgent(llm)
 
 	return &DiagramHandler{
 		Config:       config,
@@ -26,7 +27,7 @@ func NewDiagramHandler(config configs.Config) *DiagramHandler {
 
 // GenerateDiagrams 모든 다이어그램 병렬 생성
 func (h *DiagramHandler) GenerateDiagrams(ctx context.Context, req *diagram.GenerateDiagramsRequest) (*diagram.GenerateDiagramsResponse, error) {
-	results, err := h.diagramAgent.ImplementDiagrams(req.Code, req.Purpose)
+	results, err := h.diagramAgent.ImplementDiagrams(ctx, req.Code, req.Purpose)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate diagrams: %v", err)
 	}
@@ -57,7 +58,7 @@ func (h *DiagramHandler) GenerateDiagrams(ctx context.Context, req *diagram.Gene
 
 // GenerateClassDiagram 클래스 다이어그램 생성
 func (h *DiagramHandler) GenerateClassDiagram(ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
-	result, err := h.diagramAgent.GenerateClassDiagram(req.Code, req.Purpose)
+	result, err := h.diagramAgent.GenerateClassDiagram(ctx, req.Code, req.Purpose)
 	if err != nil {
 		return &diagram.GenerateDiagramResponse{
 			Diagram: "",
@@ -77,7 +78,7 @@ func (h *DiagramHandler) GenerateClassDiagram(ctx context.Context, req *diagram.
 
 // GenerateSequenceDiagram 시퀀스 다이어그램 생성
 func (h *DiagramHandler) GenerateSequenceDiagram(ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
-	result, err := h.diagramAgent.GenerateSequenceDiagram(req.Code, req.Purpose)
+	result, err := h.diagramAgent.GenerateSequenceDiagram(ctx, req.Code, req.Purpose)
 	if err != nil {
 		return &diagram.GenerateDiagramResponse{
 			Diagram: "",
@@ -97,7 +98,7 @@ func (h *DiagramHandler) GenerateSequenceDiagram(ctx context.Context, req *diagr
 
 // GenerateFlowchartDiagram 플로우차트 생성
 func (h *DiagramHandler) GenerateFlowchartDiagram(ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
-	result, err := h.diagramAgent.GenerateFlowchartDiagram(req.Code, req.Purpose)
+	result, err := h.diagramAgent.GenerateFlowchartDiagram(ctx, req.Code, req.Purpose)
 	if err != nil {
 		return &diagram.GenerateDiagramResponse{
 			Diagram: "",
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/url"
 
 	"codev42-diagram/configs"
 	"codev42-diagram/handler"
 	"codev42-diagram/proto/diagram"
+	"codev42-diagram/storage"
+	"codev42-llm/client"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
@@ -21,6 +24,53 @@ func main() {
 
 	log.Printf("Diagram Service configuration loaded")
 
+	providerConfig := client.ProviderConfig{
+		Provider:       config.LLMProvider,
+		APIKey:         config.OpenAiKey,
+		BaseURL:        config.OpenAIBaseURL,
+		ChatModel:      config.LLMChatModel,
+		EmbeddingModel: config.LLMEmbeddingModel,
+		FakeScriptPath: config.FakeLLMScript,
+
+		TenantAllowedHosts: client.ParseAllowedHosts(config.TenantLLMAllowedHosts),
+	}
+	llmProvider, err := client.NewLLMProvider(providerConfig)
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+	log.Printf("Using %s LLM provider", config.LLMProvider)
+
+	// 테넌트별 자격 증명 (x-tenant-id metadata로 요청마다 선택)
+	var credentialResolver client.CredentialResolver
+	if config.CredentialEncryptionKey != "" {
+		cipher, err := client.NewCipher(config.CredentialEncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to create credential cipher: %v", err)
+		}
+		rdbConnection, err := storage.NewRDBConnection(mysqlDSN(config))
+		if err != nil {
+			log.Fatalf("Failed to connect to MySQL: %v", err)
+		}
+		defer rdbConnection.Close()
+
+		credentialResolver = client.NewCredentialStore(rdbConnection.DB, cipher)
+		log.Printf("Per-tenant LLM credentials enabled")
+	}
+	// 서비스 전체와 테넌트별 호출 한도를 넘은 LLM 호출은 대기열에서 차례를 기다림
+	limiter := client.NewLimiter(
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMMaxConcurrency,
+			RequestsPerMinute: config.LLMRequestsPerMinute,
+			TokensPerMinute:   config.LLMTokensPerMinute,
+		},
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMTenantMaxConcurrency,
+			RequestsPerMinute: config.LLMTenantRequestsPerMinute,
+			TokensPerMinute:   config.LLMTenantTokensPerMinute,
+		},
+	)
+	llm := client.NewRateLimitedProvider(client.NewProviderPool(llmProvider, credentialResolver, providerConfig), limiter)
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", config.GRPCPort))
 	if err != nil {
 		log.Fatalf("Failed to create TCP listener: %v", err)
@@ -28,7 +78,7 @@ func main() {
 
 	grpcServer := grpc.NewServer()
 
-	diagramHandler := handler.NewDiagramHandler(*config)
+	diagramHandler := handler.NewDiagramHandler(*config, llm)
 	diagram.RegisterDiagramServiceServer(grpcServer, diagramHandler)
 
 	reflection.Register(grpcServer)
@@ -38,3 +88,14 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+func mysqlDSN(config *configs.Config) string {
+	return fmt.Sprintf(
+		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		config.MySQLUser,
+		url.QueryEscape(config.MySQLPassword),
+		config.MySQLHost,
+		config.MySQLPort,
+		config.MySQLDB,
+	)
+}
@@ -1,25 +1,22 @@
 package service
 
 import (
-	"codev42-diagram/client"
+	"codev42-llm/client"
 	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go"
 )
 
 type AnalyserAgent struct {
-	Client *openai.Client
+	LLM client.LLMProvider
 }
 
-func NewAnalyserAgent(apiKey string) *AnalyserAgent {
-	openaiClient := client.GetClient(apiKey)
-
+func NewAnalyserAgent(llm client.LLMProvider) *AnalyserAgent {
 	return &AnalyserAgent{
-		Client: openaiClient.Client(),
+		LLM: llm,
 	}
 }
 
@@ -41,7 +38,7 @@ func GenerateImplementResultSchema[T any]() interface{} {
 	return schema
 }
 
-func (agent AnalyserAgent) call(codes []string, purpose string) (*CombinedResult, error) {
+func (agent AnalyserAgent) call(ctx context.Context, codes []string, purpose string) (*CombinedResult, error) {
 	prompt := "목적: " + purpose + "\n\n"
 	for i, code := range codes {
 		prompt += fmt.Sprintf("코드 %d:\n```\n%s\n```\n\n", i+1, code)
@@ -51,24 +48,12 @@ func (agent AnalyserAgent) call(codes []string, purpose string) (*CombinedResult
 
 	var combinedResultSchema = GenerateImplementResultSchema[CombinedResult]()
 
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        openai.F("combined_result"),
-		Description: openai.F("the result of analyzing and combining the codes"),
-		Schema:      openai.F(combinedResultSchema),
-		Strict:      openai.Bool(true),
-	}
-
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type:       openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(schemaParam),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4o2024_11_20),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "combined_result",
+		SchemaDescription: "the result of analyzing and combining the codes",
+		Schema:            combinedResultSchema,
 	})
 
 	if err != nil {
@@ -76,14 +61,14 @@ func (agent AnalyserAgent) call(codes []string, purpose string) (*CombinedResult
 	}
 
 	combinedResult := &CombinedResult{}
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), combinedResult)
+	err = json.Unmarshal([]byte(content), combinedResult)
 	if err != nil {
 		return nil, err
 	}
 	return combinedResult, nil
 }
 
-func (agent AnalyserAgent) CombineImplementation(implementResults []*ImplementResult, purpose string) (*CombinedResult, error) {
+func (agent AnalyserAgent) CombineImplementation(ctx context.Context, implementResults []*ImplementResult, purpose string) (*CombinedResult, error) {
 	var codes []string
 	for _, result := range implementResults {
 		if strings.TrimSpace(result.Code) != "" {
@@ -94,7 +79,7 @@ func (agent AnalyserAgent) CombineImplementation(implementResults []*ImplementRe
 		return nil, fmt.Errorf("there is no code")
 	}
 
-	return agent.call(codes, purpose)
+	return agent.call(ctx, codes, purpose)
 }
 
 type CodeSegment struct {
@@ -107,7 +92,7 @@ type CodeSegmentAnalysisResult struct {
 }
 
 // AnalyzeCodeSegments 코드를 분석하여 중요한 세그먼트들을 식별하고 설명
-func (agent AnalyserAgent) AnalyzeCodeSegments(code, language string) ([]CodeSegment, error) {
+func (agent AnalyserAgent) AnalyzeCodeSegments(ctx context.Context, code, language string) ([]CodeSegment, error) {
 	// 코드에 줄 번호 추가
 	lines := strings.Split(code, "\n")
 	numberedCode := ""
@@ -143,22 +128,12 @@ func (agent AnalyserAgent) AnalyzeCodeSegments(code, language string) ([]CodeSeg
 
 	var segmentResultSchema = GenerateImplementResultSchema[CodeSegmentAnalysisResult]()
 
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type: openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(openai.ResponseFormatJSONSchemaJSONSchemaParam{
-					Name:        openai.F("code_segment_analysis"),
-					Description: openai.F("analysis of important code segments with explanations"),
-					Schema:      openai.F(segmentResultSchema),
-					Strict:      openai.Bool(true),
-				}),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4o2024_11_20),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "code_segment_analysis",
+		SchemaDescription: "analysis of important code segments with explanations",
+		Schema:            segmentResultSchema,
 	})
 
 	if err != nil {
@@ -166,7 +141,7 @@ func (agent AnalyserAgent) AnalyzeCodeSegments(code, language string) ([]CodeSeg
 	}
 
 	var result CodeSegmentAnalysisResult
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), &result)
+	err = json.Unmarshal([]byte(content), &result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse code segment analysis result: %v", err)
 	}
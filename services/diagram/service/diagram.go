@@ -1,27 +1,23 @@
 package service
 
 import (
-	"codev42-diagram/client"
 	"codev42-diagram/util"
+	"codev42-llm/client"
 	"context"
 	"encoding/json"
 	"fmt"
 	"sync"
-
-	"github.com/openai/openai-go"
 )
 
 type DiagramAgent struct {
-	Client        *openai.Client
+	LLM           client.LLMProvider
 	AnalyserAgent *AnalyserAgent // 코드 세그먼트 분석을 위한 AnalyserAgent 추가
 }
 
-func NewDiagramAgent(apiKey string) *DiagramAgent {
-	openaiClient := client.GetClient(apiKey)
-
+func NewDiagramAgent(llm client.LLMProvider) *DiagramAgent {
 	return &DiagramAgent{
-		Client:        openaiClient.Client(),
-		AnalyserAgent: NewAnalyserAgent(apiKey), // AnalyserAgent 초기화
+		LLM:           llm,
+		AnalyserAgent: NewAnalyserAgent(llm), // AnalyserAgent 초기화
 	}
 }
 
@@ -47,11 +43,11 @@ type DiagramTypeSelectionResult struct {
 	SelectedType []DiagramType `json:"list of selectedType"` // 선택된 다이어그램 타입
 }
 
-func (agent DiagramAgent) call(code string, purpose string, diagramType DiagramType) (*DiagramResult, error) {
+func (agent DiagramAgent) call(ctx context.Context, code string, purpose string, diagramType DiagramType) (*DiagramResult, error) {
 	const maxRetries = 3
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		result, err := agent.callOnce(code, purpose, diagramType, attempt)
+		result, err := agent.callOnce(ctx, code, purpose, diagramType, attempt)
 		if err != nil {
 			if attempt == maxRetries {
 				return nil, fmt.Errorf("failed to generate diagram after %d attempts: %v", maxRetries, err)
@@ -77,7 +73,7 @@ func (agent DiagramAgent) call(code string, purpose string, diagramType DiagramT
 }
 
 // callOnce는 단일 시도로 다이어그램을 생성
-func (agent DiagramAgent) callOnce(code string, purpose string, diagramType DiagramType, attempt int) (*DiagramResult, error) {
+func (agent DiagramAgent) callOnce(ctx context.Context, code string, purpose string, diagramType DiagramType, attempt int) (*DiagramResult, error) {
 	retryNote := ""
 	if attempt > 1 {
 		retryNote = fmt.Sprintf("\n\n이것은 %d번째 시도입니다. 다이어그램이 적절한 Mermaid 문법을 따르고 의미있는 내용을 포함하도록 해주세요.", attempt)
@@ -98,23 +94,14 @@ mermaid 다이어그램 코드만을 반환하고, 설명이나 추가 텍스트
 	// 간단한 스키마 정의 - 다이어그램 코드만 받기
 	simpleDiagramResultSchema := GenerateImplementResultSchema[DiagramResult]()
 
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type: openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(openai.ResponseFormatJSONSchemaJSONSchemaParam{
-					Name:        openai.F("diagram_result"),
-					Description: openai.F("mermaid diagram code with type"),
-					Schema:      openai.F(simpleDiagramResultSchema),
-					Strict:      openai.Bool(true),
-				}),
-			},
-		),
-		Model:       openai.F(openai.ChatModelGPT4o2024_11_20),
-		Temperature: openai.F(0.0),
+	temperature := 0.0
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "diagram_result",
+		SchemaDescription: "mermaid diagram code with type",
+		Schema:            simpleDiagramResultSchema,
+		Temperature:       &temperature,
 	})
 
 	if err != nil {
@@ -122,7 +109,7 @@ mermaid 다이어그램 코드만을 반환하고, 설명이나 추가 텍스트
 	}
 
 	var simpleDiagramResult DiagramResult
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), &simpleDiagramResult)
+	err = json.Unmarshal([]byte(content), &simpleDiagramResult)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse JSON response: %v", err)
 	}
@@ -137,22 +124,22 @@ mermaid 다이어그램 코드만을 반환하고, 설명이나 추가 텍스트
 }
 
 // GenerateClassDiagram은 클래스 다이어그램을 생성합니다
-func (agent DiagramAgent) GenerateClassDiagram(code string, purpose string) (*DiagramResult, error) {
-	return agent.call(code, purpose, DiagramTypeClass)
+func (agent DiagramAgent) GenerateClassDiagram(ctx context.Context, code string, purpose string) (*DiagramResult, error) {
+	return agent.call(ctx, code, purpose, DiagramTypeClass)
 }
 
 // GenerateSequenceDiagram은 시퀀스 다이어그램을 생성합니다
-func (agent DiagramAgent) GenerateSequenceDiagram(code string, purpose string) (*DiagramResult, error) {
-	return agent.call(code, purpose, DiagramTypeSequence)
+func (agent DiagramAgent) GenerateSequenceDiagram(ctx context.Context, code string, purpose string) (*DiagramResult, error) {
+	return agent.call(ctx, code, purpose, DiagramTypeSequence)
 }
 
 // GenerateFlowchartDiagram은 플로우차트 다이어그램을 생성합니다
-func (agent DiagramAgent) GenerateFlowchartDiagram(code string, purpose string) (*DiagramResult, error) {
-	return agent.call(code, purpose, DiagramTypeFlowchart)
+func (agent DiagramAgent) GenerateFlowchartDiagram(ctx context.Context, code string, purpose string) (*DiagramResult, error) {
+	return agent.call(ctx, code, purpose, DiagramTypeFlowchart)
 }
 
 // ImplementDiagrams는 세 가지 다이어그램을 병렬로 생성합니다
-func (agent DiagramAgent) ImplementDiagrams(code string, purpose string) ([]*DiagramResult, error) {
+func (agent DiagramAgent) ImplementDiagrams(ctx context.Context, code string, purpose string) ([]*DiagramResult, error) {
 	var wg sync.WaitGroup
 	resultChan := make(chan *DiagramResult, 3)
 	errorChan := make(chan error, 3)
@@ -161,7 +148,7 @@ func (agent DiagramAgent) ImplementDiagrams(code string, purpose string) ([]*Dia
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result, err := agent.GenerateClassDiagram(code, purpose)
+		result, err := agent.GenerateClassDiagram(ctx, code, purpose)
 		if err != nil {
 			errorChan <- err
 			return
@@ -173,7 +160,7 @@ func (agent DiagramAgent) ImplementDiagrams(code string, purpose string) ([]*Dia
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result, err := agent.GenerateSequenceDiagram(code, purpose)
+		result, err := agent.GenerateSequenceDiagram(ctx, code, purpose)
 		if err != nil {
 			errorChan <- err
 			return
@@ -185,7 +172,7 @@ func (agent DiagramAgent) ImplementDiagrams(code string, purpose string) ([]*Dia
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		result, err := agent.GenerateFlowchartDiagram(code, purpose)
+		result, err := agent.GenerateFlowchartDiagram(ctx, code, purpose)
 		if err != nil {
 			errorChan <- err
 			return
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// RDBConnection : MySQL 연동 구조체 (GORM 이용)
+type RDBConnection struct {
+	DB *gorm.DB
+}
+
+func NewRDBConnection(dsn string) (*RDBConnection, error) {
+	gormDB, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gorm db: %w", err)
+	}
+
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sql.DB from gorm: %w", err)
+	}
+	if err = sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping mysql: %w", err)
+	}
+
+	return &RDBConnection{DB: gormDB}, nil
+}
+
+// Close : DB 종료
+func (c *RDBConnection) Close() error {
+	if c.DB != nil {
+		if sqlDB, err := c.DB.DB(); err == nil {
+			if errClose := sqlDB.Close(); errClose != nil {
+				log.Printf("failed to close gorm db: %v", errClose)
+			}
+		}
+	}
+	return nil
+}
+
+// WithTransaction : 트랜잭션 안에서 fn 실행
+func (c *RDBConnection) WithTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return c.DB.WithContext(ctx).Transaction(fn)
+}
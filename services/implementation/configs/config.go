@@ -3,11 +3,32 @@ package configs
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 type Config struct {
 	OpenAiKey string
 
+	// LLM provider 설정 (openai, fake)
+	LLMProvider       string
+	OpenAIBaseURL     string
+	LLMChatModel      string
+	LLMEmbeddingModel string
+	FakeLLMScript     string
+
+	// LLM 호출 제한 (서비스 전체 / 테넌트별, 0이면 제한 없음). 한도를 넘은 호출은 대기
+	LLMMaxConcurrency          int
+	LLMRequestsPerMinute       int
+	LLMTokensPerMinute         int
+	LLMTenantMaxConcurrency    int
+	LLMTenantRequestsPerMinute int
+	LLMTenantTokensPerMinute   int
+
+	// 테넌트별 자격 증명 암호화 키 (base64, 32바이트). 비어 있으면 기본 키만 사용
+	CredentialEncryptionKey string
+	// 테넌트 자격 증명의 BaseURL로 허용하는 호스트 (쉼표로 구분). 비어 있으면 공인 주소의 https 호스트만 허용
+	TenantLLMAllowedHosts string
+
 	MySQLUser     string
 	MySQLPassword string
 	MySQLHost     string
@@ -16,6 +37,42 @@ type Config struct {
 
 	GRPCPort string
 
+	// Job 저장소 (mysql, sqlite, memory)
+	JobStore   string
+	SQLitePath string
+
+	// Job 워커 설정 (레플리카마다 고유한 WorkerID 필요)
+	WorkerID          string
+	WorkerConcurrency int
+	JobLeaseSeconds   int
+	JobPollSeconds    int
+	JobMaxAttempts    int
+
+	// 프로젝트별 파이프라인 정의 파일 디렉터리 (<프로젝트 ID>.yaml, DB에 저장된 정의가 우선). 비어 있으면 사용하지 않음
+	PipelineDir string
+
+	// 요청에 승인 게이트가 없을 때 사용할 기본 게이트 (쉼표로 구분: code, result)
+	ApprovalGates string
+
+	// 끝난 Job 보관 기간 (0이면 삭제하지 않음)과 정리 주기
+	JobTTLSeconds          int
+	JobReapIntervalSeconds int
+
+	// 생성 코드 컴파일 검사 후 수정 요청 최대 횟수 (검사기가 있는 언어만)
+	CompileRepairRounds int
+
+	// 생성 코드 단위 테스트 (Go만, go 명령이 있을 때)
+	GoBinary               string
+	UnitTestTimeoutSeconds int
+	TestRepairRounds       int
+
+	// 파이프라인 단계별 제한 시간 (0이면 제한 없음)
+	FetchPlanTimeoutSeconds        int
+	GenerateCodeTimeoutSeconds     int
+	RunTestsTimeoutSeconds         int
+	GenerateDiagramsTimeoutSeconds int
+	AnalyzeCodeTimeoutSeconds      int
+
 	// 서비스 간 통신 엔드포인트
 	PlanServiceAddr     string
 	DiagramServiceAddr  string
@@ -30,10 +87,33 @@ func GetEnv(key, defaultValue string) string {
 	return value
 }
 
+func GetEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s must be an integer: %v", key, err)
+	}
+	return parsed, nil
+}
+
 func GetConfig() (*Config, error) {
+	hostname, _ := os.Hostname()
+
 	config := &Config{
 		OpenAiKey: GetEnv("OPENAI_API_KEY", ""),
 
+		LLMProvider:       GetEnv("LLM_PROVIDER", "openai"),
+		OpenAIBaseURL:     GetEnv("OPENAI_BASE_URL", ""),
+		LLMChatModel:      GetEnv("LLM_CHAT_MODEL", ""),
+		LLMEmbeddingModel: GetEnv("LLM_EMBEDDING_MODEL", ""),
+		FakeLLMScript:     GetEnv("FAKE_LLM_SCRIPT", ""),
+
+		CredentialEncryptionKey: GetEnv("CREDENTIAL_ENCRYPTION_KEY", ""),
+		TenantLLMAllowedHosts:   GetEnv("TENANT_LLM_ALLOWED_HOSTS", ""),
+
 		MySQLUser:     GetEnv("MYSQL_USER", "mainuser"),
 		MySQLPassword: GetEnv("MYSQL_PASSWORD", "user123"),
 		MySQLHost:     GetEnv("MYSQL_HOST", "localhost"),
@@ -42,13 +122,57 @@ func GetConfig() (*Config, error) {
 
 		GRPCPort: GetEnv("GRPC_PORT", "9092"),
 
+		JobStore:   GetEnv("JOB_STORE", "mysql"),
+		SQLitePath: GetEnv("SQLITE_PATH", "implementation_jobs.db"),
+
+		WorkerID: GetEnv("WORKER_ID", hostname),
+
+		ApprovalGates: GetEnv("APPROVAL_GATES", ""),
+		PipelineDir:   GetEnv("PIPELINE_DIR", ""),
+
+		GoBinary: GetEnv("GO_BINARY", "go"),
+
 		// 서비스 엔드포인트
 		PlanServiceAddr:     GetEnv("PLAN_SERVICE_ADDR", "localhost:9091"),
 		DiagramServiceAddr:  GetEnv("DIAGRAM_SERVICE_ADDR", "localhost:9093"),
 		AnalyzerServiceAddr: GetEnv("ANALYZER_SERVICE_ADDR", "localhost:9094"),
 	}
 
-	if config.OpenAiKey == "" {
+	intSettings := []struct {
+		key          string
+		defaultValue int
+		target       *int
+	}{
+		{"LLM_MAX_CONCURRENCY", 16, &config.LLMMaxConcurrency},
+		{"LLM_REQUESTS_PER_MINUTE", 0, &config.LLMRequestsPerMinute},
+		{"LLM_TOKENS_PER_MINUTE", 0, &config.LLMTokensPerMinute},
+		{"LLM_TENANT_MAX_CONCURRENCY", 8, &config.LLMTenantMaxConcurrency},
+		{"LLM_TENANT_REQUESTS_PER_MINUTE", 0, &config.LLMTenantRequestsPerMinute},
+		{"LLM_TENANT_TOKENS_PER_MINUTE", 0, &config.LLMTenantTokensPerMinute},
+		{"WORKER_CONCURRENCY", 4, &config.WorkerConcurrency},
+		{"JOB_LEASE_SECONDS", 60, &config.JobLeaseSeconds},
+		{"JOB_POLL_SECONDS", 2, &config.JobPollSeconds},
+		{"JOB_MAX_ATTEMPTS", 3, &config.JobMaxAttempts},
+		{"JOB_TTL_SECONDS", 7 * 24 * 60 * 60, &config.JobTTLSeconds},
+		{"JOB_REAP_INTERVAL_SECONDS", 60 * 60, &config.JobReapIntervalSeconds},
+		{"COMPILE_REPAIR_ROUNDS", 2, &config.CompileRepairRounds},
+		{"UNIT_TEST_TIMEOUT_SECONDS", 60, &config.UnitTestTimeoutSeconds},
+		{"TEST_REPAIR_ROUNDS", 1, &config.TestRepairRounds},
+		{"FETCH_PLAN_TIMEOUT_SECONDS", 30, &config.FetchPlanTimeoutSeconds},
+		{"GENERATE_CODE_TIMEOUT_SECONDS", 900, &config.GenerateCodeTimeoutSeconds},
+		{"RUN_TESTS_TIMEOUT_SECONDS", 600, &config.RunTestsTimeoutSeconds},
+		{"GENERATE_DIAGRAMS_TIMEOUT_SECONDS", 300, &config.GenerateDiagramsTimeoutSeconds},
+		{"ANALYZE_CODE_TIMEOUT_SECONDS", 300, &config.AnalyzeCodeTimeoutSeconds},
+	}
+	for _, setting := range intSettings {
+		value, err := GetEnvInt(setting.key, setting.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*setting.target = value
+	}
+
+	if config.OpenAiKey == "" && config.LLMProvider != "fake" {
 		return nil, fmt.Errorf("environment variable OPENAI_API_KEY is required but not set")
 	}
 
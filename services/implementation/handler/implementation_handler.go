@@ -2,23 +2,39 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"codev42-implementation/configs"
 	"codev42-implementation/proto/analyzer"
 	"codev42-implementation/proto/diagram"
 	"codev42-implementation/proto/implementation"
 	"codev42-implementation/proto/plan"
+	"codev42-implementation/queue"
 	"codev42-implementation/service"
+	"codev42-llm/client"
 )
 
 type ImplementationHandler struct {
 	implementation.UnimplementedImplementationServiceServer
-	Config         configs.Config
-	workerAgent    *service.WorkerAgent
-	planClient     plan.PlanServiceClient
-	diagramClient  diagram.DiagramServiceClient
-	analyzerClient analyzer.AnalyzerServiceClient
+	Config              configs.Config
+	workerAgent         *service.WorkerAgent
+	unitTester          *service.UnitTester
+	jobStore            queue.JobStore
+	codeCache           queue.CodeCache
+	implementationStore queue.ImplementationStore
+	pipelineStore       queue.PipelineStore
+	planClient          plan.PlanServiceClient
+	diagramClient       diagram.DiagramServiceClient
+	analyzerClient      analyzer.AnalyzerServiceClient
+	stageTimeouts       map[string]time.Duration
+
+	// 이 레플리카에서 실행 중인 Job의 취소 함수 (다른 레플리카의 Job은 lease 갱신 실패로 중단)
+	runningMu sync.Mutex
+	running   map[string]context.CancelFunc
 }
 
 func NewImplementationHandler(
@@ -26,105 +42,440 @@ func NewImplementationHandler(
 	planClient plan.PlanServiceClient,
 	diagramClient diagram.DiagramServiceClient,
 	analyzerClient analyzer.AnalyzerServiceClient,
+	jobStore queue.JobStore,
+	codeCache queue.CodeCache,
+	implementationStore queue.ImplementationStore,
+	pipelineStore queue.PipelineStore,
+	llm client.LLMProvider,
 ) *ImplementationHandler {
-	workerAgent := service.NewWorkerAgent(config.OpenAiKey)
+	workerAgent := service.NewWorkerAgent(llm, config.CompileRepairRounds)
+	seconds := func(n int) time.Duration { return time.Duration(n) * time.Second }
 
 	return &ImplementationHandler{
-		Config:         config,
-		workerAgent:    workerAgent,
-		planClient:     planClient,
-		diagramClient:  diagramClient,
-		analyzerClient: analyzerClient,
+		Config:              config,
+		workerAgent:         workerAgent,
+		unitTester:          service.NewUnitTester(workerAgent, config.GoBinary, time.Duration(config.UnitTestTimeoutSeconds)*time.Second, config.TestRepairRounds),
+		jobStore:            jobStore,
+		codeCache:           codeCache,
+		implementationStore: implementationStore,
+		pipelineStore:       pipelineStore,
+		planClient:          planClient,
+		diagramClient:       diagramClient,
+		analyzerClient:      analyzerClient,
+		stageTimeouts: map[string]time.Duration{
+			stageFetchPlan:        seconds(config.FetchPlanTimeoutSeconds),
+			stageGenerateCode:     seconds(config.GenerateCodeTimeoutSeconds),
+			stageRunTests:         seconds(config.RunTestsTimeoutSeconds),
+			stageGenerateDiagrams: seconds(config.GenerateDiagramsTimeoutSeconds),
+			stageAnalyzeCode:      seconds(config.AnalyzeCodeTimeoutSeconds),
+		},
+		running: make(map[string]context.CancelFunc),
 	}
 }
 
-// ImplementPlan 코드 구현 (동기 실행)
+// ImplementPlan 코드 구현 시작 (비동기, Job ID 반환)
 func (h *ImplementationHandler) ImplementPlan(ctx context.Context, req *implementation.ImplementPlanRequest) (*implementation.ImplementPlanResponse, error) {
-	// Plan 서비스에서 개발 계획 조회
-	planResp, err := h.planClient.GetPlanById(ctx, &plan.GetPlanByIdRequest{
-		DevPlanId: req.DevPlanId,
-	})
+	gates, err := h.approvalGates(req.ApprovalGates)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch plan: %v", err)
-	}
-
-	// planpb.Plan -> service.Plan 변환
-	plans := make([]service.Plan, 0, len(planResp.Plans))
-	for _, pbPlan := range planResp.Plans {
-		annotations := make([]service.Annotation, 0, len(pbPlan.Annotations))
-		for _, pbAnnotation := range pbPlan.Annotations {
-			annotations = append(annotations, service.Annotation{
-				Name:        pbAnnotation.Name,
-				Description: pbAnnotation.Description,
-				Params:      pbAnnotation.Params,
-				Returns:     pbAnnotation.Returns,
-			})
+		return nil, err
+	}
+
+	// Job은 pending 상태로 저장되고, 레플리카의 워커가 lease를 획득해 처리
+	job, err := h.jobStore.CreateJob(ctx, req.DevPlanId, client.TenantFromContext(ctx), gates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+
+	return &implementation.ImplementPlanResponse{
+		JobId:   job.ID,
+		Status:  string(job.Status),
+		Message: "Implementation job created",
+	}, nil
+}
+
+// approvalGates 요청한 승인 게이트 검증 (요청에 없으면 서비스 기본값 APPROVAL_GATES)
+func (h *ImplementationHandler) approvalGates(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		requested = strings.Split(h.Config.ApprovalGates, ",")
+	}
+
+	var gates []string
+	for _, gate := range requested {
+		gate = strings.TrimSpace(gate)
+		if gate == "" {
+			continue
 		}
-		plans = append(plans, service.Plan{
-			ClassName:   pbPlan.ClassName,
-			Annotations: annotations,
-		})
+		if !queue.IsApprovalGate(gate) {
+			return nil, fmt.Errorf("unknown approval gate %q (expected %s or %s)", gate, queue.ApprovalGateCode, queue.ApprovalGateResult)
+		}
+		gates = append(gates, gate)
+	}
+	return gates, nil
+}
+
+// GetImplementationStatus 구현 상태 조회
+func (h *ImplementationHandler) GetImplementationStatus(ctx context.Context, req *implementation.GetImplementationStatusRequest) (*implementation.GetImplementationStatusResponse, error) {
+	job, err := h.jobStore.GetJob(ctx, req.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+
+	return &implementation.GetImplementationStatusResponse{
+		JobId:       job.ID,
+		Status:      string(job.Status),
+		Progress:    job.Progress,
+		CurrentStep: job.CurrentStep,
+		CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.Format(time.RFC3339),
+		PendingGate: job.PendingGate,
+	}, nil
+}
+
+// ListJobs 구현 Job 목록 조회 (결과는 GetImplementationResult로 조회)
+func (h *ImplementationHandler) ListJobs(ctx context.Context, req *implementation.ListJobsRequest) (*implementation.ListJobsResponse, error) {
+	filter := queue.JobFilter{
+		DevPlanID: req.DevPlanId,
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+	}
+	for _, status := range req.Statuses {
+		filter.Statuses = append(filter.Statuses, queue.JobStatus(status))
+	}
+	var err error
+	if filter.CreatedAfter, err = parseOptionalTime(req.CreatedAfter); err != nil {
+		return nil, fmt.Errorf("invalid CreatedAfter: %v", err)
+	}
+	if filter.CreatedBefore, err = parseOptionalTime(req.CreatedBefore); err != nil {
+		return nil, fmt.Errorf("invalid CreatedBefore: %v", err)
 	}
 
-	// AI로 코드 생성
-	results, err := h.workerAgent.ImplementPlan(planResp.Language, plans)
+	jobs, nextPageToken, err := h.jobStore.ListJobs(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate code: %v", err)
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
 	}
 
-	// 코드 결과 조합
-	var code string
-	if len(results) > 0 && results[0] != nil {
-		code = results[0].Code
+	pbJobs := make([]*implementation.JobElement, len(jobs))
+	for i, job := range jobs {
+		pbJobs[i] = &implementation.JobElement{
+			JobId:       job.ID,
+			DevPlanId:   job.DevPlanID,
+			Status:      string(job.Status),
+			Progress:    job.Progress,
+			CurrentStep: job.CurrentStep,
+			Error:       job.Error,
+			CreatedAt:   job.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   job.UpdatedAt.Format(time.RFC3339),
+			PendingGate: job.PendingGate,
+		}
+		if job.CompletedAt != nil {
+			pbJobs[i].CompletedAt = job.CompletedAt.Format(time.RFC3339)
+		}
 	}
 
-	if code == "" {
-		return nil, fmt.Errorf("generated code is empty")
+	return &implementation.ListJobsResponse{
+		Jobs:          pbJobs,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// parseOptionalTime RFC3339 시간 (비어 있으면 zero time)
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
 	}
+	return time.Parse(time.RFC3339, value)
+}
 
-	// Diagram 서비스로 다이어그램 생성
-	diagramResp, err := h.diagramClient.GenerateDiagrams(ctx, &diagram.GenerateDiagramsRequest{
-		Code:    code,
-		Purpose: fmt.Sprintf("Development Plan ID: %d", req.DevPlanId),
-	})
+// GetImplementationResult 구현 결과 조회
+func (h *ImplementationHandler) GetImplementationResult(ctx context.Context, req *implementation.GetImplementationResultRequest) (*implementation.GetImplementationResultResponse, error) {
+	job, err := h.jobStore.GetJob(ctx, req.JobId)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate diagrams: %v", err)
+		return nil, fmt.Errorf("failed to get job: %v", err)
 	}
 
-	// 다이어그램 결과 변환
-	diagrams := make([]*implementation.Diagram, 0, len(diagramResp.Diagrams))
-	for _, pbDiagram := range diagramResp.Diagrams {
-		diagrams = append(diagrams, &implementation.Diagram{
-			Diagram: pbDiagram.Diagram,
-			Type:    pbDiagram.Type,
-		})
+	resp := &implementation.GetImplementationResultResponse{
+		JobId:  job.ID,
+		Status: string(job.Status),
+		Error:  job.Error,
+	}
+	if job.CompletedAt != nil {
+		resp.CompletedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+
+	// 결과가 없는 Job은 상태만 반환 (승인을 기다리는 Job은 검토할 중간 결과 반환)
+	if job.Result == nil {
+		return resp, nil
+	}
+
+	resp.Files = convertFilesToPB(job.Result.Files)
+	resp.Diagrams = convertDiagramsToPB(job.Result.Diagrams)
+	return resp, nil
+}
+
+// CancelImplementation 대기 중이거나 실행 중이거나 승인을 기다리는 Job 취소 (진행 중인 LLM/서비스 호출도 중단)
+func (h *ImplementationHandler) CancelImplementation(ctx context.Context, req *implementation.CancelImplementationRequest) (*implementation.CancelImplementationResponse, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = "cancelled by user"
 	}
 
-	// 4. Analyzer 서비스로 코드 분석
-	analyzerResp, err := h.analyzerClient.AnalyzeCodeSegments(ctx, &analyzer.AnalyzeCodeSegmentsRequest{
-		Code:     code,
-		Language: planResp.Language,
-	})
+	err := h.jobStore.CancelJob(ctx, req.JobId, reason)
+	if errors.Is(err, queue.ErrJobFinished) {
+		job, getErr := h.jobStore.GetJob(ctx, req.JobId)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get job: %v", getErr)
+		}
+		return nil, fmt.Errorf("job %s is already %s", job.ID, job.Status)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to analyze code: %v", err)
+		return nil, fmt.Errorf("failed to cancel job: %v", err)
 	}
 
-	// 분석 결과 변환
-	explainedSegments := make([]*implementation.ExplainedSegment, 0, len(analyzerResp.CodeSegments))
-	for _, pbSegment := range analyzerResp.CodeSegments {
-		explainedSegments = append(explainedSegments, &implementation.ExplainedSegment{
-			StartLine:   pbSegment.StartLine,
-			EndLine:     pbSegment.EndLine,
-			Explanation: pbSegment.Explanation,
-		})
+	h.cancelRunning(req.JobId)
+	h.emitEvent(ctx, &queue.JobEvent{JobID: req.JobId, Type: queue.JobEventCancelled, Message: reason})
+
+	return &implementation.CancelImplementationResponse{
+		JobId:   req.JobId,
+		Status:  string(queue.JobStatusCancelled),
+		Message: "Implementation job cancelled",
+	}, nil
+}
+
+// RetryPlans 실패하거나 건너뛰었거나 계획과 시그니처가 맞지 않는 계획만 다시 구현하여 기존 결과에 합침
+// (PlanIds가 비어 있으면 해당하는 모든 계획)
+func (h *ImplementationHandler) RetryPlans(ctx context.Context, req *implementation.RetryPlansRequest) (*implementation.RetryPlansResponse, error) {
+	job, err := h.jobStore.GetJob(ctx, req.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+	if !job.Status.IsRetryable() || job.Result == nil {
+		return nil, fmt.Errorf("job %s has no plans to retry (status: %s)", job.ID, job.Status)
 	}
 
-	// 5. 최종 결과 반환
-	return &implementation.ImplementPlanResponse{
-		Code:              code,
-		Diagrams:          diagrams,
-		ExplainedSegments: explainedSegments,
-		Status:            "completed",
-		Message:           "Implementation completed successfully",
+	filesByPlan := make(map[int64]queue.GeneratedFile, len(job.Result.Files))
+	var planIDs []int64
+	for _, file := range job.Result.Files {
+		filesByPlan[file.PlanID] = file
+		if len(req.PlanIds) == 0 && file.NeedsRegeneration() {
+			planIDs = append(planIDs, file.PlanID)
+		}
+	}
+	for _, planID := range req.PlanIds {
+		file, ok := filesByPlan[planID]
+		if !ok {
+			return nil, fmt.Errorf("plan %d is not part of job %s", planID, job.ID)
+		}
+		if !file.NeedsRegeneration() {
+			return nil, fmt.Errorf("plan %d is already implemented and conforms to the plan", planID)
+		}
+		planIDs = append(planIDs, planID)
+	}
+	if len(planIDs) == 0 {
+		return nil, fmt.Errorf("job %s has no failed or non-conforming plans to retry", job.ID)
+	}
+
+	err = h.jobStore.RetryJob(ctx, job.ID, planIDs)
+	if errors.Is(err, queue.ErrJobNotRetryable) {
+		return nil, fmt.Errorf("job %s is no longer retryable", job.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job: %v", err)
+	}
+
+	return &implementation.RetryPlansResponse{
+		JobId:   job.ID,
+		Status:  string(queue.JobStatusPending),
+		PlanIds: planIDs,
+		Message: fmt.Sprintf("Retrying %d plans", len(planIDs)),
 	}, nil
 }
+
+// ApproveJob 승인 게이트에서 멈춘 Job을 승인하여 저장된 중간 결과로 다음 단계부터 재개
+func (h *ImplementationHandler) ApproveJob(ctx context.Context, req *implementation.ApproveJobRequest) (*implementation.ApproveJobResponse, error) {
+	job, err := h.jobStore.GetJob(ctx, req.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+	if job.Status != queue.JobStatusAwaitingApproval {
+		return nil, fmt.Errorf("job %s is not awaiting approval (status: %s)", job.ID, job.Status)
+	}
+
+	err = h.jobStore.ApproveJob(ctx, job.ID)
+	if errors.Is(err, queue.ErrJobNotAwaitingApproval) {
+		return nil, fmt.Errorf("job %s is no longer awaiting approval", job.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve job: %v", err)
+	}
+
+	message := fmt.Sprintf("Approved at %s gate", job.PendingGate)
+	h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventApproved, Message: message})
+
+	return &implementation.ApproveJobResponse{
+		JobId:   job.ID,
+		Status:  string(queue.JobStatusPending),
+		Gate:    job.PendingGate,
+		Message: message,
+	}, nil
+}
+
+// RejectJob 승인 게이트에서 멈춘 Job을 거절하여 계획을 리뷰어 피드백과 함께 다시 구현
+// (PlanIds가 비어 있으면 모든 계획). 이미 승인된 게이트도 다시 승인받아야 함
+func (h *ImplementationHandler) RejectJob(ctx context.Context, req *implementation.RejectJobRequest) (*implementation.RejectJobResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason is required to reject a job")
+	}
+
+	job, err := h.jobStore.GetJob(ctx, req.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %v", err)
+	}
+	if job.Status != queue.JobStatusAwaitingApproval || job.Result == nil {
+		return nil, fmt.Errorf("job %s is not awaiting approval (status: %s)", job.ID, job.Status)
+	}
+
+	planIDs := req.PlanIds
+	if len(planIDs) == 0 {
+		for _, file := range job.Result.Files {
+			planIDs = append(planIDs, file.PlanID)
+		}
+	}
+	inResult := make(map[int64]bool, len(job.Result.Files))
+	for _, file := range job.Result.Files {
+		inResult[file.PlanID] = true
+	}
+	for _, planID := range planIDs {
+		if !inResult[planID] {
+			return nil, fmt.Errorf("plan %d is not part of job %s", planID, job.ID)
+		}
+	}
+
+	err = h.jobStore.RejectJob(ctx, job.ID, planIDs, reason)
+	if errors.Is(err, queue.ErrJobNotAwaitingApproval) {
+		return nil, fmt.Errorf("job %s is no longer awaiting approval", job.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject job: %v", err)
+	}
+
+	h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventRejected, Message: reason})
+
+	return &implementation.RejectJobResponse{
+		JobId:   job.ID,
+		Status:  string(queue.JobStatusPending),
+		Gate:    job.PendingGate,
+		PlanIds: planIDs,
+		Message: fmt.Sprintf("Regenerating %d plans with reviewer feedback", len(planIDs)),
+	}, nil
+}
+
+// DeleteJobsByDevPlan 개발 계획에 연결된 Job(결과와 다이어그램 포함)과 구현 실행 기록 삭제.
+// 실행 중인 Job은 먼저 취소하고 워커가 멈출 때까지 기다려 삭제 후 구현 실행 기록이 다시 쓰이지 않게 함
+func (h *ImplementationHandler) DeleteJobsByDevPlan(ctx context.Context, req *implementation.DeleteJobsByDevPlanRequest) (*implementation.DeleteJobsByDevPlanResponse, error) {
+	leased, err := h.jobStore.CancelJobsByDevPlanIDs(ctx, req.DevPlanIds, "dev plan deleted")
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel jobs: %v", err)
+	}
+	for _, jobID := range leased {
+		h.cancelRunning(jobID)
+	}
+	if err := h.waitForWorkers(ctx, leased); err != nil {
+		return nil, fmt.Errorf("failed to wait for cancelled jobs: %v", err)
+	}
+
+	deleted, err := h.jobStore.DeleteJobsByDevPlanIDs(ctx, req.DevPlanIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete jobs: %v", err)
+	}
+	deletedImplementations, err := h.implementationStore.DeleteImplementationsByDevPlanIDs(ctx, req.DevPlanIds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete implementations: %v", err)
+	}
+
+	return &implementation.DeleteJobsByDevPlanResponse{
+		DeletedJobs:            deleted,
+		DeletedImplementations: deletedImplementations,
+	}, nil
+}
+
+// convertFilesToPB 저장된 계획별 생성 파일을 pb로 변환
+func convertFilesToPB(files []queue.GeneratedFile) []*implementation.GeneratedFile {
+	pbFiles := make([]*implementation.GeneratedFile, 0, len(files))
+	for _, file := range files {
+		explainedSegments := make([]*implementation.ExplainedSegment, 0, len(file.ExplainedSegments))
+		for _, segment := range file.ExplainedSegments {
+			explainedSegments = append(explainedSegments, &implementation.ExplainedSegment{
+				StartLine:   segment.StartLine,
+				EndLine:     segment.EndLine,
+				Explanation: segment.Explanation,
+			})
+		}
+		tests := make([]*implementation.TestResult, 0, len(file.Tests))
+		for _, test := range file.Tests {
+			tests = append(tests, &implementation.TestResult{
+				Name:       test.Name,
+				Annotation: test.Annotation,
+				Passed:     test.Passed,
+				Output:     test.Output,
+			})
+		}
+		diagnostics := make([]*implementation.Diagnostic, 0, len(file.Diagnostics))
+		for _, diagnostic := range file.Diagnostics {
+			diagnostics = append(diagnostics, &implementation.Diagnostic{
+				Line:    diagnostic.Line,
+				Column:  diagnostic.Column,
+				Message: diagnostic.Message,
+			})
+		}
+		pbFiles = append(pbFiles, &implementation.GeneratedFile{
+			Path:              file.Path,
+			Language:          file.Language,
+			PlanId:            file.PlanID,
+			Status:            string(file.Status),
+			Error:             file.Error,
+			Attempts:          file.Attempts,
+			ContentHash:       file.ContentHash,
+			Cached:            file.Cached,
+			Code:              file.Code,
+			ExplainedSegments: explainedSegments,
+			Diagnostics:       diagnostics,
+			Tests:             tests,
+			Conformance:       convertConformanceToPB(file.Conformance),
+		})
+	}
+	return pbFiles
+}
+
+// convertDiagramsToPB 저장된 다이어그램을 pb로 변환
+func convertDiagramsToPB(diagrams []queue.Diagram) []*implementation.Diagram {
+	pbDiagrams := make([]*implementation.Diagram, 0, len(diagrams))
+	for _, d := range diagrams {
+		pbDiagrams = append(pbDiagrams, &implementation.Diagram{
+			Diagram: d.Diagram,
+			Type:    d.Type,
+		})
+	}
+	return pbDiagrams
+}
+
+// convertConformanceToPB 시그니처 검사 결과를 pb로 변환 (검사하지 않은 파일은 nil)
+func convertConformanceToPB(report *queue.ConformanceReport) *implementation.ConformanceReport {
+	if report == nil {
+		return nil
+	}
+	issues := make([]*implementation.ConformanceIssue, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		issues = append(issues, &implementation.ConformanceIssue{
+			Kind:     issue.Kind,
+			Symbol:   issue.Symbol,
+			Expected: issue.Expected,
+			Actual:   issue.Actual,
+			Line:     issue.Line,
+		})
+	}
+	return &implementation.ConformanceReport{
+		Conforms: report.Conforms,
+		Issues:   issues,
+	}
+}
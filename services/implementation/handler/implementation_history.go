@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"codev42-implementation/proto/implementation"
+	"codev42-implementation/queue"
+	"codev42-implementation/service"
+)
+
+// 실행 기록 비교 결과의 변경 종류
+const (
+	changeAdded    = "added"
+	changeRemoved  = "removed"
+	changeModified = "modified"
+)
+
+// recordImplementation 완료된 실행의 결과를 개발 계획의 실행 기록으로 저장 (실패해도 Job 결과에는 영향 없음)
+func (h *ImplementationHandler) recordImplementation(ctx context.Context, job *queue.Job, status queue.JobStatus, result *queue.JobResult) {
+	language := ""
+	if len(result.Files) > 0 {
+		language = result.Files[0].Language
+	}
+
+	err := h.implementationStore.CreateImplementation(ctx, &queue.Implementation{
+		DevPlanID: job.DevPlanID,
+		JobID:     job.ID,
+		TenantID:  job.TenantID,
+		Status:    status,
+		Language:  language,
+		Provider:  h.Config.LLMProvider,
+		Model:     h.Config.LLMChatModel,
+		FileCount: int32(len(result.Files)),
+		Result:    result,
+	})
+	if err != nil {
+		log.Printf("Failed to record implementation of job %s: %v", job.ID, err)
+	}
+}
+
+func convertImplementationToPB(record *queue.Implementation) *implementation.ImplementationElement {
+	return &implementation.ImplementationElement{
+		Id:        record.ID,
+		JobId:     record.JobID,
+		Status:    string(record.Status),
+		Language:  record.Language,
+		Provider:  record.Provider,
+		Model:     record.Model,
+		FileCount: record.FileCount,
+		CreatedAt: record.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ListImplementations 개발 계획의 구현 실행 기록 목록 조회
+func (h *ImplementationHandler) ListImplementations(ctx context.Context, req *implementation.ListImplementationsRequest) (*implementation.ListImplementationsResponse, error) {
+	records, err := h.implementationStore.ListImplementations(ctx, req.DevPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list implementations: %v", err)
+	}
+
+	implementations := make([]*implementation.ImplementationElement, len(records))
+	for i := range records {
+		implementations[i] = convertImplementationToPB(&records[i])
+	}
+
+	return &implementation.ListImplementationsResponse{
+		Implementations: implementations,
+	}, nil
+}
+
+// GetImplementation 구현 실행 기록 하나의 코드, 다이어그램, 코드 설명 조회
+func (h *ImplementationHandler) GetImplementation(ctx context.Context, req *implementation.GetImplementationRequest) (*implementation.GetImplementationResponse, error) {
+	record, err := h.implementationStore.GetImplementation(ctx, req.ImplementationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get implementation: %v", err)
+	}
+
+	resp := &implementation.GetImplementationResponse{
+		Implementation: convertImplementationToPB(record),
+		DevPlanId:      record.DevPlanID,
+	}
+	if record.Result != nil {
+		resp.Files = convertFilesToPB(record.Result.Files)
+		resp.Diagrams = convertDiagramsToPB(record.Result.Diagrams)
+	}
+	return resp, nil
+}
+
+// DiffImplementations 두 구현 실행 기록의 파일(경로 기준)과 다이어그램(종류 기준) 비교
+func (h *ImplementationHandler) DiffImplementations(ctx context.Context, req *implementation.DiffImplementationsRequest) (*implementation.DiffImplementationsResponse, error) {
+	from, err := h.implementationStore.GetImplementation(ctx, req.FromImplementationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get implementation: %v", err)
+	}
+	to, err := h.implementationStore.GetImplementation(ctx, req.ToImplementationId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get implementation: %v", err)
+	}
+	if from.DevPlanID != to.DevPlanID {
+		return nil, fmt.Errorf("implementations %d and %d belong to different dev plans", from.ID, to.ID)
+	}
+
+	var fromResult, toResult queue.JobResult
+	if from.Result != nil {
+		fromResult = *from.Result
+	}
+	if to.Result != nil {
+		toResult = *to.Result
+	}
+
+	return &implementation.DiffImplementationsResponse{
+		FromImplementationId: from.ID,
+		ToImplementationId:   to.ID,
+		Files:                diffFiles(fromResult.Files, toResult.Files),
+		Diagrams:             diffDiagrams(fromResult.Diagrams, toResult.Diagrams),
+	}, nil
+}
+
+// diffFiles 경로가 같은 파일끼리 비교하여 바뀐 파일만 경로 순으로 반환
+func diffFiles(before []queue.GeneratedFile, after []queue.GeneratedFile) []*implementation.FileDiff {
+	beforeByPath := make(map[string]queue.GeneratedFile, len(before))
+	for _, file := range before {
+		beforeByPath[file.Path] = file
+	}
+	afterByPath := make(map[string]queue.GeneratedFile, len(after))
+	for _, file := range after {
+		afterByPath[file.Path] = file
+	}
+
+	var diffs []*implementation.FileDiff
+	for _, path := range sortedKeys(beforeByPath, afterByPath) {
+		oldFile, inBefore := beforeByPath[path]
+		newFile, inAfter := afterByPath[path]
+
+		diff := &implementation.FileDiff{
+			Path:         path,
+			StatusBefore: string(oldFile.Status),
+			StatusAfter:  string(newFile.Status),
+		}
+		switch {
+		case !inBefore:
+			diff.ChangeType = changeAdded
+		case !inAfter:
+			diff.ChangeType = changeRemoved
+		case oldFile.Code == newFile.Code && oldFile.Status == newFile.Status:
+			continue
+		default:
+			diff.ChangeType = changeModified
+		}
+
+		unified, added, removed := service.DiffLines(path, oldFile.Code, newFile.Code)
+		diff.UnifiedDiff = unified
+		diff.AddedLines = int32(added)
+		diff.RemovedLines = int32(removed)
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// diffDiagrams 종류가 같은 다이어그램끼리 비교하여 바뀐 다이어그램만 반환
+func diffDiagrams(before []queue.Diagram, after []queue.Diagram) []*implementation.DiagramDiff {
+	beforeByType := make(map[string]string, len(before))
+	for _, d := range before {
+		beforeByType[d.Type] = d.Diagram
+	}
+	afterByType := make(map[string]string, len(after))
+	for _, d := range after {
+		afterByType[d.Type] = d.Diagram
+	}
+
+	var diffs []*implementation.DiagramDiff
+	for _, diagramType := range sortedKeys(beforeByType, afterByType) {
+		oldDiagram, inBefore := beforeByType[diagramType]
+		newDiagram, inAfter := afterByType[diagramType]
+
+		diff := &implementation.DiagramDiff{
+			Type:   diagramType,
+			Before: oldDiagram,
+			After:  newDiagram,
+		}
+		switch {
+		case !inBefore:
+			diff.ChangeType = changeAdded
+		case !inAfter:
+			diff.ChangeType = changeRemoved
+		case oldDiagram == newDiagram:
+			continue
+		default:
+			diff.ChangeType = changeModified
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+// sortedKeys 두 map의 키를 합쳐 정렬
+func sortedKeys[V any](a map[string]V, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
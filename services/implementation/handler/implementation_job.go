@@ -0,0 +1,647 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"codev42-implementation/proto/analyzer"
+	"codev42-implementation/proto/plan"
+	"codev42-implementation/queue"
+	"codev42-implementation/service"
+	"codev42-llm/client"
+)
+
+// 파이프라인 단계 (진행 이벤트의 Stage)
+const (
+	stageFetchPlan        = "fetch_plan"
+	stageGenerateCode     = "generate_code"
+	stageRunTests         = "run_tests"
+	stageGenerateDiagrams = "generate_diagrams"
+	stageAnalyzeCode      = "analyze_code"
+)
+
+// workerStopPollInterval 취소한 Job의 워커가 멈췄는지 확인하는 주기
+const workerStopPollInterval = 200 * time.Millisecond
+
+// errAwaitingApproval 승인 게이트에서 Job이 멈췄음 (중간 결과는 저장되어 있고 ApproveJob/RejectJob으로 재개)
+var errAwaitingApproval = errors.New("job is awaiting approval")
+
+// ProcessJob 워커가 lease를 획득한 Job의 구현 파이프라인을 실행하고 결과를 기록
+func (h *ImplementationHandler) ProcessJob(ctx context.Context, job *queue.Job) {
+	// 요청한 테넌트의 자격 증명으로 LLM과 하위 서비스를 호출
+	ctx = client.WithTenant(ctx, job.TenantID)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	h.trackRunning(job.ID, cancel)
+	defer h.untrackRunning(job.ID)
+
+	result, err := h.runPipeline(ctx, job)
+	if ctx.Err() != nil {
+		// 취소된 Job이 아니라면 lease를 잃었거나 서버가 종료 중이므로 다른 레플리카가 이어서 처리
+		if h.isCancelled(context.WithoutCancel(ctx), job.ID) {
+			log.Printf("Job %s cancelled", job.ID)
+			return
+		}
+		log.Printf("Job %s interrupted: %v", job.ID, ctx.Err())
+		return
+	}
+	if errors.Is(err, errAwaitingApproval) {
+		log.Printf("Job %s awaiting approval", job.ID)
+		return
+	}
+	if err != nil {
+		log.Printf("Job %s failed: %v", job.ID, err)
+		// 모든 계획이 실패한 경우에도 계획별 결과를 남겨 RetryPlans로 다시 시도할 수 있게 함
+		if result != nil {
+			if setErr := h.jobStore.SetJobResult(ctx, job.ID, job.LeaseOwner, result); setErr != nil {
+				log.Printf("Failed to store result for job %s: %v", job.ID, setErr)
+			}
+		}
+		if setErr := h.jobStore.SetJobError(ctx, job.ID, job.LeaseOwner, err); setErr != nil {
+			log.Printf("Failed to record error for job %s: %v", job.ID, setErr)
+			return
+		}
+		h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventFailed, Message: err.Error()})
+		return
+	}
+
+	if err := h.jobStore.SetJobResult(ctx, job.ID, job.LeaseOwner, result); err != nil {
+		log.Printf("Failed to store result for job %s: %v", job.ID, err)
+		return
+	}
+	failed := 0
+	for _, file := range result.Files {
+		if file.Status != queue.PlanStatusCompleted {
+			failed++
+		}
+	}
+	if failed > 0 {
+		step := fmt.Sprintf("Partially completed (%d of %d plans not implemented)", failed, len(result.Files))
+		// 취소되었거나 lease를 잃은 Job은 구현 실행 기록을 남기지 않음
+		if h.updateProgress(ctx, job, queue.JobStatusPartiallyCompleted, 100, step) != nil {
+			return
+		}
+		h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventPartiallyCompleted, Progress: 100, Message: step})
+		h.recordImplementation(ctx, job, queue.JobStatusPartiallyCompleted, result)
+		return
+	}
+	if h.updateProgress(ctx, job, queue.JobStatusCompleted, 100, "Completed") != nil {
+		return
+	}
+	h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventCompleted, Progress: 100, Message: "Completed"})
+	h.recordImplementation(ctx, job, queue.JobStatusCompleted, result)
+}
+
+// trackRunning 이 레플리카에서 실행 중인 Job의 취소 함수 등록
+func (h *ImplementationHandler) trackRunning(jobID string, cancel context.CancelFunc) {
+	h.runningMu.Lock()
+	defer h.runningMu.Unlock()
+	h.running[jobID] = cancel
+}
+
+func (h *ImplementationHandler) untrackRunning(jobID string) {
+	h.runningMu.Lock()
+	defer h.runningMu.Unlock()
+	delete(h.running, jobID)
+}
+
+// cancelRunning Job이 이 레플리카에서 실행 중이면 즉시 중단
+func (h *ImplementationHandler) cancelRunning(jobID string) {
+	h.runningMu.Lock()
+	defer h.runningMu.Unlock()
+	if cancel, ok := h.running[jobID]; ok {
+		cancel()
+	}
+}
+
+// waitForWorkers Job을 처리하던 워커가 멈출 때까지 대기. 다른 레플리카의 워커는 다음 lease 갱신에서 멈추고,
+// 응답이 없는 워커는 lease가 만료될 때까지 기다림 (취소된 Job에는 결과와 구현 실행 기록을 남길 수 없음)
+func (h *ImplementationHandler) waitForWorkers(ctx context.Context, jobIDs []string) error {
+	for _, jobID := range jobIDs {
+		for {
+			job, err := h.jobStore.GetJob(ctx, jobID)
+			if err != nil {
+				return err
+			}
+			if !job.IsLeased(time.Now()) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(workerStopPollInterval):
+			}
+		}
+	}
+	return nil
+}
+
+func (h *ImplementationHandler) isCancelled(ctx context.Context, jobID string) bool {
+	job, err := h.jobStore.GetJob(ctx, jobID)
+	return err == nil && job.Status == queue.JobStatusCancelled
+}
+
+// runStage 단계 제한 시간(0이면 제한 없음)을 적용하여 fn 실행 (제한 시간 초과는 Job 실패로 처리)
+func (h *ImplementationHandler) runStage(ctx context.Context, stage string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fn(stageCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("stage %s timed out after %s", stage, timeout)
+	}
+	return err
+}
+
+// emitEvent 진행 이벤트 기록 (실패해도 파이프라인은 계속 진행)
+func (h *ImplementationHandler) emitEvent(ctx context.Context, event *queue.JobEvent) {
+	if err := h.jobStore.AppendJobEvent(ctx, event); err != nil {
+		log.Printf("Failed to record %s event for job %s: %v", event.Type, event.JobID, err)
+	}
+}
+
+// startStage 단계 시작을 상태와 이벤트로 기록
+func (h *ImplementationHandler) startStage(ctx context.Context, job *queue.Job, stage string, progress int32, step string) {
+	h.updateProgress(ctx, job, queue.JobStatusProcessing, progress, step)
+	h.emitEvent(ctx, &queue.JobEvent{JobID: job.ID, Type: queue.JobEventStageStarted, Stage: stage, Progress: progress, Message: step})
+}
+
+// finishStage 단계 종료 이벤트 기록
+func (h *ImplementationHandler) finishStage(ctx context.Context, jobID string, stage string, progress int32) {
+	h.emitEvent(ctx, &queue.JobEvent{JobID: jobID, Type: queue.JobEventStageFinished, Stage: stage, Progress: progress})
+}
+
+// warn 작업은 계속되지만 결과를 확인해야 하는 상황을 이벤트로 기록
+func (h *ImplementationHandler) warn(ctx context.Context, jobID string, stage string, format string, args ...interface{}) {
+	h.emitEvent(ctx, &queue.JobEvent{JobID: jobID, Type: queue.JobEventWarning, Stage: stage, Message: fmt.Sprintf(format, args...)})
+}
+
+// updateProgress Job 진행 상황 갱신 (실패해도 파이프라인은 계속 진행, 완료 처리에서만 에러를 확인)
+func (h *ImplementationHandler) updateProgress(ctx context.Context, job *queue.Job, status queue.JobStatus, progress int32, step string) error {
+	err := h.jobStore.UpdateJob(ctx, job.ID, job.LeaseOwner, status, progress, step)
+	if err != nil {
+		log.Printf("Failed to update job %s: %v", job.ID, err)
+	}
+	return err
+}
+
+// pipelineRun 파이프라인을 한 번 실행하는 동안 단계들이 공유하는 상태
+type pipelineRun struct {
+	job      *queue.Job
+	plan     *plan.GetPlanByIdResponse
+	retrying bool // job.RetryPlanIDs만 다시 구현하여 저장된 결과에 합침
+
+	// 코드 단계의 결과 (계획마다 파일 하나, 구현 순서). collectFiles에서 files로 변환
+	generated     []service.GeneratedFile
+	hashes        map[int64]string
+	cachedFiles   map[int64]queue.GeneratedFile
+	previousFiles map[int64]queue.GeneratedFile
+
+	// 코드 단계 이후 단계의 입력과 결과
+	collected   bool
+	files       []queue.GeneratedFile
+	diagrams    []queue.Diagram
+	regenerated int // 이번 실행에서 새로 구현된 계획 수
+}
+
+// runPipeline 계획을 조회한 뒤 프로젝트의 파이프라인 정의에 나열된 단계를 순서대로 실행
+// (기본: 코드 생성 → 단위 테스트 → 다이어그램 생성 → 코드 분석).
+// 일부 계획이 실패해도 결과를 반환하며, 구현된 계획이 하나도 없으면 결과와 에러를 함께 반환.
+// 재시도(RetryPlans, RejectJob)인 경우 job.RetryPlanIDs만 다시 구현하여 저장된 결과에 합침.
+// 승인 게이트에서 멈추면 errAwaitingApproval을 반환하고, 승인된 Job은 게이트 다음 단계부터 이어서 진행
+func (h *ImplementationHandler) runPipeline(ctx context.Context, job *queue.Job) (*queue.JobResult, error) {
+	jobID := job.ID
+	run := &pipelineRun{
+		job:      job,
+		retrying: job.Result != nil && len(job.RetryPlanIDs) > 0,
+	}
+
+	// 1. Plan 서비스에서 개발 계획을 조회하고 프로젝트의 파이프라인 정의를 읽음
+	h.startStage(ctx, job, stageFetchPlan, 10, "Fetching plan")
+	err := h.runStage(ctx, stageFetchPlan, h.stageTimeouts[stageFetchPlan], func(ctx context.Context) error {
+		var err error
+		run.plan, err = h.planClient.GetPlanById(ctx, &plan.GetPlanByIdRequest{
+			DevPlanId: job.DevPlanID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch plan: %v", err)
+	}
+	pipeline, err := h.loadPipeline(ctx, run.plan.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	h.finishStage(ctx, jobID, stageFetchPlan, 20)
+
+	// 승인된 Job은 저장된 중간 결과로 게이트 다음 단계부터 진행
+	start := 0
+	if job.Result != nil && !run.retrying {
+		switch {
+		case job.HasPassedGate(queue.ApprovalGateResult):
+			start = len(pipeline.stages)
+		case job.HasPassedGate(queue.ApprovalGateCode):
+			start = pipeline.codeStages
+		}
+		if start > 0 {
+			run.files = job.Result.Files
+			run.diagrams = job.Result.Diagrams
+			run.collected = true
+		}
+	}
+
+	for index := start; index <= len(pipeline.stages); index++ {
+		// 코드를 만드는 단계가 모두 끝나면 파일을 정리하고, 설정된 경우 다음 단계 전에 생성된 코드의 검토를 기다림
+		if index == pipeline.codeStages {
+			if !run.collected {
+				if err := h.collectFiles(ctx, run); err != nil {
+					if len(run.files) == 0 {
+						return nil, err
+					}
+					return &queue.JobResult{Files: run.files}, err
+				}
+				// 다시 구현된 파일이 없으면 기존 다이어그램을 유지 (코드 검토에서 거절되어 아직 다이어그램이 없으면 계속 진행)
+				if run.retrying && run.regenerated == 0 && len(job.Result.Diagrams) > 0 {
+					return &queue.JobResult{Files: run.files, Diagrams: job.Result.Diagrams}, nil
+				}
+			}
+			if err := h.awaitApproval(ctx, job, queue.ApprovalGateCode, &queue.JobResult{Files: run.files}); err != nil {
+				return nil, err
+			}
+		}
+		if index == len(pipeline.stages) {
+			break
+		}
+
+		startProgress := 20 + int32(75*index/len(pipeline.stages))
+		endProgress := 20 + int32(75*(index+1)/len(pipeline.stages))
+		if err := h.runPipelineStage(ctx, run, pipeline.stages[index], startProgress, endProgress); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &queue.JobResult{
+		Files:    run.files,
+		Diagrams: run.diagrams,
+	}
+
+	// 검토 전인 코드는 다른 Job에서 재사용하지 않도록 승인된 뒤에 캐시
+	if err := h.awaitApproval(ctx, job, queue.ApprovalGateResult, result); err != nil {
+		return nil, err
+	}
+	h.cacheFiles(ctx, job, run.files, generatedFiles(run.files))
+
+	return result, nil
+}
+
+// runPipelineStage 단계 하나를 진행 이벤트와 제한 시간을 적용하여 실행. optional 단계는 실패해도 경고만 남김
+func (h *ImplementationHandler) runPipelineStage(ctx context.Context, run *pipelineRun, stage configuredStage, startProgress, endProgress int32) error {
+	if stage.runner.skip(h, run) {
+		return nil
+	}
+
+	jobID := run.job.ID
+	timeout := h.stageTimeouts[stage.name]
+	if stage.timeout > 0 {
+		timeout = stage.timeout
+	}
+	h.startStage(ctx, run.job, stage.name, startProgress, stage.step)
+	err := h.runStage(ctx, stage.name, timeout, func(ctx context.Context) error {
+		return stage.runner.run(ctx, h, run)
+	})
+	if err != nil {
+		if !stage.optional || ctx.Err() != nil {
+			return err
+		}
+		h.warn(ctx, jobID, stage.name, "optional stage %s failed: %v", stage.name, err)
+	}
+	h.finishStage(ctx, jobID, stage.name, endProgress)
+	return nil
+}
+
+// collectFiles 코드 단계의 결과를 Job 결과 파일로 변환 (캐시와 기존 결과에서 재사용한 파일 포함)
+func (h *ImplementationHandler) collectFiles(ctx context.Context, run *pipelineRun) error {
+	jobID := run.job.ID
+	files := make([]queue.GeneratedFile, 0, len(run.generated))
+	implemented, regenerated := 0, 0
+	for index, file := range run.generated {
+		if cached, ok := run.cachedFiles[file.PlanID]; ok && file.Reused() {
+			cached.Path = file.Path
+			cached.PlanID = file.PlanID
+			cached.Status = queue.PlanStatusCompleted
+			cached.Error = ""
+			cached.Attempts = 0
+			cached.ContentHash = run.hashes[file.PlanID]
+			cached.Cached = true
+			files = append(files, cached)
+			implemented++
+			h.emitEvent(ctx, &queue.JobEvent{
+				JobID:     jobID,
+				Type:      queue.JobEventPlanGenerated,
+				Stage:     stageGenerateCode,
+				Message:   fmt.Sprintf("Plan %d reused from cache", index+1),
+				PlanIndex: int32(index),
+				PlanID:    file.PlanID,
+				Path:      file.Path,
+			})
+			continue
+		}
+		if file.Reused() {
+			files = append(files, run.previousFiles[file.PlanID])
+			if file.Status == service.PlanStatusCompleted {
+				implemented++
+			}
+			continue
+		}
+		if file.Status == service.PlanStatusCompleted && strings.TrimSpace(file.Code) == "" {
+			file.Status = service.PlanStatusFailed
+			file.Error = "generated code is empty"
+			file.Code = ""
+		}
+		if file.Status == service.PlanStatusCompleted {
+			implemented++
+			regenerated++
+		}
+		tests := make([]queue.TestResult, 0, len(file.Tests))
+		for _, test := range file.Tests {
+			tests = append(tests, queue.TestResult{
+				Name:       test.Name,
+				Annotation: test.Annotation,
+				Passed:     test.Passed,
+				Output:     test.Output,
+			})
+		}
+		diagnostics := make([]queue.Diagnostic, 0, len(file.Diagnostics))
+		for _, diagnostic := range file.Diagnostics {
+			diagnostics = append(diagnostics, queue.Diagnostic{
+				Line:    int32(diagnostic.Line),
+				Column:  int32(diagnostic.Column),
+				Message: diagnostic.Message,
+			})
+		}
+		files = append(files, queue.GeneratedFile{
+			Path:        file.Path,
+			Language:    file.Language,
+			PlanID:      file.PlanID,
+			Status:      queue.PlanStatus(file.Status),
+			Error:       file.Error,
+			Attempts:    int32(file.Attempts),
+			ContentHash: run.hashes[file.PlanID],
+			Code:        file.Code,
+			Diagnostics: diagnostics,
+			Tests:       tests,
+			Conformance: convertConformance(file.Conformance),
+		})
+	}
+
+	run.files = files
+	run.regenerated = regenerated
+	run.collected = true
+	if len(files) == 0 {
+		return fmt.Errorf("generated code is empty")
+	}
+	if implemented == 0 {
+		return fmt.Errorf("no plan was implemented: %s", planErrors(files))
+	}
+	return nil
+}
+
+// awaitApproval Job에 설정된 승인 게이트이면 중간 결과를 저장하고 awaiting_approval로 전환하여 errAwaitingApproval 반환
+func (h *ImplementationHandler) awaitApproval(ctx context.Context, job *queue.Job, gate string, result *queue.JobResult) error {
+	if !job.RequiresApproval(gate) {
+		return nil
+	}
+	if err := h.jobStore.AwaitApproval(ctx, job.ID, job.LeaseOwner, gate, result); err != nil {
+		return fmt.Errorf("failed to stop at approval gate %s: %v", gate, err)
+	}
+	h.emitEvent(ctx, &queue.JobEvent{
+		JobID:   job.ID,
+		Type:    queue.JobEventAwaitingApproval,
+		Message: fmt.Sprintf("Awaiting approval (%s)", gate),
+	})
+	return errAwaitingApproval
+}
+
+// unanalyzedFiles 구현되었지만 아직 코드 분석 결과가 없는 파일 (코드 캐시에서 재사용한 파일 제외)
+func unanalyzedFiles(files []queue.GeneratedFile) []int {
+	var targets []int
+	for index, file := range files {
+		if file.Status == queue.PlanStatusCompleted && !file.Cached && len(file.ExplainedSegments) == 0 {
+			targets = append(targets, index)
+		}
+	}
+	return targets
+}
+
+// generatedFiles 코드 캐시에서 재사용하지 않고 구현된 파일
+func generatedFiles(files []queue.GeneratedFile) []int {
+	var targets []int
+	for index, file := range files {
+		if file.Status == queue.PlanStatusCompleted && !file.Cached {
+			targets = append(targets, index)
+		}
+	}
+	return targets
+}
+
+// previousResult 저장된 결과를 재사용할 파일 목록으로 변환. retryPlanIDs의 계획은 다시 구현하도록 pending으로 표시
+func previousResult(result *queue.JobResult, retryPlanIDs []int64) ([]service.GeneratedFile, map[int64]queue.GeneratedFile) {
+	retry := make(map[int64]bool, len(retryPlanIDs))
+	for _, id := range retryPlanIDs {
+		retry[id] = true
+	}
+
+	previous := make([]service.GeneratedFile, 0, len(result.Files))
+	byPlan := make(map[int64]queue.GeneratedFile, len(result.Files))
+	for _, file := range result.Files {
+		byPlan[file.PlanID] = file
+		status := service.PlanStatus(file.Status)
+		if retry[file.PlanID] {
+			status = service.PlanStatusPending
+		}
+		previous = append(previous, service.GeneratedFile{
+			Path:     file.Path,
+			Language: file.Language,
+			PlanID:   file.PlanID,
+			Code:     file.Code,
+			Status:   status,
+			Error:    file.Error,
+			Attempts: int(file.Attempts),
+		})
+	}
+	return previous, byPlan
+}
+
+// convertConformance service의 시그니처 검사 결과를 저장용으로 변환
+func convertConformance(report *service.ConformanceReport) *queue.ConformanceReport {
+	if report == nil {
+		return nil
+	}
+	issues := make([]queue.ConformanceIssue, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		issues = append(issues, queue.ConformanceIssue{
+			Kind:     issue.Kind,
+			Symbol:   issue.Symbol,
+			Expected: issue.Expected,
+			Actual:   issue.Actual,
+			Line:     int32(issue.Line),
+		})
+	}
+	return &queue.ConformanceReport{
+		Conforms: report.Conforms,
+		Issues:   issues,
+	}
+}
+
+// cachedResult 코드 캐시에서 찾은 계획을 재사용할 파일 목록으로 변환 (조회에 실패하면 새로 구현)
+func (h *ImplementationHandler) cachedResult(ctx context.Context, job *queue.Job, language string, plans []service.Plan, hashes map[int64]string) ([]service.GeneratedFile, map[int64]queue.GeneratedFile) {
+	if h.codeCache == nil {
+		return nil, nil
+	}
+
+	var previous []service.GeneratedFile
+	cached := make(map[int64]queue.GeneratedFile)
+	for _, plan := range plans {
+		file, err := h.codeCache.GetCode(ctx, queue.CodeCacheKey{
+			TenantID:    job.TenantID,
+			Language:    language,
+			ContentHash: hashes[plan.ID],
+		})
+		if err != nil {
+			log.Printf("Failed to look up cached code for plan %d: %v", plan.ID, err)
+			continue
+		}
+		if file == nil {
+			continue
+		}
+		cached[plan.ID] = *file
+		// 경로는 이번 계획 목록 기준으로 다시 정함
+		previous = append(previous, service.GeneratedFile{
+			Language: language,
+			PlanID:   plan.ID,
+			Code:     file.Code,
+			Status:   service.PlanStatusCompleted,
+		})
+	}
+	return previous, cached
+}
+
+// cacheFiles 이번에 구현한 파일 중 컴파일 진단과 계획 불일치가 없고 테스트를 모두 통과한 파일을 코드 캐시에 저장
+func (h *ImplementationHandler) cacheFiles(ctx context.Context, job *queue.Job, files []queue.GeneratedFile, targets []int) {
+	if h.codeCache == nil {
+		return
+	}
+
+	for _, index := range targets {
+		file := files[index]
+		if !cacheable(file) {
+			continue
+		}
+		err := h.codeCache.PutCode(ctx, queue.CodeCacheKey{
+			TenantID:    job.TenantID,
+			Language:    file.Language,
+			ContentHash: file.ContentHash,
+		}, file)
+		if err != nil {
+			log.Printf("Failed to cache code for plan %d: %v", file.PlanID, err)
+		}
+	}
+}
+
+func cacheable(file queue.GeneratedFile) bool {
+	if file.Status != queue.PlanStatusCompleted || file.ContentHash == "" || len(file.Diagnostics) > 0 {
+		return false
+	}
+	if file.Conformance != nil && !file.Conformance.Conforms {
+		return false
+	}
+	for _, test := range file.Tests {
+		if !test.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// implementedFiles 구현에 성공한 파일만 반환
+func implementedFiles(files []queue.GeneratedFile) []queue.GeneratedFile {
+	implemented := make([]queue.GeneratedFile, 0, len(files))
+	for _, file := range files {
+		if file.Status == queue.PlanStatusCompleted {
+			implemented = append(implemented, file)
+		}
+	}
+	return implemented
+}
+
+// planErrors 구현되지 않은 계획의 실패 이유를 요약
+func planErrors(files []queue.GeneratedFile) string {
+	var errs []string
+	for _, file := range files {
+		if file.Status != queue.PlanStatusCompleted {
+			errs = append(errs, fmt.Sprintf("%s: %s", file.Path, file.Error))
+		}
+	}
+	return strings.Join(errs, "; ")
+}
+
+// combineFiles 파일 경로를 주석으로 구분하여 하나의 코드로 합침
+func combineFiles(files []queue.GeneratedFile) string {
+	var b strings.Builder
+	for i, file := range files {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("// File: " + file.Path + "\n")
+		b.WriteString(file.Code)
+		if !strings.HasSuffix(file.Code, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// analyzeFiles targets의 파일마다 코드 구간 설명을 생성 (라인 번호는 파일 기준)
+func (h *ImplementationHandler) analyzeFiles(ctx context.Context, files []queue.GeneratedFile, targets []int) error {
+	var wg sync.WaitGroup
+	errorChan := make(chan error, len(targets))
+
+	for _, i := range targets {
+		wg.Add(1)
+		go func(file *queue.GeneratedFile) {
+			defer wg.Done()
+			analyzerResp, err := h.analyzerClient.AnalyzeCodeSegments(ctx, &analyzer.AnalyzeCodeSegmentsRequest{
+				Code:     file.Code,
+				Language: file.Language,
+			})
+			if err != nil {
+				errorChan <- fmt.Errorf("failed to analyze code of %s: %v", file.Path, err)
+				return
+			}
+
+			file.ExplainedSegments = make([]queue.ExplainedSegment, 0, len(analyzerResp.CodeSegments))
+			for _, pbSegment := range analyzerResp.CodeSegments {
+				file.ExplainedSegments = append(file.ExplainedSegments, queue.ExplainedSegment{
+					StartLine:   pbSegment.StartLine,
+					EndLine:     pbSegment.EndLine,
+					Explanation: pbSegment.Explanation,
+				})
+			}
+		}(&files[i])
+	}
+
+	wg.Wait()
+	close(errorChan)
+	if err, ok := <-errorChan; ok {
+		return err
+	}
+	return nil
+}
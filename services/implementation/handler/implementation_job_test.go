@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"codev42-implementation/configs"
+	"codev42-implementation/proto/analyzer"
+	"codev42-implementation/proto/diagram"
+	"codev42-implementation/proto/implementation"
+	"codev42-implementation/proto/plan"
+	"codev42-implementation/queue"
+	"codev42-llm/client"
+
+	"google.golang.org/grpc"
+)
+
+// fakePlanClient 고정된 개발 계획을 돌려주는 Plan 서비스
+type fakePlanClient struct {
+	plan.PlanServiceClient
+	resp *plan.GetPlanByIdResponse
+}
+
+func (f *fakePlanClient) GetPlanById(ctx context.Context, in *plan.GetPlanByIdRequest, opts ...grpc.CallOption) (*plan.GetPlanByIdResponse, error) {
+	return f.resp, nil
+}
+
+// fakeDiagramClient 요청받은 다이어그램 종류를 기록하고 성공 응답을 돌려주는 Diagram 서비스
+type fakeDiagramClient struct {
+	diagram.DiagramServiceClient
+	calls []string
+}
+
+func (f *fakeDiagramClient) GenerateDiagrams(ctx context.Context, in *diagram.GenerateDiagramsRequest, opts ...grpc.CallOption) (*diagram.GenerateDiagramsResponse, error) {
+	f.calls = append(f.calls, "all")
+	var diagrams []*diagram.DiagramResult
+	for _, diagramType := range []string{"classDiagram", "sequenceDiagram", "flowchart"} {
+		diagrams = append(diagrams, &diagram.DiagramResult{Diagram: diagramType + " of " + in.Purpose, Type: diagramType, Success: true})
+	}
+	return &diagram.GenerateDiagramsResponse{Diagrams: diagrams, SuccessCount: 3, TotalCount: 3}, nil
+}
+
+func (f *fakeDiagramClient) GenerateFlowchartDiagram(ctx context.Context, in *diagram.GenerateDiagramRequest, opts ...grpc.CallOption) (*diagram.GenerateDiagramResponse, error) {
+	f.calls = append(f.calls, "flowchart")
+	return &diagram.GenerateDiagramResponse{Diagram: "flowchart", Type: "flowchart", Success: true}, nil
+}
+
+// fakeAnalyzerClient 파일마다 코드 구간 하나를 설명하는 Analyzer 서비스 (err가 있으면 실패)
+type fakeAnalyzerClient struct {
+	analyzer.AnalyzerServiceClient
+	err error
+}
+
+func (f *fakeAnalyzerClient) AnalyzeCodeSegments(ctx context.Context, in *analyzer.AnalyzeCodeSegmentsRequest, opts ...grpc.CallOption) (*analyzer.AnalyzeCodeSegmentsResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &analyzer.AnalyzeCodeSegmentsResponse{
+		CodeSegments: []*analyzer.CodeSegment{{StartLine: 0, EndLine: 1, Explanation: "설명"}},
+		Success:      true,
+	}, nil
+}
+
+// testHandler 네트워크 없이 FakeProvider와 메모리 저장소로 동작하는 핸들러
+type testHandler struct {
+	*ImplementationHandler
+	llm      *client.FakeProvider
+	plans    *fakePlanClient
+	jobs     *queue.JobQueue
+	diagrams *fakeDiagramClient
+	analyzer *fakeAnalyzerClient
+}
+
+func newTestHandler(t *testing.T, config configs.Config) *testHandler {
+	t.Helper()
+	llm := client.NewFakeProvider()
+	llm.Script("development_result",
+		`{"code":"class Repository:\n    def get(self, key):\n        return key\n"}`,
+		`{"code":"class Service:\n    def run(self, repository):\n        return repository.get(1)\n"}`,
+	)
+	planClient := &fakePlanClient{resp: &plan.GetPlanByIdResponse{
+		DevPlanId: 1,
+		ProjectId: "project",
+		Branch:    "main",
+		Language:  "python",
+		Plans: []*plan.Plan{
+			{PlanId: 1, ClassName: "Repository", Annotations: []*plan.Annotation{{Name: "get", Params: "key", Returns: "object", Description: "조회"}}},
+			{PlanId: 2, ClassName: "Service", DependsOn: []string{"Repository"}, Annotations: []*plan.Annotation{{Name: "run", Params: "repository", Returns: "object", Description: "실행"}}},
+		},
+	}}
+
+	h := &testHandler{
+		llm:      llm,
+		plans:    planClient,
+		jobs:     queue.NewJobQueue(),
+		diagrams: &fakeDiagramClient{},
+		analyzer: &fakeAnalyzerClient{},
+	}
+	h.ImplementationHandler = NewImplementationHandler(
+		config,
+		planClient,
+		h.diagrams,
+		h.analyzer,
+		h.jobs,
+		queue.NewMemoryCodeCache(),
+		queue.NewMemoryImplementationStore(),
+		queue.NewMemoryPipelineStore(),
+		llm,
+	)
+	return h
+}
+
+// claim ImplementPlan으로 Job을 만들고 워커처럼 lease를 획득
+func (h *testHandler) claim(t *testing.T, gates ...string) *queue.Job {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := h.ImplementPlan(ctx, &implementation.ImplementPlanRequest{DevPlanId: 1, ApprovalGates: gates}); err != nil {
+		t.Fatalf("ImplementPlan: %v", err)
+	}
+	return h.claimNext(t)
+}
+
+func (h *testHandler) claimNext(t *testing.T) *queue.Job {
+	t.Helper()
+	job, err := h.jobs.ClaimJob(context.Background(), "worker", time.Minute, 3)
+	if err != nil || job == nil {
+		t.Fatalf("ClaimJob: job=%v err=%v", job, err)
+	}
+	return job
+}
+
+func (h *testHandler) job(t *testing.T, jobID string) *queue.Job {
+	t.Helper()
+	job, err := h.jobs.GetJob(context.Background(), jobID)
+	if err != nil {
+		t.Fatalf("GetJob: %v", err)
+	}
+	return job
+}
+
+func TestProcessJobWithFakeProvider(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+
+	job := h.claim(t)
+	h.ProcessJob(ctx, job)
+
+	done := h.job(t, job.ID)
+	if done.Status != queue.JobStatusCompleted || done.Progress != 100 {
+		t.Fatalf("expected completed job, got status=%s progress=%d error=%q", done.Status, done.Progress, done.Error)
+	}
+
+	files := done.Result.Files
+	if len(files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(files))
+	}
+	for i, want := range []string{"class Repository", "class Service"} {
+		file := files[i]
+		if file.Status != queue.PlanStatusCompleted || !strings.Contains(file.Code, want) {
+			t.Fatalf("file %d: status=%s code=%q", i, file.Status, file.Code)
+		}
+		if len(file.ExplainedSegments) != 1 {
+			t.Fatalf("file %d was not analyzed", i)
+		}
+	}
+	if len(done.Result.Diagrams) != 3 {
+		t.Fatalf("expected 3 diagrams, got %d", len(done.Result.Diagrams))
+	}
+
+	// 의존 관계 순서대로 계획마다 한 번씩 LLM을 호출
+	calls := h.llm.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 llm calls, got %d", len(calls))
+	}
+	if !strings.Contains(calls[1].Prompt, "class Repository") {
+		t.Fatalf("dependent plan was generated without the code of its dependency")
+	}
+
+	implementations, err := h.ListImplementations(ctx, &implementation.ListImplementationsRequest{DevPlanId: 1})
+	if err != nil || len(implementations.Implementations) != 1 {
+		t.Fatalf("expected one recorded implementation, got %v (err=%v)", implementations, err)
+	}
+
+	// 내용이 같은 계획은 코드 캐시에서 재사용하여 LLM을 다시 호출하지 않음
+	again := h.claim(t)
+	h.ProcessJob(ctx, again)
+	if done := h.job(t, again.ID); done.Status != queue.JobStatusCompleted || !done.Result.Files[0].Cached {
+		t.Fatalf("expected cached completed job, got status=%s", done.Status)
+	}
+	if len(h.llm.Calls()) != 2 {
+		t.Fatalf("cached plans were generated again")
+	}
+}
+
+func TestProcessJobRegeneratesDependentsOfChangedPlan(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+
+	h.ProcessJob(ctx, h.claim(t))
+	if len(h.llm.Calls()) != 2 {
+		t.Fatalf("expected 2 llm calls, got %d", len(h.llm.Calls()))
+	}
+
+	// Service가 의존하는 Repository의 시그니처만 바뀌어도 Service를 캐시에서 재사용하지 않음
+	h.plans.resp.Plans[0].Annotations[0].Returns = "str"
+	h.llm.Script("development_result",
+		`{"code":"class Repository:\n    def get(self, key):\n        return str(key)\n"}`,
+		`{"code":"class Service:\n    def run(self, repository):\n        return repository.get(2)\n"}`,
+	)
+	job := h.claim(t)
+	h.ProcessJob(ctx, job)
+
+	done := h.job(t, job.ID)
+	if done.Status != queue.JobStatusCompleted {
+		t.Fatalf("expected completed job, got status=%s error=%q", done.Status, done.Error)
+	}
+	for i, file := range done.Result.Files {
+		if file.Cached {
+			t.Fatalf("file %d was reused from the cache after its dependency changed", i)
+		}
+	}
+	if len(h.llm.Calls()) != 4 {
+		t.Fatalf("expected both plans to be generated again, got %d llm calls", len(h.llm.Calls()))
+	}
+}
+
+func TestProcessJobStopsAtApprovalGates(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+
+	job := h.claim(t, queue.ApprovalGateCode, queue.ApprovalGateResult)
+	h.ProcessJob(ctx, job)
+	waiting := h.job(t, job.ID)
+	if waiting.Status != queue.JobStatusAwaitingApproval || waiting.PendingGate != queue.ApprovalGateCode {
+		t.Fatalf("expected job at code gate, got status=%s gate=%s", waiting.Status, waiting.PendingGate)
+	}
+	if len(h.diagrams.calls) != 0 {
+		t.Fatalf("diagrams were generated before the code gate was approved")
+	}
+
+	if _, err := h.ApproveJob(ctx, &implementation.ApproveJobRequest{JobId: job.ID}); err != nil {
+		t.Fatalf("ApproveJob: %v", err)
+	}
+	h.ProcessJob(ctx, h.claimNext(t))
+	if waiting := h.job(t, job.ID); waiting.PendingGate != queue.ApprovalGateResult {
+		t.Fatalf("expected job at result gate, got status=%s gate=%s", waiting.Status, waiting.PendingGate)
+	}
+
+	if _, err := h.ApproveJob(ctx, &implementation.ApproveJobRequest{JobId: job.ID}); err != nil {
+		t.Fatalf("ApproveJob: %v", err)
+	}
+	h.ProcessJob(ctx, h.claimNext(t))
+	done := h.job(t, job.ID)
+	if done.Status != queue.JobStatusCompleted || len(done.Result.Diagrams) != 3 {
+		t.Fatalf("expected completed job with diagrams, got status=%s error=%q", done.Status, done.Error)
+	}
+	if len(h.llm.Calls()) != 2 || len(h.diagrams.calls) != 1 {
+		t.Fatalf("resumed job repeated finished stages: llm=%d diagrams=%d", len(h.llm.Calls()), len(h.diagrams.calls))
+	}
+}
+
+func TestDeleteJobsByDevPlanWaitsForRunningJobs(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+	job := h.claim(t)
+
+	// 워커처럼 취소될 때까지 실행하다가, 멈추기 직전에 구현 실행 기록을 남기고 lease를 반환
+	jobCtx, cancel := context.WithCancel(ctx)
+	h.trackRunning(job.ID, cancel)
+	go func() {
+		<-jobCtx.Done()
+		h.untrackRunning(job.ID)
+		h.recordImplementation(ctx, job, queue.JobStatusCompleted, &queue.JobResult{})
+		if err := h.jobs.ReleaseLease(ctx, job.ID, job.LeaseOwner); err != nil {
+			t.Errorf("ReleaseLease: %v", err)
+		}
+	}()
+
+	resp, err := h.DeleteJobsByDevPlan(ctx, &implementation.DeleteJobsByDevPlanRequest{DevPlanIds: []int64{1}})
+	if err != nil {
+		t.Fatalf("DeleteJobsByDevPlan: %v", err)
+	}
+	if resp.DeletedJobs != 1 || resp.DeletedImplementations != 1 {
+		t.Fatalf("expected the job and its late implementation record to be deleted, got %+v", resp)
+	}
+	implementations, err := h.ListImplementations(ctx, &implementation.ListImplementationsRequest{DevPlanId: 1})
+	if err != nil || len(implementations.Implementations) != 0 {
+		t.Fatalf("implementation records remain after delete: %v (err=%v)", implementations, err)
+	}
+}
+
+func TestProcessJobDoesNotRecordCancelledJob(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+
+	job := h.claim(t)
+	if _, err := h.CancelImplementation(ctx, &implementation.CancelImplementationRequest{JobId: job.ID}); err != nil {
+		t.Fatalf("CancelImplementation: %v", err)
+	}
+	h.ProcessJob(ctx, job)
+
+	if done := h.job(t, job.ID); done.Status != queue.JobStatusCancelled {
+		t.Fatalf("cancelled job was overwritten: %s", done.Status)
+	}
+	implementations, err := h.ListImplementations(ctx, &implementation.ListImplementationsRequest{DevPlanId: 1})
+	if err != nil || len(implementations.Implementations) != 0 {
+		t.Fatalf("cancelled job recorded an implementation: %v (err=%v)", implementations, err)
+	}
+}
@@ -0,0 +1,599 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"codev42-implementation/proto/diagram"
+	"codev42-implementation/proto/implementation"
+	"codev42-implementation/queue"
+	"codev42-implementation/service"
+)
+
+// 파이프라인 정의 출처 (GetPipeline의 Source)
+const (
+	pipelineSourceDB      = "db"
+	pipelineSourceRepo    = "repo"
+	pipelineSourceDefault = "default"
+)
+
+// stageRunner 파이프라인 단계 구현
+type stageRunner interface {
+	// skip 이번 실행에서 할 일이 없는 단계인지 여부 (건너뛴 단계는 진행 이벤트를 남기지 않음)
+	skip(h *ImplementationHandler, run *pipelineRun) bool
+	// run 단계를 실행하여 run의 코드, 파일이나 다이어그램을 갱신
+	run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error
+}
+
+// pipelineStageSpec 등록된 단계의 종류
+type pipelineStageSpec struct {
+	step        string // 진행 상황에 표시할 설명
+	changesCode bool   // 계획별 코드를 만들거나 바꾸는 단계 (code 승인 게이트 전, 다른 단계보다 앞에 와야 함)
+	// configure 프로젝트 정의의 settings를 검증하여 실행할 단계를 만듦
+	configure func(config service.PipelineStageConfig) (stageRunner, error)
+}
+
+var (
+	pipelineStagesMu sync.RWMutex
+	pipelineStages   = map[string]pipelineStageSpec{}
+)
+
+func init() {
+	registerPipelineStage(stageGenerateCode, pipelineStageSpec{
+		step:        "Generating code",
+		changesCode: true,
+		configure:   withoutSettings(generateCodeStage{}),
+	})
+	registerPipelineStage(stageRunTests, pipelineStageSpec{
+		step:        "Running tests",
+		changesCode: true,
+		configure:   withoutSettings(runTestsStage{}),
+	})
+	registerPipelineStage(stageGenerateDiagrams, pipelineStageSpec{
+		step:      "Generating diagrams",
+		configure: newGenerateDiagramsStage,
+	})
+	registerPipelineStage(stageAnalyzeCode, pipelineStageSpec{
+		step:      "Analyzing code",
+		configure: withoutSettings(analyzeCodeStage{}),
+	})
+}
+
+// registerPipelineStage 파이프라인 정의에서 name으로 참조할 수 있는 단계를 등록
+func registerPipelineStage(name string, spec pipelineStageSpec) {
+	pipelineStagesMu.Lock()
+	defer pipelineStagesMu.Unlock()
+	pipelineStages[name] = spec
+}
+
+// pipelineStageFor 등록된 단계 조회
+func pipelineStageFor(name string) (pipelineStageSpec, bool) {
+	pipelineStagesMu.RLock()
+	defer pipelineStagesMu.RUnlock()
+	spec, ok := pipelineStages[name]
+	return spec, ok
+}
+
+// registeredPipelineStages 등록된 단계 이름 (정렬)
+func registeredPipelineStages() []string {
+	pipelineStagesMu.RLock()
+	defer pipelineStagesMu.RUnlock()
+	names := make([]string, 0, len(pipelineStages))
+	for name := range pipelineStages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StageInput 등록된 단계가 받는 Job 정보와 지금까지의 결과 (코드 단계 이후에 실행되므로 계획별 파일이 모두 있음)
+type StageInput struct {
+	JobID     string
+	DevPlanID int64
+	ProjectID string
+	Language  string
+	Files     []queue.GeneratedFile // 계획별 파일 (구현 순서). 복사본이므로 수정해도 결과에 반영되지 않음
+	Diagrams  []queue.Diagram
+}
+
+// StageOutput 단계 실행 결과
+type StageOutput struct {
+	Files    []queue.GeneratedFile // nil이 아니면 결과 파일을 교체 (입력과 같은 순서와 개수)
+	Diagrams []queue.Diagram       // 결과에 추가할 다이어그램
+	Warnings []string              // Job 진행 이벤트로 남길 경고
+}
+
+// Stage 파이프라인 정의에서 이름으로 참조하는 단계 (RegisterStage로 등록)
+type Stage interface {
+	Run(ctx context.Context, input *StageInput) (*StageOutput, error)
+}
+
+// StageFactory 프로젝트 정의의 settings를 검증하여 실행할 단계를 만듦 (config.DecodeSettings 사용)
+type StageFactory func(config service.PipelineStageConfig) (Stage, error)
+
+// RegisterStage 파이프라인 정의에서 name으로 참조할 수 있는 단계를 등록. step은 진행 상황에 표시할 설명.
+// 등록된 단계는 코드 단계 이후에 실행되며, 이미 등록된 이름이면 panic
+func RegisterStage(name string, step string, factory StageFactory) {
+	if name == "" || factory == nil {
+		panic("handler: RegisterStage requires a name and a factory")
+	}
+	if _, ok := pipelineStageFor(name); ok {
+		panic("handler: RegisterStage called twice for stage " + name)
+	}
+	registerPipelineStage(name, pipelineStageSpec{
+		step: step,
+		configure: func(config service.PipelineStageConfig) (stageRunner, error) {
+			stage, err := factory(config)
+			if err != nil {
+				return nil, err
+			}
+			return registeredStage{name: config.Name, stage: stage}, nil
+		},
+	})
+}
+
+// registeredStage RegisterStage로 등록된 단계를 파이프라인 단계로 실행
+type registeredStage struct {
+	name  string
+	stage Stage
+}
+
+func (registeredStage) skip(h *ImplementationHandler, run *pipelineRun) bool {
+	return false
+}
+
+func (s registeredStage) run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error {
+	output, err := s.stage.Run(ctx, &StageInput{
+		JobID:     run.job.ID,
+		DevPlanID: run.job.DevPlanID,
+		ProjectID: run.plan.ProjectId,
+		Language:  run.plan.Language,
+		Files:     append([]queue.GeneratedFile(nil), run.files...),
+		Diagrams:  append([]queue.Diagram(nil), run.diagrams...),
+	})
+	if err != nil {
+		return err
+	}
+	if output == nil {
+		return nil
+	}
+	if output.Files != nil {
+		if len(output.Files) != len(run.files) {
+			return fmt.Errorf("stage %s returned %d files for %d plans", s.name, len(output.Files), len(run.files))
+		}
+		run.files = output.Files
+	}
+	run.diagrams = append(run.diagrams, output.Diagrams...)
+	for _, warning := range output.Warnings {
+		h.warn(ctx, run.job.ID, s.name, "%s", warning)
+	}
+	return nil
+}
+
+// withoutSettings settings가 없는 단계의 configure
+func withoutSettings(runner stageRunner) func(config service.PipelineStageConfig) (stageRunner, error) {
+	return func(config service.PipelineStageConfig) (stageRunner, error) {
+		if config.HasSettings() {
+			return nil, fmt.Errorf("stage %s has no settings", config.Name)
+		}
+		return runner, nil
+	}
+}
+
+// configuredStage 프로젝트 정의의 설정이 적용된 단계
+type configuredStage struct {
+	name     string
+	step     string
+	optional bool
+	timeout  time.Duration // 0이면 서비스 기본값
+	runner   stageRunner
+}
+
+// configuredPipeline 실행할 단계 목록. 앞의 codeStages개 단계가 계획별 코드를 만들거나 바꾸는 단계
+type configuredPipeline struct {
+	stages     []configuredStage
+	codeStages int
+}
+
+// stageNames 단계 이름 목록
+func (p *configuredPipeline) stageNames() []string {
+	names := make([]string, 0, len(p.stages))
+	for _, stage := range p.stages {
+		names = append(names, stage.name)
+	}
+	return names
+}
+
+// buildPipeline YAML 정의를 검증하여 실행할 단계 목록으로 변환.
+// generate_code가 첫 단계여야 하며, 코드를 바꾸는 단계는 다른 단계보다 앞에 와야 함
+func buildPipeline(content string) (*configuredPipeline, error) {
+	definition, err := service.ParsePipelineDefinition(content)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Stages[0].Name != stageGenerateCode {
+		return nil, fmt.Errorf("the first stage must be %s", stageGenerateCode)
+	}
+
+	pipeline := &configuredPipeline{}
+	for _, config := range definition.Stages {
+		spec, ok := pipelineStageFor(config.Name)
+		if !ok {
+			return nil, fmt.Errorf("unknown stage %s (registered: %s)", config.Name, strings.Join(registeredPipelineStages(), ", "))
+		}
+		if config.Name == stageGenerateCode && config.Optional {
+			return nil, fmt.Errorf("stage %s cannot be optional", stageGenerateCode)
+		}
+		if spec.changesCode {
+			if len(pipeline.stages) > pipeline.codeStages {
+				return nil, fmt.Errorf("stage %s changes code and must come before %s", config.Name, pipeline.stages[pipeline.codeStages].name)
+			}
+			pipeline.codeStages++
+		}
+		runner, err := spec.configure(config)
+		if err != nil {
+			return nil, err
+		}
+		pipeline.stages = append(pipeline.stages, configuredStage{
+			name:     config.Name,
+			step:     spec.step,
+			optional: config.Optional,
+			timeout:  time.Duration(config.TimeoutSeconds) * time.Second,
+			runner:   runner,
+		})
+	}
+	return pipeline, nil
+}
+
+// pipelineDefinition 프로젝트의 파이프라인 정의 조회 (DB → PIPELINE_DIR의 <프로젝트 ID>.yaml → 기본 정의 순)
+func (h *ImplementationHandler) pipelineDefinition(ctx context.Context, projectID string) (*queue.Pipeline, string, error) {
+	if h.pipelineStore != nil && projectID != "" {
+		stored, err := h.pipelineStore.GetPipeline(ctx, projectID)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get pipeline of project %s: %v", projectID, err)
+		}
+		if stored != nil {
+			return stored, pipelineSourceDB, nil
+		}
+	}
+
+	if path := h.pipelineFilePath(projectID); path != "" {
+		content, err := os.ReadFile(path)
+		if err == nil {
+			return &queue.Pipeline{ProjectID: projectID, Definition: string(content)}, pipelineSourceRepo, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", fmt.Errorf("failed to read pipeline of project %s: %v", projectID, err)
+		}
+	}
+
+	return &queue.Pipeline{ProjectID: projectID, Definition: service.DefaultPipelineYAML}, pipelineSourceDefault, nil
+}
+
+// pipelineFilePath PIPELINE_DIR에 있는 프로젝트 정의 파일 경로 (설정이 없거나 파일 이름으로 쓸 수 없는 ID면 빈 문자열)
+func (h *ImplementationHandler) pipelineFilePath(projectID string) string {
+	if h.Config.PipelineDir == "" || projectID == "" || projectID == "." || projectID == ".." {
+		return ""
+	}
+	if strings.ContainsAny(projectID, `/\`) {
+		return ""
+	}
+	return filepath.Join(h.Config.PipelineDir, projectID+".yaml")
+}
+
+// loadPipeline 프로젝트의 파이프라인 정의를 읽어 실행할 단계 목록으로 변환
+func (h *ImplementationHandler) loadPipeline(ctx context.Context, projectID string) (*configuredPipeline, error) {
+	definition, source, err := h.pipelineDefinition(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := buildPipeline(definition.Definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline of project %s (%s): %v", projectID, source, err)
+	}
+	return pipeline, nil
+}
+
+// SetPipeline 프로젝트의 파이프라인 정의 저장 (저장 전에 단계와 설정을 검증)
+func (h *ImplementationHandler) SetPipeline(ctx context.Context, req *implementation.SetPipelineRequest) (*implementation.SetPipelineResponse, error) {
+	if req.ProjectId == "" {
+		return nil, fmt.Errorf("project id is required")
+	}
+	if h.pipelineStore == nil {
+		return nil, fmt.Errorf("pipeline store is not configured")
+	}
+	pipeline, err := buildPipeline(req.Definition)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %v", err)
+	}
+
+	stored, err := h.pipelineStore.PutPipeline(ctx, req.ProjectId, req.Definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save pipeline: %v", err)
+	}
+
+	return &implementation.SetPipelineResponse{
+		ProjectId: stored.ProjectID,
+		Stages:    pipeline.stageNames(),
+		UpdatedAt: stored.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// GetPipeline 프로젝트에 적용되는 파이프라인 정의 조회 (정의가 유효하지 않으면 Error에 이유를 담아 반환)
+func (h *ImplementationHandler) GetPipeline(ctx context.Context, req *implementation.GetPipelineRequest) (*implementation.GetPipelineResponse, error) {
+	definition, source, err := h.pipelineDefinition(ctx, req.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &implementation.GetPipelineResponse{
+		ProjectId:  req.ProjectId,
+		Definition: definition.Definition,
+		Source:     source,
+	}
+	if !definition.UpdatedAt.IsZero() {
+		resp.UpdatedAt = definition.UpdatedAt.Format(time.RFC3339)
+	}
+	pipeline, err := buildPipeline(definition.Definition)
+	if err != nil {
+		resp.Error = err.Error()
+		return resp, nil
+	}
+	resp.Stages = pipeline.stageNames()
+	return resp, nil
+}
+
+// DeletePipeline 저장된 파이프라인 정의 삭제 (이후 저장소 파일이나 기본 정의를 사용)
+func (h *ImplementationHandler) DeletePipeline(ctx context.Context, req *implementation.DeletePipelineRequest) (*implementation.DeletePipelineResponse, error) {
+	if req.ProjectId == "" {
+		return nil, fmt.Errorf("project id is required")
+	}
+	if h.pipelineStore == nil {
+		return &implementation.DeletePipelineResponse{Deleted: false}, nil
+	}
+
+	deleted, err := h.pipelineStore.DeletePipeline(ctx, req.ProjectId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete pipeline: %v", err)
+	}
+	return &implementation.DeletePipelineResponse{Deleted: deleted}, nil
+}
+
+// generateCodeStage AI로 계획별 코드 생성 (의존 관계 순서대로, 계획마다 파일 하나)
+type generateCodeStage struct{}
+
+func (generateCodeStage) skip(h *ImplementationHandler, run *pipelineRun) bool {
+	return false
+}
+
+func (generateCodeStage) run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error {
+	job := run.job
+
+	// 재시도는 기존 결과에 있는 계획만 대상으로 하고, 다시 구현하지 않는 파일은 그대로 사용
+	var previous []service.GeneratedFile
+	run.previousFiles = make(map[int64]queue.GeneratedFile)
+	if run.retrying {
+		previous, run.previousFiles = previousResult(job.Result, job.RetryPlanIDs)
+	}
+
+	// 거절된 계획은 리뷰어 피드백을 반영하여 다시 구현
+	feedback := make(map[int64]string)
+	if run.retrying && job.ReviewFeedback != "" {
+		for _, planID := range job.RetryPlanIDs {
+			feedback[planID] = job.ReviewFeedback
+		}
+	}
+
+	// planpb.Plan -> service.Plan 변환
+	allPlans := make([]service.Plan, 0, len(run.plan.Plans))
+	for _, pbPlan := range run.plan.Plans {
+		annotations := make([]service.Annotation, 0, len(pbPlan.Annotations))
+		for _, pbAnnotation := range pbPlan.Annotations {
+			annotations = append(annotations, service.Annotation{
+				Name:        pbAnnotation.Name,
+				Description: pbAnnotation.Description,
+				Params:      pbAnnotation.Params,
+				Returns:     pbAnnotation.Returns,
+			})
+		}
+		allPlans = append(allPlans, service.Plan{
+			ID:          pbPlan.PlanId,
+			ClassName:   pbPlan.ClassName,
+			Annotations: annotations,
+			DependsOn:   pbPlan.DependsOn,
+			Feedback:    feedback[pbPlan.PlanId],
+		})
+	}
+
+	// 해시는 의존 대상의 내용을 포함하므로 재시도 대상만 고르기 전에 전체 계획으로 계산
+	hashes, err := service.PlanHashes(allPlans)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %v", err)
+	}
+	run.hashes = hashes
+	plans := make([]service.Plan, 0, len(allPlans))
+	for _, plan := range allPlans {
+		if _, ok := run.previousFiles[plan.ID]; run.retrying && !ok {
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	// 내용(의존 대상 포함)이 바뀌지 않은 계획은 코드 캐시의 파일을 재사용 (재시도는 저장된 결과를 재사용하므로 제외)
+	if !run.retrying {
+		previous, run.cachedFiles = h.cachedResult(ctx, job, run.plan.Language, plans, run.hashes)
+	}
+
+	onGenerated := func(planIndex int, file service.GeneratedFile) {
+		if file.Status != service.PlanStatusCompleted {
+			h.warn(ctx, job.ID, stageGenerateCode, "%s was not implemented (%s): %s", file.Path, file.Status, file.Error)
+			return
+		}
+		h.emitEvent(ctx, &queue.JobEvent{
+			JobID:     job.ID,
+			Type:      queue.JobEventPlanGenerated,
+			Stage:     stageGenerateCode,
+			Message:   fmt.Sprintf("Plan %d generated", planIndex+1),
+			PlanIndex: int32(planIndex),
+			PlanID:    file.PlanID,
+			Path:      file.Path,
+		})
+		if len(file.Diagnostics) > 0 {
+			h.warn(ctx, job.ID, stageGenerateCode, "%s has %d compile diagnostics after repair", file.Path, len(file.Diagnostics))
+		}
+		if file.Conformance != nil && !file.Conformance.Conforms {
+			h.warn(ctx, job.ID, stageGenerateCode, "%s does not conform to the plan: %d issues", file.Path, len(file.Conformance.Issues))
+		}
+	}
+	generated, err := h.workerAgent.ImplementPlan(ctx, run.plan.Language, plans, previous, onGenerated)
+	if err != nil {
+		return fmt.Errorf("failed to generate code: %v", err)
+	}
+	run.generated = generated
+	return nil
+}
+
+// runTestsStage 어노테이션별 단위 테스트를 생성하여 실행 (실패한 함수는 다시 구현, 지원하는 언어만)
+type runTestsStage struct{}
+
+func (runTestsStage) skip(h *ImplementationHandler, run *pipelineRun) bool {
+	return !h.unitTester.Supports(run.plan.Language)
+}
+
+func (runTestsStage) run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error {
+	if err := h.unitTester.Run(ctx, run.plan.Language, run.generated); err != nil {
+		return fmt.Errorf("failed to run tests: %v", err)
+	}
+	for _, file := range run.generated {
+		if file.Reused() {
+			continue
+		}
+		failed := 0
+		for _, test := range file.Tests {
+			if !test.Passed {
+				failed++
+			}
+		}
+		if failed > 0 {
+			h.warn(ctx, run.job.ID, stageRunTests, "%s has %d failing tests", file.Path, failed)
+		}
+	}
+	return nil
+}
+
+// 다이어그램 종류별 Diagram 서비스 RPC (generate_diagrams 단계의 types 설정)
+var diagramGenerators = map[string]func(client diagram.DiagramServiceClient, ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error){
+	"classDiagram": func(client diagram.DiagramServiceClient, ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
+		return client.GenerateClassDiagram(ctx, req)
+	},
+	"sequenceDiagram": func(client diagram.DiagramServiceClient, ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
+		return client.GenerateSequenceDiagram(ctx, req)
+	},
+	"flowchart": func(client diagram.DiagramServiceClient, ctx context.Context, req *diagram.GenerateDiagramRequest) (*diagram.GenerateDiagramResponse, error) {
+		return client.GenerateFlowchartDiagram(ctx, req)
+	},
+}
+
+// generateDiagramsSettings generate_diagrams 단계 설정
+type generateDiagramsSettings struct {
+	Types []string `yaml:"types"` // 생성할 다이어그램 종류 (classDiagram, sequenceDiagram, flowchart). 비어 있으면 전부
+}
+
+// generateDiagramsStage Diagram 서비스로 구현된 전체 파일에 대한 다이어그램 생성
+type generateDiagramsStage struct {
+	types []string
+}
+
+func newGenerateDiagramsStage(config service.PipelineStageConfig) (stageRunner, error) {
+	var settings generateDiagramsSettings
+	if err := config.DecodeSettings(&settings); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(settings.Types))
+	for _, diagramType := range settings.Types {
+		if _, ok := diagramGenerators[diagramType]; !ok {
+			return nil, fmt.Errorf("unknown diagram type %s in stage %s (classDiagram, sequenceDiagram, flowchart)", diagramType, config.Name)
+		}
+		if seen[diagramType] {
+			return nil, fmt.Errorf("diagram type %s is listed more than once in stage %s", diagramType, config.Name)
+		}
+		seen[diagramType] = true
+	}
+	return generateDiagramsStage{types: settings.Types}, nil
+}
+
+func (generateDiagramsStage) skip(h *ImplementationHandler, run *pipelineRun) bool {
+	return false
+}
+
+func (s generateDiagramsStage) run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error {
+	code := combineFiles(implementedFiles(run.files))
+	purpose := fmt.Sprintf("Development Plan ID: %d", run.job.DevPlanID)
+
+	var results []*diagram.DiagramResult
+	if len(s.types) == 0 {
+		diagramResp, err := h.diagramClient.GenerateDiagrams(ctx, &diagram.GenerateDiagramsRequest{
+			Code:    code,
+			Purpose: purpose,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to generate diagrams: %v", err)
+		}
+		results = diagramResp.Diagrams
+	} else {
+		for _, diagramType := range s.types {
+			diagramResp, err := diagramGenerators[diagramType](h.diagramClient, ctx, &diagram.GenerateDiagramRequest{
+				Code:    code,
+				Purpose: purpose,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to generate %s: %v", diagramType, err)
+			}
+			results = append(results, &diagram.DiagramResult{
+				Diagram: diagramResp.Diagram,
+				Type:    diagramType,
+				Success: diagramResp.Success,
+				Error:   diagramResp.Error,
+			})
+		}
+	}
+
+	diagrams := make([]queue.Diagram, 0, len(results))
+	for _, pbDiagram := range results {
+		diagrams = append(diagrams, queue.Diagram{
+			Diagram: pbDiagram.Diagram,
+			Type:    pbDiagram.Type,
+		})
+		if !pbDiagram.Success {
+			h.warn(ctx, run.job.ID, stageGenerateDiagrams, "failed to generate %s: %s", pbDiagram.Type, pbDiagram.Error)
+			continue
+		}
+		h.emitEvent(ctx, &queue.JobEvent{
+			JobID:   run.job.ID,
+			Type:    queue.JobEventDiagramGenerated,
+			Stage:   stageGenerateDiagrams,
+			Diagram: pbDiagram.Type,
+		})
+	}
+	run.diagrams = append(run.diagrams, diagrams...)
+	return nil
+}
+
+// analyzeCodeStage Analyzer 서비스로 아직 분석되지 않은 파일별 코드 분석 (병렬)
+type analyzeCodeStage struct{}
+
+func (analyzeCodeStage) skip(h *ImplementationHandler, run *pipelineRun) bool {
+	return false
+}
+
+func (analyzeCodeStage) run(ctx context.Context, h *ImplementationHandler, run *pipelineRun) error {
+	return h.analyzeFiles(ctx, run.files, unanalyzedFiles(run.files))
+}
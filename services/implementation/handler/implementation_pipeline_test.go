@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"codev42-implementation/configs"
+	"codev42-implementation/proto/implementation"
+	"codev42-implementation/queue"
+	"codev42-implementation/service"
+)
+
+// headerStage 파일마다 settings의 header를 코드 앞에 붙이고 다이어그램과 경고를 하나씩 추가하는 외부 단계
+type headerStage struct {
+	header string
+	inputs chan *StageInput
+}
+
+func (s *headerStage) Run(ctx context.Context, input *StageInput) (*StageOutput, error) {
+	s.inputs <- input
+	files := make([]queue.GeneratedFile, len(input.Files))
+	for i, file := range input.Files {
+		file.Code = s.header + "\n" + file.Code
+		files[i] = file
+	}
+	return &StageOutput{
+		Files:    files,
+		Diagrams: []queue.Diagram{{Type: "header", Diagram: s.header}},
+		Warnings: []string{fmt.Sprintf("%d files", len(files))},
+	}, nil
+}
+
+var headerStageInputs = make(chan *StageInput, 1)
+
+func init() {
+	RegisterStage("add_header", "Adding headers", func(config service.PipelineStageConfig) (Stage, error) {
+		settings := struct {
+			Header string `yaml:"header"`
+		}{}
+		if err := config.DecodeSettings(&settings); err != nil {
+			return nil, err
+		}
+		if settings.Header == "" {
+			return nil, fmt.Errorf("stage %s requires a header", config.Name)
+		}
+		return &headerStage{header: settings.Header, inputs: headerStageInputs}, nil
+	})
+}
+
+func TestRegisteredStageRunsInPipeline(t *testing.T) {
+	h := newTestHandler(t, configs.Config{})
+	ctx := context.Background()
+
+	// settings가 잘못되면 등록한 쪽의 검증 오류로 저장을 거부
+	if _, err := h.SetPipeline(ctx, &implementation.SetPipelineRequest{
+		ProjectId:  "project",
+		Definition: "stages:\n  - name: generate_code\n  - name: add_header\n",
+	}); err == nil || !strings.Contains(err.Error(), "requires a header") {
+		t.Fatalf("expected settings error, got %v", err)
+	}
+	if _, err := h.SetPipeline(ctx, &implementation.SetPipelineRequest{
+		ProjectId: "project",
+		Definition: `stages:
+  - name: generate_code
+  - name: add_header
+    settings:
+      header: "# generated"
+  - name: generate_diagrams
+`,
+	}); err != nil {
+		t.Fatalf("SetPipeline: %v", err)
+	}
+
+	job := h.claim(t)
+	h.ProcessJob(ctx, job)
+
+	done := h.job(t, job.ID)
+	if done.Status != queue.JobStatusCompleted {
+		t.Fatalf("expected completed job, got status=%s error=%q", done.Status, done.Error)
+	}
+	input := <-headerStageInputs
+	if input.JobID != job.ID || input.ProjectID != "project" || input.Language != "python" || len(input.Files) != 2 {
+		t.Fatalf("unexpected stage input: %+v", input)
+	}
+	for i, file := range done.Result.Files {
+		if !strings.HasPrefix(file.Code, "# generated\n") {
+			t.Fatalf("file %d was not changed by the stage: %q", i, file.Code)
+		}
+	}
+	// 이후 단계의 다이어그램은 외부 단계의 다이어그램 뒤에 추가됨
+	if len(done.Result.Diagrams) != 4 || done.Result.Diagrams[0].Type != "header" {
+		t.Fatalf("expected stage diagram followed by generated diagrams, got %+v", done.Result.Diagrams)
+	}
+
+	events, err := h.jobs.ListJobEvents(ctx, job.ID, 0, 100)
+	if err != nil {
+		t.Fatalf("ListJobEvents: %v", err)
+	}
+	warned := false
+	for _, event := range events {
+		if event.Type == queue.JobEventWarning && event.Stage == "add_header" && event.Message == "2 files" {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Fatalf("stage warning was not recorded: %+v", events)
+	}
+}
+
+func TestRegisterStageRejectsDuplicateName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for a stage name that is already registered")
+		}
+	}()
+	RegisterStage(stageGenerateCode, "Generating code", func(config service.PipelineStageConfig) (Stage, error) {
+		return nil, nil
+	})
+}
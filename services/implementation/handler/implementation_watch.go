@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"codev42-implementation/proto/implementation"
+	"codev42-implementation/queue"
+)
+
+const (
+	// 이벤트는 Job 저장소에 기록되므로, 어느 레플리카가 처리 중이든 저장소를 주기적으로 조회하여 전달
+	watchPollInterval = 500 * time.Millisecond
+	watchBatchSize    = 100
+)
+
+// WatchImplementation Job 진행 이벤트를 완료/실패 이벤트까지 스트리밍
+func (h *ImplementationHandler) WatchImplementation(req *implementation.WatchImplementationRequest, stream implementation.ImplementationService_WatchImplementationServer) error {
+	ctx := stream.Context()
+	if _, err := h.jobStore.GetJob(ctx, req.JobId); err != nil {
+		return fmt.Errorf("failed to get job: %v", err)
+	}
+
+	afterSeq := req.AfterSeq
+	for {
+		events, err := h.jobStore.ListJobEvents(ctx, req.JobId, afterSeq, watchBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := stream.Send(convertJobEventToPB(event)); err != nil {
+				return err
+			}
+			afterSeq = event.Seq
+			if event.Type.IsTerminal() {
+				return nil
+			}
+		}
+		if len(events) == watchBatchSize {
+			continue
+		}
+
+		// 이벤트 기록 전에 끝난 Job(이전 버전에서 처리 등)은 상태로 마지막 이벤트를 만들어 종료
+		job, err := h.jobStore.GetJob(ctx, req.JobId)
+		if err != nil {
+			return fmt.Errorf("failed to get job: %v", err)
+		}
+		if job.Status.IsFinished() {
+			remaining, err := h.jobStore.ListJobEvents(ctx, req.JobId, afterSeq, watchBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(remaining) > 0 {
+				continue
+			}
+			return stream.Send(terminalEventFromJob(job, afterSeq))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+func convertJobEventToPB(event queue.JobEvent) *implementation.ImplementationEvent {
+	return &implementation.ImplementationEvent{
+		Seq:         event.Seq,
+		JobId:       event.JobID,
+		Type:        string(event.Type),
+		Stage:       event.Stage,
+		Progress:    event.Progress,
+		Message:     event.Message,
+		PlanIndex:   event.PlanIndex,
+		PlanId:      event.PlanID,
+		Path:        event.Path,
+		DiagramType: event.Diagram,
+		CreatedAt:   event.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func terminalEventFromJob(job *queue.Job, lastSeq int64) *implementation.ImplementationEvent {
+	event := &implementation.ImplementationEvent{
+		Seq:      lastSeq,
+		JobId:    job.ID,
+		Type:     string(queue.JobEventCompleted),
+		Progress: job.Progress,
+		Message:  job.CurrentStep,
+	}
+	switch job.Status {
+	case queue.JobStatusFailed:
+		event.Type = string(queue.JobEventFailed)
+		event.Message = job.Error
+	case queue.JobStatusPartiallyCompleted:
+		event.Type = string(queue.JobEventPartiallyCompleted)
+	case queue.JobStatusCancelled:
+		event.Type = string(queue.JobEventCancelled)
+		event.Message = job.Error
+	}
+	if job.CompletedAt != nil {
+		event.CreatedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+	return event
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
+	"net/url"
+	"time"
 
 	"codev42-implementation/configs"
 	"codev42-implementation/handler"
@@ -11,6 +14,9 @@ import (
 	"codev42-implementation/proto/diagram"
 	"codev42-implementation/proto/implementation"
 	"codev42-implementation/proto/plan"
+	"codev42-implementation/queue"
+	"codev42-implementation/storage"
+	"codev42-llm/client"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -25,6 +31,119 @@ func main() {
 
 	log.Printf("Implementation Service configuration loaded")
 
+	providerConfig := client.ProviderConfig{
+		Provider:       config.LLMProvider,
+		APIKey:         config.OpenAiKey,
+		BaseURL:        config.OpenAIBaseURL,
+		ChatModel:      config.LLMChatModel,
+		EmbeddingModel: config.LLMEmbeddingModel,
+		FakeScriptPath: config.FakeLLMScript,
+
+		TenantAllowedHosts: client.ParseAllowedHosts(config.TenantLLMAllowedHosts),
+	}
+	llmProvider, err := client.NewLLMProvider(providerConfig)
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+	log.Printf("Using %s LLM provider", config.LLMProvider)
+
+	// Job 저장소, 코드 캐시, 구현 실행 기록 저장소, 파이프라인 정의 저장소 생성 (모두 Job 저장소와 같은 DB를 사용)
+	var jobStore queue.JobStore
+	var codeCache queue.CodeCache
+	var implementationStore queue.ImplementationStore
+	var pipelineStore queue.PipelineStore
+	switch config.JobStore {
+	case "memory":
+		log.Printf("Using in-memory job store")
+		jobStore = queue.NewJobQueue()
+		codeCache = queue.NewMemoryCodeCache()
+		implementationStore = queue.NewMemoryImplementationStore()
+		pipelineStore = queue.NewMemoryPipelineStore()
+	case "mysql":
+		log.Printf("Connecting to MySQL: %s@%s:%s/%s",
+			config.MySQLUser,
+			config.MySQLHost,
+			config.MySQLPort,
+			config.MySQLDB,
+		)
+
+		rdbConnection, err := storage.NewRDBConnection(mysqlDSN(config))
+		if err != nil {
+			log.Fatalf("Failed to connect to MySQL: %v", err)
+		}
+		defer rdbConnection.Close()
+
+		jobStore = queue.NewMySQLJobStore(rdbConnection)
+		codeCache = queue.NewMySQLCodeCache(rdbConnection)
+		implementationStore = queue.NewMySQLImplementationStore(rdbConnection)
+		pipelineStore = queue.NewMySQLPipelineStore(rdbConnection)
+	case "sqlite":
+		// 여러 레플리카를 로컬에서 띄워볼 때 공유 저장소로 사용
+		log.Printf("Using SQLite job store at %s", config.SQLitePath)
+		rdbConnection, err := storage.NewSQLiteConnection(config.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite: %v", err)
+		}
+		defer rdbConnection.Close()
+
+		sqliteStore := queue.NewMySQLJobStore(rdbConnection)
+		if err := sqliteStore.AutoMigrate(); err != nil {
+			log.Fatalf("Failed to migrate SQLite job store: %v", err)
+		}
+		jobStore = sqliteStore
+
+		sqliteCache := queue.NewMySQLCodeCache(rdbConnection)
+		if err := sqliteCache.AutoMigrate(); err != nil {
+			log.Fatalf("Failed to migrate SQLite code cache: %v", err)
+		}
+		codeCache = sqliteCache
+
+		sqliteImplementations := queue.NewMySQLImplementationStore(rdbConnection)
+		if err := sqliteImplementations.AutoMigrate(); err != nil {
+			log.Fatalf("Failed to migrate SQLite implementation store: %v", err)
+		}
+		implementationStore = sqliteImplementations
+
+		sqlitePipelines := queue.NewMySQLPipelineStore(rdbConnection)
+		if err := sqlitePipelines.AutoMigrate(); err != nil {
+			log.Fatalf("Failed to migrate SQLite pipeline store: %v", err)
+		}
+		pipelineStore = sqlitePipelines
+	default:
+		log.Fatalf("Unknown job store: %s", config.JobStore)
+	}
+
+	// 테넌트별 자격 증명 (x-tenant-id metadata 또는 Job의 TenantID로 선택)
+	var credentialResolver client.CredentialResolver
+	if config.CredentialEncryptionKey != "" {
+		cipher, err := client.NewCipher(config.CredentialEncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to create credential cipher: %v", err)
+		}
+		rdbConnection, err := storage.NewRDBConnection(mysqlDSN(config))
+		if err != nil {
+			log.Fatalf("Failed to connect to MySQL: %v", err)
+		}
+		defer rdbConnection.Close()
+
+		credentialResolver = client.NewCredentialStore(rdbConnection.DB, cipher)
+		log.Printf("Per-tenant LLM credentials enabled")
+	}
+	// 서비스 전체와 테넌트별 호출 한도를 넘은 LLM 호출은 대기열에서 차례를 기다림
+	limiter := client.NewLimiter(
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMMaxConcurrency,
+			RequestsPerMinute: config.LLMRequestsPerMinute,
+			TokensPerMinute:   config.LLMTokensPerMinute,
+		},
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMTenantMaxConcurrency,
+			RequestsPerMinute: config.LLMTenantRequestsPerMinute,
+			TokensPerMinute:   config.LLMTenantTokensPerMinute,
+		},
+	)
+	llm := client.NewRateLimitedProvider(client.NewProviderPool(llmProvider, credentialResolver, providerConfig), limiter)
+
 	// 다른 서비스로의 gRPC 클라이언트 연결 생성
 	log.Printf("Connecting to Plan Service at %s", config.PlanServiceAddr)
 	planConn, err := grpc.NewClient(
@@ -74,9 +193,39 @@ func main() {
 		planClient,
 		diagramClient,
 		analyzerClient,
+		jobStore,
+		codeCache,
+		implementationStore,
+		pipelineStore,
+		llm,
 	)
 	implementation.RegisterImplementationServiceServer(grpcServer, implementationHandler)
 
+	// lease 기반 Job 워커 실행
+	workerCtx, cancelWorker := context.WithCancel(context.Background())
+	defer cancelWorker()
+
+	worker := queue.NewWorker(jobStore, queue.WorkerOptions{
+		Owner:         config.WorkerID,
+		Concurrency:   config.WorkerConcurrency,
+		LeaseDuration: time.Duration(config.JobLeaseSeconds) * time.Second,
+		PollInterval:  time.Duration(config.JobPollSeconds) * time.Second,
+		MaxAttempts:   int32(config.JobMaxAttempts),
+	}, implementationHandler.ProcessJob)
+	go worker.Run(workerCtx)
+	log.Printf("Job worker %s started (concurrency: %d)", config.WorkerID, config.WorkerConcurrency)
+
+	// 보관 기간이 지난 끝난 Job 정리 (실행 결과는 구현 실행 기록에 남음)
+	if config.JobTTLSeconds > 0 {
+		reaper := queue.NewReaper(
+			jobStore,
+			time.Duration(config.JobTTLSeconds)*time.Second,
+			time.Duration(config.JobReapIntervalSeconds)*time.Second,
+		)
+		go reaper.Run(workerCtx)
+		log.Printf("Job reaper started (ttl: %ds)", config.JobTTLSeconds)
+	}
+
 	reflection.Register(grpcServer)
 
 	log.Printf("Implementation Service starting on port %s", config.GRPCPort)
@@ -84,3 +233,14 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+func mysqlDSN(config *configs.Config) string {
+	return fmt.Sprintf(
+		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		config.MySQLUser,
+		url.QueryEscape(config.MySQLPassword),
+		config.MySQLHost,
+		config.MySQLPort,
+		config.MySQLDB,
+	)
+}
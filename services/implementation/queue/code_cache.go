@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// CodeCacheKey identifies generated code by the plan's content hash.
+// Entries are scoped to a tenant so that code is never shared across tenants.
+type CodeCacheKey struct {
+	TenantID    string
+	Language    string
+	ContentHash string
+}
+
+func (k CodeCacheKey) normalized() CodeCacheKey {
+	k.Language = strings.ToLower(strings.TrimSpace(k.Language))
+	return k
+}
+
+// CodeCache stores implemented files so that unchanged plans are not generated again
+type CodeCache interface {
+	// GetCode returns the cached file for the key, or nil if there is none
+	GetCode(ctx context.Context, key CodeCacheKey) (*GeneratedFile, error)
+
+	// PutCode stores (or replaces) the file for the key
+	PutCode(ctx context.Context, key CodeCacheKey, file GeneratedFile) error
+}
+
+// MemoryCodeCache is an in-memory CodeCache used with the in-memory job store
+type MemoryCodeCache struct {
+	files map[CodeCacheKey]GeneratedFile
+	mu    sync.RWMutex
+}
+
+// NewMemoryCodeCache creates an empty in-memory code cache
+func NewMemoryCodeCache() *MemoryCodeCache {
+	return &MemoryCodeCache{
+		files: make(map[CodeCacheKey]GeneratedFile),
+	}
+}
+
+func (c *MemoryCodeCache) GetCode(ctx context.Context, key CodeCacheKey) (*GeneratedFile, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	file, ok := c.files[key.normalized()]
+	if !ok {
+		return nil, nil
+	}
+	return &file, nil
+}
+
+func (c *MemoryCodeCache) PutCode(ctx context.Context, key CodeCacheKey, file GeneratedFile) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.files[key.normalized()] = file
+	return nil
+}
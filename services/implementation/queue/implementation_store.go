@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Implementation is one recorded run of a dev plan's implementation.
+// A job records a new run each time it completes, including after RetryJob.
+type Implementation struct {
+	ID        int64
+	DevPlanID int64
+	JobID     string
+	TenantID  string
+	Status    JobStatus // completed or partially_completed
+	Language  string
+	Provider  string // LLM provider used for the run
+	Model     string // LLM chat model used for the run
+	FileCount int32
+	Result    *JobResult // code, explained segments and diagrams (nil in ListImplementations)
+	CreatedAt time.Time
+}
+
+// ImplementationStore keeps the run history of each dev plan
+type ImplementationStore interface {
+	// CreateImplementation records a run and sets its ID and CreatedAt
+	CreateImplementation(ctx context.Context, implementation *Implementation) error
+
+	// ListImplementations returns the runs of a dev plan, oldest first, without their results
+	ListImplementations(ctx context.Context, devPlanID int64) ([]Implementation, error)
+
+	// GetImplementation retrieves a run with its result
+	GetImplementation(ctx context.Context, id int64) (*Implementation, error)
+
+	// DeleteImplementationsByDevPlanIDs deletes every run of the given dev plans
+	DeleteImplementationsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error)
+}
+
+// MemoryImplementationStore is an in-memory ImplementationStore used with the in-memory job store
+type MemoryImplementationStore struct {
+	implementations []Implementation
+	lastID          int64
+	mu              sync.RWMutex
+}
+
+// NewMemoryImplementationStore creates an empty in-memory implementation store
+func NewMemoryImplementationStore() *MemoryImplementationStore {
+	return &MemoryImplementationStore{}
+}
+
+func (s *MemoryImplementationStore) CreateImplementation(ctx context.Context, implementation *Implementation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	implementation.ID = s.lastID
+	implementation.CreatedAt = time.Now()
+	s.implementations = append(s.implementations, *implementation)
+	return nil
+}
+
+func (s *MemoryImplementationStore) ListImplementations(ctx context.Context, devPlanID int64) ([]Implementation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var implementations []Implementation
+	for _, implementation := range s.implementations {
+		if implementation.DevPlanID == devPlanID {
+			implementation.Result = nil
+			implementations = append(implementations, implementation)
+		}
+	}
+	return implementations, nil
+}
+
+func (s *MemoryImplementationStore) GetImplementation(ctx context.Context, id int64) (*Implementation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, implementation := range s.implementations {
+		if implementation.ID == id {
+			return &implementation, nil
+		}
+	}
+	return nil, fmt.Errorf("implementation not found: %d", id)
+}
+
+func (s *MemoryImplementationStore) DeleteImplementationsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets := make(map[int64]bool, len(devPlanIDs))
+	for _, id := range devPlanIDs {
+		targets[id] = true
+	}
+
+	kept := s.implementations[:0]
+	var deleted int64
+	for _, implementation := range s.implementations {
+		if targets[implementation.DevPlanID] {
+			deleted++
+			continue
+		}
+		kept = append(kept, implementation)
+	}
+	s.implementations = kept
+	return deleted, nil
+}
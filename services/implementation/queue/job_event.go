@@ -0,0 +1,49 @@
+package queue
+
+import "time"
+
+// JobEventType is the kind of progress event emitted while a job runs
+type JobEventType string
+
+const (
+	JobEventStageStarted     JobEventType = "stage_started"
+	JobEventStageFinished    JobEventType = "stage_finished"
+	JobEventPlanGenerated    JobEventType = "plan_generated"
+	JobEventDiagramGenerated JobEventType = "diagram_generated"
+	JobEventWarning          JobEventType = "warning"
+	JobEventCompleted        JobEventType = "completed"
+	JobEventFailed           JobEventType = "failed"
+	JobEventCancelled        JobEventType = "cancelled"
+
+	JobEventPartiallyCompleted JobEventType = "partially_completed"
+
+	// Approval gates: the job stops until ApproveJob resumes it or RejectJob regenerates its plans
+	JobEventAwaitingApproval JobEventType = "awaiting_approval"
+	JobEventApproved         JobEventType = "approved"
+	JobEventRejected         JobEventType = "rejected"
+)
+
+// IsTerminal reports whether no further events follow this one
+func (t JobEventType) IsTerminal() bool {
+	switch t {
+	case JobEventCompleted, JobEventPartiallyCompleted, JobEventFailed, JobEventCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// JobEvent is one progress event of a job. Seq increases per job in emission order.
+type JobEvent struct {
+	Seq       int64
+	JobID     string
+	Type      JobEventType
+	Stage     string // pipeline stage (fetch_plan, generate_code, ...)
+	Progress  int32
+	Message   string
+	PlanIndex int32 // plan_generated: position in implementation order
+	PlanID    int64
+	Path      string // plan_generated: generated file path
+	Diagram   string // diagram_generated: diagram type
+	CreatedAt time.Time
+}
@@ -0,0 +1,115 @@
+package queue
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultJobPageSize = 50
+	maxJobPageSize     = 200
+)
+
+// finishedJobStatuses are the statuses removed by DeleteFinishedJobs
+var finishedJobStatuses = []JobStatus{JobStatusCompleted, JobStatusPartiallyCompleted, JobStatusFailed, JobStatusCancelled}
+
+// JobFilter selects jobs in ListJobs. Zero values match every job.
+type JobFilter struct {
+	DevPlanID     int64
+	Statuses      []JobStatus
+	CreatedAfter  time.Time // inclusive
+	CreatedBefore time.Time // exclusive
+
+	PageSize  int    // defaults to 50, at most 200
+	PageToken string // NextPageToken of the previous page
+}
+
+func (f JobFilter) pageSize() int {
+	switch {
+	case f.PageSize <= 0:
+		return defaultJobPageSize
+	case f.PageSize > maxJobPageSize:
+		return maxJobPageSize
+	default:
+		return f.PageSize
+	}
+}
+
+// matches reports whether a job passes the filter, ignoring pagination
+func (f JobFilter) matches(job *Job) bool {
+	if f.DevPlanID != 0 && job.DevPlanID != f.DevPlanID {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, status := range f.Statuses {
+			if job.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() && job.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !job.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// jobCursor is the position after the last job of a page (jobs are ordered by CreatedAt, then ID, descending)
+type jobCursor struct {
+	createdAt time.Time
+	id        string
+}
+
+// after reports whether a job comes after the cursor in listing order
+func (c *jobCursor) after(job *Job) bool {
+	if c == nil {
+		return true
+	}
+	if !job.CreatedAt.Equal(c.createdAt) {
+		return job.CreatedAt.Before(c.createdAt)
+	}
+	return job.ID < c.id
+}
+
+func encodePageToken(job *Job) string {
+	raw := strconv.FormatInt(job.CreatedAt.UnixNano(), 10) + "|" + job.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (*jobCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, ErrInvalidPageToken
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	return &jobCursor{createdAt: time.Unix(0, unixNano), id: id}, nil
+}
+
+// nextPage trims jobs fetched with one extra row to the page size and returns the token of the next page
+func nextPage(jobs []Job, pageSize int) ([]Job, string) {
+	if len(jobs) <= pageSize {
+		return jobs, ""
+	}
+	jobs = jobs[:pageSize]
+	return jobs, encodePageToken(&jobs[pageSize-1])
+}
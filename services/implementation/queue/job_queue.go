@@ -1,7 +1,9 @@
 package queue
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -16,76 +18,219 @@ const (
 	JobStatusProcessing JobStatus = "processing"
 	JobStatusCompleted  JobStatus = "completed"
 	JobStatusFailed     JobStatus = "failed"
+	JobStatusCancelled  JobStatus = "cancelled"
+
+	// JobStatusPartiallyCompleted means some plans failed; they can be retried with RetryJob
+	JobStatusPartiallyCompleted JobStatus = "partially_completed"
+
+	// JobStatusAwaitingApproval means the job stopped at an approval gate and holds its
+	// intermediate result until ApproveJob or RejectJob requeues it
+	JobStatusAwaitingApproval JobStatus = "awaiting_approval"
 )
 
+// Approval gates are the checkpoints where a job can wait for a reviewer
+const (
+	ApprovalGateCode   = "code"   // after code generation and tests, before diagrams and analysis
+	ApprovalGateResult = "result" // after analysis, before the job completes
+)
+
+// IsApprovalGate reports whether gate is a known approval gate
+func IsApprovalGate(gate string) bool {
+	return gate == ApprovalGateCode || gate == ApprovalGateResult
+}
+
+// IsFinished reports whether the job reached a final status
+func (s JobStatus) IsFinished() bool {
+	switch s {
+	case JobStatusCompleted, JobStatusPartiallyCompleted, JobStatusFailed, JobStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable reports whether plans of a job in this status can be retried.
+// Completed jobs can still have plans whose code does not conform to the plan
+func (s JobStatus) IsRetryable() bool {
+	return s == JobStatusPartiallyCompleted || s == JobStatusFailed || s == JobStatusCompleted
+}
+
 // Job represents an implementation job
 type Job struct {
-	ID           string
-	DevPlanID    int64
-	Status       JobStatus
-	Progress     int32
-	CurrentStep  string
-	Result       *JobResult
-	Error        string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	CompletedAt  *time.Time
+	ID          string
+	DevPlanID   int64
+	TenantID    string // LLM 자격 증명을 선택할 테넌트 (없으면 기본 키)
+	Status      JobStatus
+	Progress    int32
+	CurrentStep string
+	Result      *JobResult
+	Error       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+
+	// 멀티 레플리카 처리를 위한 lease 정보
+	LeaseOwner     string
+	LeaseExpiresAt *time.Time
+	Attempts       int32
+
+	// RetryJob으로 다시 구현할 계획 ID (비어 있으면 처음부터 구현)
+	RetryPlanIDs []int64
+
+	// 승인 게이트: 멈출 게이트, 승인된 게이트 (코드를 다시 생성하면 초기화), 현재 승인을 기다리는 게이트
+	ApprovalGates []string
+	PassedGates   []string
+	PendingGate   string
+
+	// RejectJob의 사유로, 다시 구현하는 계획의 프롬프트에 함께 전달
+	ReviewFeedback string
+}
+
+// IsLeased reports whether a worker may still be processing the job
+func (j *Job) IsLeased(now time.Time) bool {
+	return j.LeaseOwner != "" && j.LeaseExpiresAt != nil && j.LeaseExpiresAt.After(now)
+}
+
+// HasPassedGate reports whether a reviewer approved the job at gate
+func (j *Job) HasPassedGate(gate string) bool {
+	return containsGate(j.PassedGates, gate)
+}
+
+// RequiresApproval reports whether the job must stop at gate for a reviewer
+func (j *Job) RequiresApproval(gate string) bool {
+	return containsGate(j.ApprovalGates, gate) && !j.HasPassedGate(gate)
+}
+
+func containsGate(gates []string, gate string) bool {
+	for _, g := range gates {
+		if g == gate {
+			return true
+		}
+	}
+	return false
 }
 
 // JobResult stores the implementation result
 type JobResult struct {
-	Code              string
-	Diagrams          []Diagram
-	ExplainedSegments []ExplainedSegment
+	Files    []GeneratedFile `json:"files"`
+	Diagrams []Diagram       `json:"diagrams"` // generated over the combined set of files
+}
+
+// PlanStatus is the outcome of implementing a single plan
+type PlanStatus string
+
+const (
+	PlanStatusCompleted PlanStatus = "completed"
+	PlanStatusFailed    PlanStatus = "failed"
+	PlanStatusSkipped   PlanStatus = "skipped" // a dependency was not implemented
+)
+
+// GeneratedFile is one implemented file, produced from a single plan
+type GeneratedFile struct {
+	Path              string             `json:"path"`
+	Language          string             `json:"language"`
+	PlanID            int64              `json:"planId"`
+	Status            PlanStatus         `json:"status"`
+	Error             string             `json:"error,omitempty"`
+	Attempts          int32              `json:"attempts"`
+	ContentHash       string             `json:"contentHash,omitempty"` // hash of the plan's annotations, the code cache key
+	Cached            bool               `json:"cached,omitempty"`      // reused from the code cache instead of being generated
+	Code              string             `json:"code"`
+	ExplainedSegments []ExplainedSegment `json:"explainedSegments"` // line numbers are relative to this file
+	Diagnostics       []Diagnostic       `json:"diagnostics,omitempty"`
+	Tests             []TestResult       `json:"tests,omitempty"`
+	Conformance       *ConformanceReport `json:"conformance,omitempty"` // nil if the language has no conformance checker
+}
+
+// ConformanceReport compares the generated code with the planned signatures
+type ConformanceReport struct {
+	Conforms bool               `json:"conforms"`
+	Issues   []ConformanceIssue `json:"issues,omitempty"`
+}
+
+// ConformanceIssue is one mismatch between the plan and the generated code
+type ConformanceIssue struct {
+	Kind     string `json:"kind"`   // missing, receiver_mismatch, params_mismatch, returns_mismatch, unplanned_symbol
+	Symbol   string `json:"symbol"` // ClassName.Method, function or type name
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Line     int32  `json:"line,omitempty"`
+}
+
+// NeedsRegeneration reports whether the plan of this file should be implemented again
+func (f GeneratedFile) NeedsRegeneration() bool {
+	return f.Status != PlanStatusCompleted || (f.Conformance != nil && !f.Conformance.Conforms)
+}
+
+// TestResult is the outcome of one generated unit test (or subtest)
+type TestResult struct {
+	Name       string `json:"name"`
+	Annotation string `json:"annotation"` // function under test
+	Passed     bool   `json:"passed"`
+	Output     string `json:"output,omitempty"` // test output on failure
+}
+
+// Diagnostic is a compile check finding left after the repair rounds
+type Diagnostic struct {
+	Line    int32  `json:"line"`
+	Column  int32  `json:"column"`
+	Message string `json:"message"`
 }
 
 // Diagram represents a mermaid diagram
 type Diagram struct {
-	Diagram string
-	Type    string
+	Diagram string `json:"diagram"`
+	Type    string `json:"type"`
 }
 
 // ExplainedSegment represents an explained code segment
 type ExplainedSegment struct {
-	StartLine   int32
-	EndLine     int32
-	Explanation string
+	StartLine   int32  `json:"startLine"`
+	EndLine     int32  `json:"endLine"`
+	Explanation string `json:"explanation"`
 }
 
-// JobQueue is an in-memory job queue (can be replaced with Redis)
+// JobQueue is an in-memory JobStore, used for tests and local runs
 type JobQueue struct {
-	jobs map[string]*Job
-	mu   sync.RWMutex
+	jobs    map[string]*Job
+	events  map[string][]JobEvent
+	lastSeq int64
+	mu      sync.RWMutex
 }
 
 // NewJobQueue creates a new job queue
 func NewJobQueue() *JobQueue {
 	return &JobQueue{
-		jobs: make(map[string]*Job),
+		jobs:   make(map[string]*Job),
+		events: make(map[string][]JobEvent),
 	}
 }
 
 // CreateJob creates a new job
-func (q *JobQueue) CreateJob(devPlanID int64) *Job {
+func (q *JobQueue) CreateJob(ctx context.Context, devPlanID int64, tenantID string, approvalGates []string) (*Job, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	job := &Job{
-		ID:          uuid.New().String(),
-		DevPlanID:   devPlanID,
-		Status:      JobStatusPending,
-		Progress:    0,
-		CurrentStep: "Initializing",
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		ID:            uuid.New().String(),
+		DevPlanID:     devPlanID,
+		TenantID:      tenantID,
+		Status:        JobStatusPending,
+		Progress:      0,
+		CurrentStep:   "Initializing",
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		ApprovalGates: append([]string{}, approvalGates...),
 	}
 
 	q.jobs[job.ID] = job
-	return job
+
+	snapshot := *job
+	return &snapshot, nil
 }
 
-// GetJob retrieves a job by ID
-func (q *JobQueue) GetJob(jobID string) (*Job, error) {
+// GetJob retrieves a snapshot of a job by ID
+func (q *JobQueue) GetJob(ctx context.Context, jobID string) (*Job, error) {
 	q.mu.RLock()
 	defer q.mu.RUnlock()
 
@@ -94,11 +239,45 @@ func (q *JobQueue) GetJob(jobID string) (*Job, error) {
 		return nil, fmt.Errorf("job not found: %s", jobID)
 	}
 
-	return job, nil
+	// 워커가 갱신 중인 Job과 공유하지 않도록 복사본 반환
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// ListJobs returns snapshots of the jobs matching the filter, newest first, without their results
+func (q *JobQueue) ListJobs(ctx context.Context, filter JobFilter) ([]Job, string, error) {
+	cursor, err := decodePageToken(filter.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	q.mu.RLock()
+	var jobs []Job
+	for _, job := range q.jobs {
+		if filter.matches(job) && cursor.after(job) {
+			snapshot := *job
+			snapshot.Result = nil
+			jobs = append(jobs, snapshot)
+		}
+	}
+	q.mu.RUnlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].CreatedAt.Equal(jobs[j].CreatedAt) {
+			return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	pageSize := filter.pageSize()
+	if len(jobs) > pageSize+1 {
+		jobs = jobs[:pageSize+1]
+	}
+	jobs, next := nextPage(jobs, pageSize)
+	return jobs, next, nil
 }
 
 // UpdateJob updates a job's status and progress
-func (q *JobQueue) UpdateJob(jobID string, status JobStatus, progress int32, currentStep string) error {
+func (q *JobQueue) UpdateJob(ctx context.Context, jobID string, owner string, status JobStatus, progress int32, currentStep string) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -106,13 +285,19 @@ func (q *JobQueue) UpdateJob(jobID string, status JobStatus, progress int32, cur
 	if !exists {
 		return fmt.Errorf("job not found: %s", jobID)
 	}
+	if job.Status == JobStatusCancelled {
+		return ErrJobCancelled
+	}
+	if job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
 
 	job.Status = status
 	job.Progress = progress
 	job.CurrentStep = currentStep
 	job.UpdatedAt = time.Now()
 
-	if status == JobStatusCompleted || status == JobStatusFailed {
+	if status.IsFinished() {
 		now := time.Now()
 		job.CompletedAt = &now
 	}
@@ -121,7 +306,7 @@ func (q *JobQueue) UpdateJob(jobID string, status JobStatus, progress int32, cur
 }
 
 // SetJobResult sets the result of a completed job
-func (q *JobQueue) SetJobResult(jobID string, result *JobResult) error {
+func (q *JobQueue) SetJobResult(ctx context.Context, jobID string, owner string, result *JobResult) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -129,13 +314,19 @@ func (q *JobQueue) SetJobResult(jobID string, result *JobResult) error {
 	if !exists {
 		return fmt.Errorf("job not found: %s", jobID)
 	}
+	if job.Status == JobStatusCancelled {
+		return ErrJobCancelled
+	}
+	if job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
 
 	job.Result = result
 	return nil
 }
 
 // SetJobError sets the error for a failed job
-func (q *JobQueue) SetJobError(jobID string, err error) error {
+func (q *JobQueue) SetJobError(ctx context.Context, jobID string, owner string, err error) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -143,6 +334,12 @@ func (q *JobQueue) SetJobError(jobID string, err error) error {
 	if !exists {
 		return fmt.Errorf("job not found: %s", jobID)
 	}
+	if job.Status == JobStatusCancelled {
+		return ErrJobCancelled
+	}
+	if job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
 
 	job.Error = err.Error()
 	job.Status = JobStatusFailed
@@ -152,3 +349,329 @@ func (q *JobQueue) SetJobError(jobID string, err error) error {
 
 	return nil
 }
+
+// CancelJob marks a pending, processing or awaiting approval job as cancelled
+func (q *JobQueue) CancelJob(ctx context.Context, jobID string, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.Status.IsFinished() {
+		return ErrJobFinished
+	}
+
+	now := time.Now()
+	job.Status = JobStatusCancelled
+	job.Error = reason
+	job.CompletedAt = &now
+	job.UpdatedAt = now
+	return nil
+}
+
+// CancelJobsByDevPlanIDs cancels every active job of the given dev plans and returns the leased ones
+func (q *JobQueue) CancelJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64, reason string) ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	targets := make(map[int64]bool, len(devPlanIDs))
+	for _, id := range devPlanIDs {
+		targets[id] = true
+	}
+
+	now := time.Now()
+	var leased []string
+	for id, job := range q.jobs {
+		if !targets[job.DevPlanID] {
+			continue
+		}
+		if !job.Status.IsFinished() {
+			job.Status = JobStatusCancelled
+			job.Error = reason
+			job.CompletedAt = &now
+			job.UpdatedAt = now
+		}
+		if job.IsLeased(now) {
+			leased = append(leased, id)
+		}
+	}
+	return leased, nil
+}
+
+// RetryJob requeues a finished (not cancelled) job to regenerate the given plans
+func (q *JobQueue) RetryJob(ctx context.Context, jobID string, planIDs []int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if !job.Status.IsRetryable() {
+		return ErrJobNotRetryable
+	}
+
+	job.Status = JobStatusPending
+	job.Progress = 0
+	job.CurrentStep = "Retrying plans"
+	job.Error = ""
+	job.CompletedAt = nil
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+	job.Attempts = 0
+	job.RetryPlanIDs = append([]int64{}, planIDs...)
+	job.PassedGates = nil
+	job.ReviewFeedback = ""
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+// AwaitApproval stores the intermediate result and stops the job at gate until it is approved or rejected
+func (q *JobQueue) AwaitApproval(ctx context.Context, jobID string, owner string, gate string, result *JobResult) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.Status == JobStatusCancelled {
+		return ErrJobCancelled
+	}
+	if job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
+
+	job.Status = JobStatusAwaitingApproval
+	job.CurrentStep = awaitingApprovalStep(gate)
+	job.Result = result
+	job.PendingGate = gate
+	job.LeaseOwner = ""
+	job.LeaseExpiresAt = nil
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+// ApproveJob requeues a job waiting at an approval gate so that a worker resumes after the gate.
+// The stored result already contains the regenerated plans, so the retry state is cleared.
+func (q *JobQueue) ApproveJob(ctx context.Context, jobID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.Status != JobStatusAwaitingApproval {
+		return ErrJobNotAwaitingApproval
+	}
+
+	job.Status = JobStatusPending
+	job.CurrentStep = approvedStep(job.PendingGate)
+	job.PassedGates = append(append([]string{}, job.PassedGates...), job.PendingGate)
+	job.PendingGate = ""
+	job.Attempts = 0
+	job.RetryPlanIDs = nil
+	job.ReviewFeedback = ""
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+// RejectJob requeues a job waiting at an approval gate to regenerate planIDs with the reviewer's feedback
+func (q *JobQueue) RejectJob(ctx context.Context, jobID string, planIDs []int64, feedback string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.Status != JobStatusAwaitingApproval {
+		return ErrJobNotAwaitingApproval
+	}
+
+	job.Status = JobStatusPending
+	job.Progress = 0
+	job.CurrentStep = "Regenerating rejected plans"
+	job.PendingGate = ""
+	job.PassedGates = nil
+	job.Attempts = 0
+	job.RetryPlanIDs = append([]int64{}, planIDs...)
+	job.ReviewFeedback = feedback
+	job.UpdatedAt = time.Now()
+	return nil
+}
+
+// ClaimJob leases the oldest pending job, or a processing job whose lease expired
+func (q *JobQueue) ClaimJob(ctx context.Context, owner string, leaseDuration time.Duration, maxAttempts int32) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	candidates := make([]*Job, 0)
+	for _, job := range q.jobs {
+		if !isClaimable(job, now) {
+			continue
+		}
+		if job.Status == JobStatusProcessing && job.Attempts >= maxAttempts {
+			failLeaseExhausted(job, now)
+			continue
+		}
+		candidates = append(candidates, job)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+
+	job := candidates[0]
+	expiresAt := now.Add(leaseDuration)
+	job.Status = JobStatusProcessing
+	job.LeaseOwner = owner
+	job.LeaseExpiresAt = &expiresAt
+	job.Attempts++
+	job.UpdatedAt = now
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// RenewLease extends the lease of a job held by owner
+func (q *JobQueue) RenewLease(ctx context.Context, jobID string, owner string, leaseDuration time.Duration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.LeaseOwner != owner || job.Status != JobStatusProcessing {
+		return ErrLeaseLost
+	}
+
+	expiresAt := time.Now().Add(leaseDuration)
+	job.LeaseExpiresAt = &expiresAt
+	return nil
+}
+
+// ReleaseLease expires the lease held by owner
+func (q *JobQueue) ReleaseLease(ctx context.Context, jobID string, owner string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, exists := q.jobs[jobID]
+	if !exists {
+		return fmt.Errorf("job not found: %s", jobID)
+	}
+	if job.LeaseOwner != owner {
+		return ErrLeaseLost
+	}
+
+	now := time.Now()
+	job.LeaseExpiresAt = &now
+	return nil
+}
+
+// DeleteJobsByDevPlanIDs deletes every job of the given dev plans
+func (q *JobQueue) DeleteJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	targets := make(map[int64]bool, len(devPlanIDs))
+	for _, id := range devPlanIDs {
+		targets[id] = true
+	}
+
+	var deleted int64
+	for id, job := range q.jobs {
+		if targets[job.DevPlanID] {
+			delete(q.jobs, id)
+			delete(q.events, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteFinishedJobs deletes finished jobs (and their events) that finished before completedBefore
+func (q *JobQueue) DeleteFinishedJobs(ctx context.Context, completedBefore time.Time) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var deleted int64
+	for id, job := range q.jobs {
+		if job.Status.IsFinished() && job.CompletedAt != nil && job.CompletedAt.Before(completedBefore) {
+			delete(q.jobs, id)
+			delete(q.events, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// AppendJobEvent records a progress event
+func (q *JobQueue) AppendJobEvent(ctx context.Context, event *JobEvent) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, exists := q.jobs[event.JobID]; !exists {
+		return fmt.Errorf("job not found: %s", event.JobID)
+	}
+
+	q.lastSeq++
+	event.Seq = q.lastSeq
+	event.CreatedAt = time.Now()
+	q.events[event.JobID] = append(q.events[event.JobID], *event)
+	return nil
+}
+
+// ListJobEvents returns events of a job after afterSeq
+func (q *JobQueue) ListJobEvents(ctx context.Context, jobID string, afterSeq int64, limit int) ([]JobEvent, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	var events []JobEvent
+	for _, event := range q.events[jobID] {
+		if event.Seq <= afterSeq {
+			continue
+		}
+		events = append(events, event)
+		if len(events) == limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+func isClaimable(job *Job, now time.Time) bool {
+	switch job.Status {
+	case JobStatusPending:
+		return true
+	case JobStatusProcessing:
+		return job.LeaseExpiresAt == nil || job.LeaseExpiresAt.Before(now)
+	default:
+		return false
+	}
+}
+
+func failLeaseExhausted(job *Job, now time.Time) {
+	job.Status = JobStatusFailed
+	job.Error = fmt.Sprintf("lease expired after %d attempts", job.Attempts)
+	job.CompletedAt = &now
+	job.UpdatedAt = now
+}
+
+// awaitingApprovalStep is the CurrentStep of a job waiting at gate
+func awaitingApprovalStep(gate string) string {
+	return fmt.Sprintf("Awaiting approval (%s)", gate)
+}
+
+// approvedStep is the CurrentStep of a job requeued after being approved at gate
+func approvedStep(gate string) string {
+	return fmt.Sprintf("Approved (%s)", gate)
+}
@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLeaseLost is returned when a job's lease is no longer held by the caller
+var ErrLeaseLost = errors.New("job lease lost")
+
+// ErrJobCancelled is returned when updating a job that has been cancelled
+var ErrJobCancelled = errors.New("job cancelled")
+
+// ErrJobNotRetryable is returned when retrying plans of a job that is still running or was cancelled
+var ErrJobNotRetryable = errors.New("job is not completed, partially completed or failed")
+
+// ErrJobNotAwaitingApproval is returned when approving or rejecting a job that is not waiting at an approval gate
+var ErrJobNotAwaitingApproval = errors.New("job is not awaiting approval")
+
+// ErrInvalidPageToken is returned by ListJobs when the page token was not issued by a previous call
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrJobFinished is returned when cancelling a job that already completed, failed or was cancelled
+var ErrJobFinished = errors.New("job already finished")
+
+// JobStore persists implementation jobs and their results
+type JobStore interface {
+	// CreateJob creates a new pending job for a dev plan on behalf of a tenant.
+	// The job stops for a reviewer at each of approvalGates.
+	CreateJob(ctx context.Context, devPlanID int64, tenantID string, approvalGates []string) (*Job, error)
+
+	// GetJob retrieves a job by ID
+	GetJob(ctx context.Context, jobID string) (*Job, error)
+
+	// ListJobs returns jobs matching the filter, newest first, without their results.
+	// nextPageToken is empty on the last page.
+	ListJobs(ctx context.Context, filter JobFilter) (jobs []Job, nextPageToken string, err error)
+
+	// UpdateJob updates the status and progress of a job leased to owner.
+	// UpdateJob, SetJobResult, SetJobError and AwaitApproval return ErrJobCancelled once the job is cancelled,
+	// and ErrLeaseLost once owner no longer holds the lease (another worker may have claimed the job).
+	UpdateJob(ctx context.Context, jobID string, owner string, status JobStatus, progress int32, currentStep string) error
+
+	// SetJobResult sets the result of a completed job leased to owner
+	SetJobResult(ctx context.Context, jobID string, owner string, result *JobResult) error
+
+	// SetJobError marks a job leased to owner as failed with the given error
+	SetJobError(ctx context.Context, jobID string, owner string, err error) error
+
+	// CancelJob marks a pending, processing or awaiting approval job as cancelled, or returns ErrJobFinished.
+	// A worker still processing it loses its lease on the next heartbeat.
+	CancelJob(ctx context.Context, jobID string, reason string) error
+
+	// CancelJobsByDevPlanIDs cancels every pending, processing or awaiting approval job of the given dev plans
+	// and returns the IDs of their jobs whose lease is still held by a worker (including jobs that just finished),
+	// so that the caller can wait for those workers to stop before deleting the dev plans
+	CancelJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64, reason string) ([]string, error)
+
+	// RetryJob requeues a finished (not cancelled) job so that a worker regenerates
+	// planIDs and merges them into the stored result, or returns ErrJobNotRetryable
+	RetryJob(ctx context.Context, jobID string, planIDs []int64) error
+
+	// AwaitApproval stores the intermediate result of a job leased to owner, releases its lease and
+	// moves it to awaiting_approval at gate, or returns ErrJobCancelled or ErrLeaseLost
+	AwaitApproval(ctx context.Context, jobID string, owner string, gate string, result *JobResult) error
+
+	// ApproveJob requeues a job waiting at an approval gate and marks the gate as passed,
+	// or returns ErrJobNotAwaitingApproval
+	ApproveJob(ctx context.Context, jobID string) error
+
+	// RejectJob requeues a job waiting at an approval gate so that a worker regenerates planIDs
+	// with the reviewer's feedback; passed gates must be approved again.
+	// Returns ErrJobNotAwaitingApproval if the job is not waiting at a gate.
+	RejectJob(ctx context.Context, jobID string, planIDs []int64, feedback string) error
+
+	// ClaimJob leases the next runnable job to owner; returns nil when there is none.
+	// Processing jobs whose lease expired are claimable again until maxAttempts is reached.
+	ClaimJob(ctx context.Context, owner string, leaseDuration time.Duration, maxAttempts int32) (*Job, error)
+
+	// DeleteJobsByDevPlanIDs deletes every job (and its stored result and events) of the given dev plans
+	DeleteJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error)
+
+	// DeleteFinishedJobs deletes completed, partially completed, failed and cancelled jobs
+	// (and their events) that finished before the given time
+	DeleteFinishedJobs(ctx context.Context, completedBefore time.Time) (int64, error)
+
+	// RenewLease extends the lease of a job held by owner, or returns ErrLeaseLost
+	RenewLease(ctx context.Context, jobID string, owner string, leaseDuration time.Duration) error
+
+	// ReleaseLease expires the lease held by owner once its worker stops processing the job, so that
+	// a job still processing can be claimed again at once. Returns ErrLeaseLost if owner no longer holds it.
+	ReleaseLease(ctx context.Context, jobID string, owner string) error
+
+	// AppendJobEvent records a progress event; Seq and CreatedAt are assigned by the store
+	AppendJobEvent(ctx context.Context, event *JobEvent) error
+
+	// ListJobEvents returns up to limit events of a job with Seq greater than afterSeq, oldest first
+	ListJobEvents(ctx context.Context, jobID string, afterSeq int64, limit int) ([]JobEvent, error)
+}
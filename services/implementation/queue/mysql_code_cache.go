@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"codev42-implementation/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// codeCacheRecord is the implementation_code_cache row
+type codeCacheRecord struct {
+	TenantID    string    `gorm:"primaryKey;type:varchar(255)"`
+	Language    string    `gorm:"primaryKey;type:varchar(32)"`
+	ContentHash string    `gorm:"primaryKey;type:varchar(64)"`
+	File        []byte    `gorm:"type:json;not null"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+func (codeCacheRecord) TableName() string {
+	return "implementation_code_cache"
+}
+
+// MySQLCodeCache is a CodeCache backed by the implementation_code_cache table, shared by all replicas
+type MySQLCodeCache struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewMySQLCodeCache creates a code cache on an existing connection
+func NewMySQLCodeCache(dbConn *storage.RDBConnection) *MySQLCodeCache {
+	return &MySQLCodeCache{dbConn: dbConn}
+}
+
+func (c *MySQLCodeCache) GetCode(ctx context.Context, key CodeCacheKey) (*GeneratedFile, error) {
+	key = key.normalized()
+
+	var record codeCacheRecord
+	err := c.dbConn.DB.WithContext(ctx).
+		First(&record, "tenant_id = ? AND language = ? AND content_hash = ?", key.TenantID, key.Language, key.ContentHash).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached code: %w", err)
+	}
+
+	var file GeneratedFile
+	if err := json.Unmarshal(record.File, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cached code %s: %w", key.ContentHash, err)
+	}
+	return &file, nil
+}
+
+func (c *MySQLCodeCache) PutCode(ctx context.Context, key CodeCacheKey, file GeneratedFile) error {
+	key = key.normalized()
+
+	encoded, err := json.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode cached code %s: %w", key.ContentHash, err)
+	}
+
+	record := &codeCacheRecord{
+		TenantID:    key.TenantID,
+		Language:    key.Language,
+		ContentHash: key.ContentHash,
+		File:        encoded,
+	}
+	err = c.dbConn.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "language"}, {Name: "content_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"file", "updated_at"}),
+	}).Create(record).Error
+	if err != nil {
+		return fmt.Errorf("failed to store cached code: %w", err)
+	}
+	return nil
+}
+
+// AutoMigrate creates the implementation_code_cache table (SQLite stand-in only; MySQL uses atlas migrations)
+func (c *MySQLCodeCache) AutoMigrate() error {
+	return c.dbConn.DB.AutoMigrate(&codeCacheRecord{})
+}
@@ -0,0 +1,143 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"codev42-implementation/storage"
+
+	"gorm.io/gorm"
+)
+
+// implementationRecord is the implementations row
+type implementationRecord struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	DevPlanID int64     `gorm:"not null;index"`
+	JobID     string    `gorm:"type:varchar(36);not null;index"`
+	TenantID  string    `gorm:"type:varchar(255)"`
+	Status    string    `gorm:"type:varchar(32);not null"`
+	Language  string    `gorm:"type:varchar(255)"`
+	Provider  string    `gorm:"type:varchar(32)"`
+	Model     string    `gorm:"type:varchar(255)"`
+	FileCount int32     `gorm:"not null;default:0"`
+	Result    []byte    `gorm:"type:json"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (implementationRecord) TableName() string {
+	return "implementations"
+}
+
+func (r *implementationRecord) toImplementation() (*Implementation, error) {
+	implementation := &Implementation{
+		ID:        r.ID,
+		DevPlanID: r.DevPlanID,
+		JobID:     r.JobID,
+		TenantID:  r.TenantID,
+		Status:    JobStatus(r.Status),
+		Language:  r.Language,
+		Provider:  r.Provider,
+		Model:     r.Model,
+		FileCount: r.FileCount,
+		CreatedAt: r.CreatedAt,
+	}
+
+	if len(r.Result) > 0 {
+		var result JobResult
+		if err := json.Unmarshal(r.Result, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode result of implementation %d: %w", r.ID, err)
+		}
+		implementation.Result = &result
+	}
+	return implementation, nil
+}
+
+// MySQLImplementationStore is an ImplementationStore backed by the implementations table
+type MySQLImplementationStore struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewMySQLImplementationStore creates an implementation store on an existing connection
+func NewMySQLImplementationStore(dbConn *storage.RDBConnection) *MySQLImplementationStore {
+	return &MySQLImplementationStore{dbConn: dbConn}
+}
+
+func (s *MySQLImplementationStore) CreateImplementation(ctx context.Context, implementation *Implementation) error {
+	encoded, err := json.Marshal(implementation.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of job %s: %w", implementation.JobID, err)
+	}
+
+	record := &implementationRecord{
+		DevPlanID: implementation.DevPlanID,
+		JobID:     implementation.JobID,
+		TenantID:  implementation.TenantID,
+		Status:    string(implementation.Status),
+		Language:  implementation.Language,
+		Provider:  implementation.Provider,
+		Model:     implementation.Model,
+		FileCount: implementation.FileCount,
+		Result:    encoded,
+	}
+	if err := s.dbConn.DB.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to create implementation: %w", err)
+	}
+
+	implementation.ID = record.ID
+	implementation.CreatedAt = record.CreatedAt
+	return nil
+}
+
+func (s *MySQLImplementationStore) ListImplementations(ctx context.Context, devPlanID int64) ([]Implementation, error) {
+	var records []implementationRecord
+	err := s.dbConn.DB.WithContext(ctx).
+		Omit("result").
+		Where("dev_plan_id = ?", devPlanID).
+		Order("id ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list implementations: %w", err)
+	}
+
+	implementations := make([]Implementation, 0, len(records))
+	for i := range records {
+		implementation, err := records[i].toImplementation()
+		if err != nil {
+			return nil, err
+		}
+		implementations = append(implementations, *implementation)
+	}
+	return implementations, nil
+}
+
+func (s *MySQLImplementationStore) GetImplementation(ctx context.Context, id int64) (*Implementation, error) {
+	var record implementationRecord
+	err := s.dbConn.DB.WithContext(ctx).First(&record, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("implementation not found: %d", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get implementation: %w", err)
+	}
+	return record.toImplementation()
+}
+
+func (s *MySQLImplementationStore) DeleteImplementationsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error) {
+	if len(devPlanIDs) == 0 {
+		return 0, nil
+	}
+
+	res := s.dbConn.DB.WithContext(ctx).Where("dev_plan_id IN ?", devPlanIDs).Delete(&implementationRecord{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to delete implementations: %w", res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+// AutoMigrate creates the implementations table (SQLite stand-in only; MySQL uses atlas migrations)
+func (s *MySQLImplementationStore) AutoMigrate() error {
+	return s.dbConn.DB.AutoMigrate(&implementationRecord{})
+}
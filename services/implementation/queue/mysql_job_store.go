@@ -0,0 +1,653 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"codev42-implementation/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// jobRecord is the implementation_jobs row
+type jobRecord struct {
+	ID          string     `gorm:"primaryKey;type:varchar(36)"`
+	DevPlanID   int64      `gorm:"not null;index"`
+	TenantID    string     `gorm:"type:varchar(255)"`
+	Status      string     `gorm:"type:varchar(32);not null;index"`
+	Progress    int32      `gorm:"not null;default:0"`
+	CurrentStep string     `gorm:"type:varchar(255)"`
+	Result      []byte     `gorm:"type:json"`
+	Error       string     `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime;index"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+	CompletedAt *time.Time `gorm:"index"`
+
+	LeaseOwner     string `gorm:"type:varchar(255)"`
+	LeaseExpiresAt *time.Time
+	Attempts       int32 `gorm:"not null;default:0"`
+
+	RetryPlanIDs []byte `gorm:"type:json"`
+
+	ApprovalGates  []byte `gorm:"type:json"`
+	PassedGates    []byte `gorm:"type:json"`
+	PendingGate    string `gorm:"type:varchar(32)"`
+	ReviewFeedback string `gorm:"type:text"`
+}
+
+func (jobRecord) TableName() string {
+	return "implementation_jobs"
+}
+
+// jobEventRecord is the implementation_job_events row
+type jobEventRecord struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement"`
+	JobID     string    `gorm:"type:varchar(36);not null;index:idx_implementation_job_events_job"`
+	Type      string    `gorm:"type:varchar(32);not null"`
+	Stage     string    `gorm:"type:varchar(64)"`
+	Progress  int32     `gorm:"not null;default:0"`
+	Message   string    `gorm:"type:text"`
+	PlanIndex int32     `gorm:"not null;default:0"`
+	PlanID    int64     `gorm:"not null;default:0"`
+	Path      string    `gorm:"type:varchar(255)"`
+	Diagram   string    `gorm:"type:varchar(64)"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (jobEventRecord) TableName() string {
+	return "implementation_job_events"
+}
+
+func (r *jobEventRecord) toJobEvent() JobEvent {
+	return JobEvent{
+		Seq:       r.ID,
+		JobID:     r.JobID,
+		Type:      JobEventType(r.Type),
+		Stage:     r.Stage,
+		Progress:  r.Progress,
+		Message:   r.Message,
+		PlanIndex: r.PlanIndex,
+		PlanID:    r.PlanID,
+		Path:      r.Path,
+		Diagram:   r.Diagram,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *jobRecord) toJob() (*Job, error) {
+	job := &Job{
+		ID:          r.ID,
+		DevPlanID:   r.DevPlanID,
+		TenantID:    r.TenantID,
+		Status:      JobStatus(r.Status),
+		Progress:    r.Progress,
+		CurrentStep: r.CurrentStep,
+		Error:       r.Error,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+		CompletedAt: r.CompletedAt,
+
+		LeaseOwner:     r.LeaseOwner,
+		LeaseExpiresAt: r.LeaseExpiresAt,
+		Attempts:       r.Attempts,
+
+		PendingGate:    r.PendingGate,
+		ReviewFeedback: r.ReviewFeedback,
+	}
+
+	if len(r.RetryPlanIDs) > 0 {
+		if err := json.Unmarshal(r.RetryPlanIDs, &job.RetryPlanIDs); err != nil {
+			return nil, fmt.Errorf("failed to decode retry plans of job %s: %w", r.ID, err)
+		}
+	}
+
+	if len(r.ApprovalGates) > 0 {
+		if err := json.Unmarshal(r.ApprovalGates, &job.ApprovalGates); err != nil {
+			return nil, fmt.Errorf("failed to decode approval gates of job %s: %w", r.ID, err)
+		}
+	}
+	if len(r.PassedGates) > 0 {
+		if err := json.Unmarshal(r.PassedGates, &job.PassedGates); err != nil {
+			return nil, fmt.Errorf("failed to decode passed gates of job %s: %w", r.ID, err)
+		}
+	}
+
+	if len(r.Result) > 0 {
+		var result JobResult
+		if err := json.Unmarshal(r.Result, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode result of job %s: %w", r.ID, err)
+		}
+		job.Result = &result
+	}
+
+	return job, nil
+}
+
+// MySQLJobStore is a JobStore backed by the implementation_jobs table.
+// It only relies on conditional UPDATEs for leasing, so SQLite works as a local stand-in.
+type MySQLJobStore struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewMySQLJobStore creates a new MySQL job store
+func NewMySQLJobStore(dbConn *storage.RDBConnection) *MySQLJobStore {
+	return &MySQLJobStore{dbConn: dbConn}
+}
+
+// CreateJob creates a new job
+func (s *MySQLJobStore) CreateJob(ctx context.Context, devPlanID int64, tenantID string, approvalGates []string) (*Job, error) {
+	record := &jobRecord{
+		ID:          uuid.New().String(),
+		DevPlanID:   devPlanID,
+		TenantID:    tenantID,
+		Status:      string(JobStatusPending),
+		Progress:    0,
+		CurrentStep: "Initializing",
+	}
+	if len(approvalGates) > 0 {
+		encoded, err := json.Marshal(approvalGates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode approval gates: %w", err)
+		}
+		record.ApprovalGates = encoded
+	}
+
+	if err := s.dbConn.DB.WithContext(ctx).Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	return record.toJob()
+}
+
+// GetJob retrieves a job by ID
+func (s *MySQLJobStore) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	var record jobRecord
+	err := s.dbConn.DB.WithContext(ctx).
+		Where("id = ?", jobID).
+		First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("job not found: %s", jobID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return record.toJob()
+}
+
+// ListJobs returns jobs matching the filter, newest first, without their results
+func (s *MySQLJobStore) ListJobs(ctx context.Context, filter JobFilter) ([]Job, string, error) {
+	cursor, err := decodePageToken(filter.PageToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := s.dbConn.DB.WithContext(ctx).Model(&jobRecord{}).Omit("result")
+	if filter.DevPlanID != 0 {
+		query = query.Where("dev_plan_id = ?", filter.DevPlanID)
+	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		query = query.Where("status IN ?", statuses)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedAfter)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedBefore)
+	}
+	if cursor != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.createdAt, cursor.createdAt, cursor.id)
+	}
+
+	pageSize := filter.pageSize()
+	var records []jobRecord
+	err = query.Order("created_at DESC, id DESC").Limit(pageSize + 1).Find(&records).Error
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]Job, 0, len(records))
+	for i := range records {
+		job, err := records[i].toJob()
+		if err != nil {
+			return nil, "", err
+		}
+		jobs = append(jobs, *job)
+	}
+	jobs, next := nextPage(jobs, pageSize)
+	return jobs, next, nil
+}
+
+// UpdateJob updates a job's status and progress
+func (s *MySQLJobStore) UpdateJob(ctx context.Context, jobID string, owner string, status JobStatus, progress int32, currentStep string) error {
+	updates := map[string]interface{}{
+		"status":       string(status),
+		"progress":     progress,
+		"current_step": currentStep,
+	}
+	if status.IsFinished() {
+		updates["completed_at"] = time.Now()
+	}
+
+	return s.update(ctx, jobID, owner, updates)
+}
+
+// SetJobResult sets the result of a completed job
+func (s *MySQLJobStore) SetJobResult(ctx context.Context, jobID string, owner string, result *JobResult) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of job %s: %w", jobID, err)
+	}
+
+	return s.update(ctx, jobID, owner, map[string]interface{}{
+		"result": encoded,
+	})
+}
+
+// SetJobError sets the error for a failed job
+func (s *MySQLJobStore) SetJobError(ctx context.Context, jobID string, owner string, jobErr error) error {
+	return s.update(ctx, jobID, owner, map[string]interface{}{
+		"status":       string(JobStatusFailed),
+		"error":        jobErr.Error(),
+		"completed_at": time.Now(),
+	})
+}
+
+// CancelJob marks a pending, processing or awaiting approval job as cancelled
+func (s *MySQLJobStore) CancelJob(ctx context.Context, jobID string, reason string) error {
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND status IN ?", jobID, []string{string(JobStatusPending), string(JobStatusProcessing), string(JobStatusAwaitingApproval)}).
+		Updates(map[string]interface{}{
+			"status":       string(JobStatusCancelled),
+			"error":        reason,
+			"completed_at": time.Now(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := s.GetJob(ctx, jobID); err != nil {
+			return err
+		}
+		return ErrJobFinished
+	}
+	return nil
+}
+
+// CancelJobsByDevPlanIDs cancels every active job of the given dev plans and returns the leased ones
+func (s *MySQLJobStore) CancelJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64, reason string) ([]string, error) {
+	if len(devPlanIDs) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var leased []string
+	err := s.dbConn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&jobRecord{}).
+			Where("dev_plan_id IN ? AND status IN ?", devPlanIDs, []string{string(JobStatusPending), string(JobStatusProcessing), string(JobStatusAwaitingApproval)}).
+			Updates(map[string]interface{}{
+				"status":       string(JobStatusCancelled),
+				"error":        reason,
+				"completed_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&jobRecord{}).
+			Where("dev_plan_id IN ? AND lease_owner <> '' AND lease_expires_at > ?", devPlanIDs, now).
+			Pluck("id", &leased).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel jobs: %w", err)
+	}
+	return leased, nil
+}
+
+// RetryJob requeues a partially completed or failed job to regenerate the given plans
+func (s *MySQLJobStore) RetryJob(ctx context.Context, jobID string, planIDs []int64) error {
+	encoded, err := json.Marshal(planIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode retry plans of job %s: %w", jobID, err)
+	}
+
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND status IN ?", jobID, []string{string(JobStatusPartiallyCompleted), string(JobStatusFailed), string(JobStatusCompleted)}).
+		Updates(map[string]interface{}{
+			"status":           string(JobStatusPending),
+			"progress":         0,
+			"current_step":     "Retrying plans",
+			"error":            "",
+			"completed_at":     nil,
+			"lease_owner":      "",
+			"lease_expires_at": nil,
+			"attempts":         0,
+			"retry_plan_ids":   encoded,
+			"passed_gates":     nil,
+			"review_feedback":  "",
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := s.GetJob(ctx, jobID); err != nil {
+			return err
+		}
+		return ErrJobNotRetryable
+	}
+	return nil
+}
+
+// AwaitApproval stores the intermediate result and stops the job at gate until it is approved or rejected
+func (s *MySQLJobStore) AwaitApproval(ctx context.Context, jobID string, owner string, gate string, result *JobResult) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode result of job %s: %w", jobID, err)
+	}
+
+	return s.update(ctx, jobID, owner, map[string]interface{}{
+		"status":           string(JobStatusAwaitingApproval),
+		"current_step":     awaitingApprovalStep(gate),
+		"result":           encoded,
+		"pending_gate":     gate,
+		"lease_owner":      "",
+		"lease_expires_at": nil,
+	})
+}
+
+// ApproveJob requeues a job waiting at an approval gate so that a worker resumes after the gate
+func (s *MySQLJobStore) ApproveJob(ctx context.Context, jobID string) error {
+	job, err := s.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != JobStatusAwaitingApproval {
+		return ErrJobNotAwaitingApproval
+	}
+	encoded, err := json.Marshal(append(job.PassedGates, job.PendingGate))
+	if err != nil {
+		return fmt.Errorf("failed to encode passed gates of job %s: %w", jobID, err)
+	}
+
+	// 동시에 승인/거절/취소된 경우를 막기 위해 조회한 게이트에서 멈춰 있을 때만 갱신
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND status = ? AND pending_gate = ?", jobID, string(JobStatusAwaitingApproval), job.PendingGate).
+		Updates(map[string]interface{}{
+			"status":          string(JobStatusPending),
+			"current_step":    approvedStep(job.PendingGate),
+			"passed_gates":    encoded,
+			"pending_gate":    "",
+			"attempts":        0,
+			"retry_plan_ids":  nil,
+			"review_feedback": "",
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrJobNotAwaitingApproval
+	}
+	return nil
+}
+
+// RejectJob requeues a job waiting at an approval gate to regenerate planIDs with the reviewer's feedback
+func (s *MySQLJobStore) RejectJob(ctx context.Context, jobID string, planIDs []int64, feedback string) error {
+	encoded, err := json.Marshal(planIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode retry plans of job %s: %w", jobID, err)
+	}
+
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND status = ?", jobID, string(JobStatusAwaitingApproval)).
+		Updates(map[string]interface{}{
+			"status":          string(JobStatusPending),
+			"progress":        0,
+			"current_step":    "Regenerating rejected plans",
+			"pending_gate":    "",
+			"passed_gates":    nil,
+			"attempts":        0,
+			"retry_plan_ids":  encoded,
+			"review_feedback": feedback,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		if _, err := s.GetJob(ctx, jobID); err != nil {
+			return err
+		}
+		return ErrJobNotAwaitingApproval
+	}
+	return nil
+}
+
+// ClaimJob leases the oldest pending job, or a processing job whose lease expired
+func (s *MySQLJobStore) ClaimJob(ctx context.Context, owner string, leaseDuration time.Duration, maxAttempts int32) (*Job, error) {
+	db := s.dbConn.DB.WithContext(ctx)
+	now := time.Now()
+
+	// 재시도 횟수를 모두 소진한 Job은 실패 처리
+	err := db.Model(&jobRecord{}).
+		Where("status = ? AND lease_expires_at < ? AND attempts >= ?", string(JobStatusProcessing), now, maxAttempts).
+		Updates(map[string]interface{}{
+			"status":       string(JobStatusFailed),
+			"error":        fmt.Sprintf("lease expired after %d attempts", maxAttempts),
+			"completed_at": now,
+		}).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire jobs: %w", err)
+	}
+
+	var candidateIDs []string
+	err = claimable(db.Model(&jobRecord{}), now).
+		Order("created_at").
+		Limit(claimBatchSize).
+		Pluck("id", &candidateIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find claimable jobs: %w", err)
+	}
+
+	// 다른 레플리카와 경쟁하므로 조건부 UPDATE가 성공한 Job만 가져감
+	for _, id := range candidateIDs {
+		res := claimable(db.Model(&jobRecord{}).Where("id = ?", id), now).
+			Updates(map[string]interface{}{
+				"status":           string(JobStatusProcessing),
+				"lease_owner":      owner,
+				"lease_expires_at": now.Add(leaseDuration),
+				"attempts":         gorm.Expr("attempts + 1"),
+			})
+		if res.Error != nil {
+			return nil, fmt.Errorf("failed to claim job %s: %w", id, res.Error)
+		}
+		if res.RowsAffected == 1 {
+			return s.GetJob(ctx, id)
+		}
+	}
+
+	return nil, nil
+}
+
+// RenewLease extends the lease of a job held by owner
+func (s *MySQLJobStore) RenewLease(ctx context.Context, jobID string, owner string, leaseDuration time.Duration) error {
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND lease_owner = ? AND status = ?", jobID, owner, string(JobStatusProcessing)).
+		Update("lease_expires_at", time.Now().Add(leaseDuration))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// ReleaseLease expires the lease held by owner
+func (s *MySQLJobStore) ReleaseLease(ctx context.Context, jobID string, owner string) error {
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND lease_owner = ?", jobID, owner).
+		Update("lease_expires_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// DeleteJobsByDevPlanIDs deletes every job of the given dev plans.
+// A worker still processing one of them loses its lease on the next heartbeat.
+func (s *MySQLJobStore) DeleteJobsByDevPlanIDs(ctx context.Context, devPlanIDs []int64) (int64, error) {
+	if len(devPlanIDs) == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	err := s.dbConn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		jobIDs := tx.Model(&jobRecord{}).Select("id").Where("dev_plan_id IN ?", devPlanIDs)
+		if err := tx.Where("job_id IN (?)", jobIDs).Delete(&jobEventRecord{}).Error; err != nil {
+			return err
+		}
+
+		res := tx.Where("dev_plan_id IN ?", devPlanIDs).Delete(&jobRecord{})
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete jobs: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteFinishedJobs deletes finished jobs (and their events) that finished before completedBefore
+func (s *MySQLJobStore) DeleteFinishedJobs(ctx context.Context, completedBefore time.Time) (int64, error) {
+	statuses := make([]string, len(finishedJobStatuses))
+	for i, status := range finishedJobStatuses {
+		statuses[i] = string(status)
+	}
+
+	var deleted int64
+	err := s.dbConn.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var jobIDs []string
+		err := tx.Model(&jobRecord{}).
+			Where("status IN ? AND completed_at < ?", statuses, completedBefore).
+			Pluck("id", &jobIDs).Error
+		if err != nil || len(jobIDs) == 0 {
+			return err
+		}
+		if err := tx.Where("job_id IN ?", jobIDs).Delete(&jobEventRecord{}).Error; err != nil {
+			return err
+		}
+
+		// RetryJob으로 다시 대기 중이 된 Job은 제외
+		res := tx.Where("id IN ? AND status IN ?", jobIDs, statuses).Delete(&jobRecord{})
+		if res.Error != nil {
+			return res.Error
+		}
+		deleted = res.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+	return deleted, nil
+}
+
+// AppendJobEvent records a progress event; Seq is the auto-increment row ID
+func (s *MySQLJobStore) AppendJobEvent(ctx context.Context, event *JobEvent) error {
+	record := &jobEventRecord{
+		JobID:     event.JobID,
+		Type:      string(event.Type),
+		Stage:     event.Stage,
+		Progress:  event.Progress,
+		Message:   event.Message,
+		PlanIndex: event.PlanIndex,
+		PlanID:    event.PlanID,
+		Path:      event.Path,
+		Diagram:   event.Diagram,
+	}
+	if err := s.dbConn.DB.WithContext(ctx).Create(record).Error; err != nil {
+		return fmt.Errorf("failed to append event to job %s: %w", event.JobID, err)
+	}
+
+	event.Seq = record.ID
+	event.CreatedAt = record.CreatedAt
+	return nil
+}
+
+// ListJobEvents returns events of a job after afterSeq
+func (s *MySQLJobStore) ListJobEvents(ctx context.Context, jobID string, afterSeq int64, limit int) ([]JobEvent, error) {
+	var records []jobEventRecord
+	err := s.dbConn.DB.WithContext(ctx).
+		Where("job_id = ? AND id > ?", jobID, afterSeq).
+		Order("id").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events of job %s: %w", jobID, err)
+	}
+
+	events := make([]JobEvent, len(records))
+	for i := range records {
+		events[i] = records[i].toJobEvent()
+	}
+	return events, nil
+}
+
+// AutoMigrate creates the implementation_jobs tables (SQLite stand-in only; MySQL uses atlas migrations)
+func (s *MySQLJobStore) AutoMigrate() error {
+	return s.dbConn.DB.AutoMigrate(&jobRecord{}, &jobEventRecord{})
+}
+
+// claimBatchSize is how many candidates ClaimJob tries before giving up
+const claimBatchSize = 5
+
+// claimable restricts a query to pending jobs and processing jobs with an expired lease
+func claimable(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.Where(
+		"(status = ? OR (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)))",
+		string(JobStatusPending), string(JobStatusProcessing), now,
+	)
+}
+
+// update applies updates to a job leased to owner unless it has been cancelled.
+// A worker whose lease expired and was claimed by another worker gets ErrLeaseLost instead of overwriting its progress.
+func (s *MySQLJobStore) update(ctx context.Context, jobID string, owner string, updates map[string]interface{}) error {
+	res := s.dbConn.DB.WithContext(ctx).
+		Model(&jobRecord{}).
+		Where("id = ? AND lease_owner = ? AND status <> ?", jobID, owner, string(JobStatusCancelled)).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		job, err := s.GetJob(ctx, jobID)
+		if err != nil {
+			return err
+		}
+		if job.Status == JobStatusCancelled {
+			return ErrJobCancelled
+		}
+		// MySQL은 값이 바뀌지 않은 행을 영향받은 행으로 세지 않으므로 lease 소유자를 다시 확인
+		if job.LeaseOwner != owner {
+			return ErrLeaseLost
+		}
+	}
+	return nil
+}
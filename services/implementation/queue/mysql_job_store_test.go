@@ -0,0 +1,292 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"codev42-implementation/storage"
+)
+
+// newSQLiteJobStore MySQL 대신 SQLite 파일로 MySQLJobStore를 만듦 (레플리카 간 공유 저장소와 같은 조건부 UPDATE 사용)
+func newSQLiteJobStore(t *testing.T) *MySQLJobStore {
+	t.Helper()
+	conn, err := storage.NewSQLiteConnection(filepath.Join(t.TempDir(), "jobs.db") + "?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	store := NewMySQLJobStore(conn)
+	if err := store.AutoMigrate(); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	return store
+}
+
+// jobStores 두 JobStore 구현에 같은 테스트를 적용
+func jobStores(t *testing.T) map[string]JobStore {
+	return map[string]JobStore{
+		"memory": NewJobQueue(),
+		"sqlite": newSQLiteJobStore(t),
+	}
+}
+
+func TestClaimJobConcurrentSingleWinner(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			const workers = 8
+			var wg sync.WaitGroup
+			winners := make(chan string, workers)
+			errs := make(chan error, workers)
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func(owner string) {
+					defer wg.Done()
+					claimed, err := store.ClaimJob(ctx, owner, time.Minute, 3)
+					if err != nil {
+						errs <- err
+						return
+					}
+					if claimed != nil {
+						winners <- claimed.LeaseOwner
+					}
+				}(fmt.Sprintf("worker-%d", i))
+			}
+			wg.Wait()
+			close(winners)
+			close(errs)
+
+			for err := range errs {
+				t.Fatalf("ClaimJob: %v", err)
+			}
+			var owners []string
+			for owner := range winners {
+				owners = append(owners, owner)
+			}
+			if len(owners) != 1 {
+				t.Fatalf("expected exactly one worker to claim the job, got %v", owners)
+			}
+
+			stored, err := store.GetJob(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("GetJob: %v", err)
+			}
+			if stored.Status != JobStatusProcessing || stored.LeaseOwner != owners[0] || stored.Attempts != 1 {
+				t.Fatalf("unexpected job after claim: status=%s owner=%s attempts=%d", stored.Status, stored.LeaseOwner, stored.Attempts)
+			}
+		})
+	}
+}
+
+func TestClaimJobReclaimsExpiredLease(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			if claimed, err := store.ClaimJob(ctx, "worker-a", 50*time.Millisecond, 3); err != nil || claimed == nil {
+				t.Fatalf("first claim: job=%v err=%v", claimed, err)
+			}
+			// lease가 유효한 동안에는 다른 워커가 가져갈 수 없음
+			if claimed, err := store.ClaimJob(ctx, "worker-b", time.Minute, 3); err != nil || claimed != nil {
+				t.Fatalf("claim before expiry: job=%v err=%v", claimed, err)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			claimed, err := store.ClaimJob(ctx, "worker-b", time.Minute, 3)
+			if err != nil || claimed == nil {
+				t.Fatalf("claim after expiry: job=%v err=%v", claimed, err)
+			}
+			if claimed.ID != job.ID || claimed.LeaseOwner != "worker-b" || claimed.Attempts != 2 {
+				t.Fatalf("unexpected reclaimed job: id=%s owner=%s attempts=%d", claimed.ID, claimed.LeaseOwner, claimed.Attempts)
+			}
+		})
+	}
+}
+
+func TestClaimJobFailsAfterMaxAttempts(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			if claimed, err := store.ClaimJob(ctx, "worker-a", 10*time.Millisecond, 1); err != nil || claimed == nil {
+				t.Fatalf("first claim: job=%v err=%v", claimed, err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if claimed, err := store.ClaimJob(ctx, "worker-b", time.Minute, 1); err != nil || claimed != nil {
+				t.Fatalf("claim after attempts exhausted: job=%v err=%v", claimed, err)
+			}
+
+			stored, err := store.GetJob(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("GetJob: %v", err)
+			}
+			if stored.Status != JobStatusFailed {
+				t.Fatalf("expected failed job, got %s", stored.Status)
+			}
+		})
+	}
+}
+
+func TestRenewLeaseRejectsStaleOwner(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			if _, err := store.ClaimJob(ctx, "worker-a", 10*time.Millisecond, 3); err != nil {
+				t.Fatalf("first claim: %v", err)
+			}
+			if err := store.RenewLease(ctx, job.ID, "worker-a", 10*time.Millisecond); err != nil {
+				t.Fatalf("RenewLease by owner: %v", err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if _, err := store.ClaimJob(ctx, "worker-b", time.Minute, 3); err != nil {
+				t.Fatalf("reclaim: %v", err)
+			}
+
+			if err := store.RenewLease(ctx, job.ID, "worker-a", time.Minute); !errors.Is(err, ErrLeaseLost) {
+				t.Fatalf("expected ErrLeaseLost for stale owner, got %v", err)
+			}
+			if err := store.RenewLease(ctx, job.ID, "worker-b", time.Minute); err != nil {
+				t.Fatalf("RenewLease by new owner: %v", err)
+			}
+		})
+	}
+}
+
+func TestStaleOwnerCannotOverwriteJob(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job, err := store.CreateJob(ctx, 1, "", []string{ApprovalGateCode})
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			if _, err := store.ClaimJob(ctx, "worker-a", 10*time.Millisecond, 3); err != nil {
+				t.Fatalf("first claim: %v", err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if _, err := store.ClaimJob(ctx, "worker-b", time.Minute, 3); err != nil {
+				t.Fatalf("reclaim: %v", err)
+			}
+			if err := store.UpdateJob(ctx, job.ID, "worker-b", JobStatusProcessing, 40, "Generating code"); err != nil {
+				t.Fatalf("UpdateJob by owner: %v", err)
+			}
+
+			stale := &JobResult{Files: []GeneratedFile{{Path: "stale.go"}}}
+			writes := map[string]func() error{
+				"UpdateJob": func() error {
+					return store.UpdateJob(ctx, job.ID, "worker-a", JobStatusCompleted, 100, "Completed")
+				},
+				"SetJobResult": func() error { return store.SetJobResult(ctx, job.ID, "worker-a", stale) },
+				"SetJobError":  func() error { return store.SetJobError(ctx, job.ID, "worker-a", errors.New("stale")) },
+				"AwaitApproval": func() error {
+					return store.AwaitApproval(ctx, job.ID, "worker-a", ApprovalGateCode, stale)
+				},
+			}
+			for write, fn := range writes {
+				if err := fn(); !errors.Is(err, ErrLeaseLost) {
+					t.Fatalf("%s by stale owner: expected ErrLeaseLost, got %v", write, err)
+				}
+			}
+
+			stored, err := store.GetJob(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("GetJob: %v", err)
+			}
+			if stored.Status != JobStatusProcessing || stored.Progress != 40 || stored.LeaseOwner != "worker-b" || stored.Result != nil || stored.Error != "" {
+				t.Fatalf("stale owner overwrote job: status=%s progress=%d owner=%s result=%v error=%q",
+					stored.Status, stored.Progress, stored.LeaseOwner, stored.Result, stored.Error)
+			}
+
+			// 취소된 Job은 lease 소유자여도 ErrJobCancelled
+			if err := store.CancelJob(ctx, job.ID, "cancelled"); err != nil {
+				t.Fatalf("CancelJob: %v", err)
+			}
+			if err := store.UpdateJob(ctx, job.ID, "worker-b", JobStatusProcessing, 50, "Running tests"); !errors.Is(err, ErrJobCancelled) {
+				t.Fatalf("expected ErrJobCancelled, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCancelJobsByDevPlanIDsReturnsLeasedJobs(t *testing.T) {
+	for name, store := range jobStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			running, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+			if _, err := store.ClaimJob(ctx, "worker-a", time.Minute, 3); err != nil {
+				t.Fatalf("ClaimJob: %v", err)
+			}
+			pending, err := store.CreateJob(ctx, 1, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+			other, err := store.CreateJob(ctx, 2, "", nil)
+			if err != nil {
+				t.Fatalf("CreateJob: %v", err)
+			}
+
+			leased, err := store.CancelJobsByDevPlanIDs(ctx, []int64{1}, "dev plan deleted")
+			if err != nil {
+				t.Fatalf("CancelJobsByDevPlanIDs: %v", err)
+			}
+			if len(leased) != 1 || leased[0] != running.ID {
+				t.Fatalf("expected only the running job to be leased, got %v", leased)
+			}
+			for id, want := range map[string]JobStatus{running.ID: JobStatusCancelled, pending.ID: JobStatusCancelled, other.ID: JobStatusPending} {
+				job, err := store.GetJob(ctx, id)
+				if err != nil {
+					t.Fatalf("GetJob: %v", err)
+				}
+				if job.Status != want {
+					t.Fatalf("job %s: expected %s, got %s", id, want, job.Status)
+				}
+			}
+
+			// 워커가 멈추면 lease를 반환하고, 다른 워커는 반환할 수 없음
+			if err := store.ReleaseLease(ctx, running.ID, "worker-b"); !errors.Is(err, ErrLeaseLost) {
+				t.Fatalf("expected ErrLeaseLost for another owner, got %v", err)
+			}
+			if err := store.ReleaseLease(ctx, running.ID, "worker-a"); err != nil {
+				t.Fatalf("ReleaseLease: %v", err)
+			}
+			job, err := store.GetJob(ctx, running.ID)
+			if err != nil {
+				t.Fatalf("GetJob: %v", err)
+			}
+			if job.IsLeased(time.Now()) {
+				t.Fatalf("job is still leased after release: %v", job.LeaseExpiresAt)
+			}
+			if leased, err := store.CancelJobsByDevPlanIDs(ctx, []int64{1}, "dev plan deleted"); err != nil || len(leased) != 0 {
+				t.Fatalf("expected no leased jobs after release, got %v (err=%v)", leased, err)
+			}
+		})
+	}
+}
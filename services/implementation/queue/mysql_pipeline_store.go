@@ -0,0 +1,83 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"codev42-implementation/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// pipelineRecord is the implementation_pipelines row
+type pipelineRecord struct {
+	ProjectID  string    `gorm:"primaryKey;type:varchar(255)"`
+	Definition string    `gorm:"type:text;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (pipelineRecord) TableName() string {
+	return "implementation_pipelines"
+}
+
+func (r *pipelineRecord) toPipeline() *Pipeline {
+	return &Pipeline{
+		ProjectID:  r.ProjectID,
+		Definition: r.Definition,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+// MySQLPipelineStore is a PipelineStore backed by the implementation_pipelines table
+type MySQLPipelineStore struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewMySQLPipelineStore creates a pipeline store on an existing connection
+func NewMySQLPipelineStore(dbConn *storage.RDBConnection) *MySQLPipelineStore {
+	return &MySQLPipelineStore{dbConn: dbConn}
+}
+
+func (s *MySQLPipelineStore) GetPipeline(ctx context.Context, projectID string) (*Pipeline, error) {
+	var record pipelineRecord
+	err := s.dbConn.DB.WithContext(ctx).First(&record, "project_id = ?", projectID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline of project %s: %w", projectID, err)
+	}
+	return record.toPipeline(), nil
+}
+
+func (s *MySQLPipelineStore) PutPipeline(ctx context.Context, projectID string, definition string) (*Pipeline, error) {
+	record := &pipelineRecord{
+		ProjectID:  projectID,
+		Definition: definition,
+	}
+	err := s.dbConn.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"definition", "updated_at"}),
+	}).Create(record).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to store pipeline of project %s: %w", projectID, err)
+	}
+	return record.toPipeline(), nil
+}
+
+func (s *MySQLPipelineStore) DeletePipeline(ctx context.Context, projectID string) (bool, error) {
+	res := s.dbConn.DB.WithContext(ctx).Delete(&pipelineRecord{}, "project_id = ?", projectID)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to delete pipeline of project %s: %w", projectID, res.Error)
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// AutoMigrate creates the implementation_pipelines table (SQLite stand-in only; MySQL uses atlas migrations)
+func (s *MySQLPipelineStore) AutoMigrate() error {
+	return s.dbConn.DB.AutoMigrate(&pipelineRecord{})
+}
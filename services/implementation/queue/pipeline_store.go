@@ -0,0 +1,73 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pipeline is the stored YAML pipeline definition of a project
+type Pipeline struct {
+	ProjectID  string
+	Definition string
+	UpdatedAt  time.Time
+}
+
+// PipelineStore persists per-project pipeline definitions. Definitions are validated
+// by the caller; the store keeps the YAML as written.
+type PipelineStore interface {
+	// GetPipeline returns the definition of a project, or nil if it has none
+	GetPipeline(ctx context.Context, projectID string) (*Pipeline, error)
+
+	// PutPipeline stores (or replaces) the definition of a project
+	PutPipeline(ctx context.Context, projectID string, definition string) (*Pipeline, error)
+
+	// DeletePipeline removes the definition of a project and reports whether it existed
+	DeletePipeline(ctx context.Context, projectID string) (bool, error)
+}
+
+// MemoryPipelineStore is an in-memory PipelineStore used with the in-memory job store
+type MemoryPipelineStore struct {
+	pipelines map[string]Pipeline
+	mu        sync.RWMutex
+}
+
+// NewMemoryPipelineStore creates an empty in-memory pipeline store
+func NewMemoryPipelineStore() *MemoryPipelineStore {
+	return &MemoryPipelineStore{
+		pipelines: make(map[string]Pipeline),
+	}
+}
+
+func (s *MemoryPipelineStore) GetPipeline(ctx context.Context, projectID string) (*Pipeline, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pipeline, ok := s.pipelines[projectID]
+	if !ok {
+		return nil, nil
+	}
+	return &pipeline, nil
+}
+
+func (s *MemoryPipelineStore) PutPipeline(ctx context.Context, projectID string, definition string) (*Pipeline, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pipeline := Pipeline{
+		ProjectID:  projectID,
+		Definition: definition,
+		UpdatedAt:  time.Now(),
+	}
+	s.pipelines[projectID] = pipeline
+	return &pipeline, nil
+}
+
+func (s *MemoryPipelineStore) DeletePipeline(ctx context.Context, projectID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.pipelines[projectID]
+	delete(s.pipelines, projectID)
+	return ok, nil
+}
@@ -0,0 +1,53 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Reaper periodically deletes finished jobs older than a TTL.
+// Completed runs stay in the ImplementationStore, so only the job rows and their events are dropped.
+type Reaper struct {
+	store    JobStore
+	ttl      time.Duration
+	interval time.Duration
+}
+
+// NewReaper creates a reaper that deletes jobs finished more than ttl ago, checking every interval
+func NewReaper(store JobStore, ttl time.Duration, interval time.Duration) *Reaper {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Reaper{
+		store:    store,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run deletes expired jobs once immediately and then every interval until ctx is cancelled
+func (r *Reaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.reap(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Reaper) reap(ctx context.Context) {
+	deleted, err := r.store.DeleteFinishedJobs(ctx, time.Now().Add(-r.ttl))
+	if err != nil {
+		log.Printf("Failed to delete finished jobs: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d jobs finished more than %s ago", deleted, r.ttl)
+	}
+}
@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ProcessFunc runs a claimed job. ctx is cancelled when the lease is lost.
+type ProcessFunc func(ctx context.Context, job *Job)
+
+// WorkerOptions configures how a Worker claims and leases jobs
+type WorkerOptions struct {
+	Owner         string        // 이 레플리카를 식별하는 lease 소유자 ID
+	Concurrency   int           // 동시에 처리할 Job 수
+	LeaseDuration time.Duration // lease 유효 시간
+	PollInterval  time.Duration // 처리할 Job이 없을 때 대기 시간
+	MaxAttempts   int32         // lease 만료 후 재시도 가능한 최대 횟수
+}
+
+// Worker claims jobs from a shared JobStore and keeps their lease alive while running
+type Worker struct {
+	store   JobStore
+	opts    WorkerOptions
+	process ProcessFunc
+}
+
+// NewWorker creates a new worker
+func NewWorker(store JobStore, opts WorkerOptions, process ProcessFunc) *Worker {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+	return &Worker{
+		store:   store,
+		opts:    opts,
+		process: process,
+	}
+}
+
+// Run polls for jobs until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	for {
+		job, err := w.store.ClaimJob(ctx, w.opts.Owner, w.opts.LeaseDuration, w.opts.MaxAttempts)
+		if err != nil {
+			log.Printf("Worker %s failed to claim job: %v", w.opts.Owner, err)
+		}
+		if job != nil {
+			w.runWithHeartbeat(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.opts.PollInterval):
+		}
+	}
+}
+
+// runWithHeartbeat renews the lease periodically, cancels the job once the lease is lost
+// and releases the lease when the job stops
+func (w *Worker) runWithHeartbeat(ctx context.Context, job *Job) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(w.opts.LeaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := w.store.RenewLease(jobCtx, job.ID, w.opts.Owner, w.opts.LeaseDuration)
+				if errors.Is(err, ErrLeaseLost) {
+					log.Printf("Worker %s lost lease on job %s", w.opts.Owner, job.ID)
+					cancel()
+					return
+				}
+				if err != nil {
+					log.Printf("Worker %s failed to renew lease on job %s: %v", w.opts.Owner, job.ID, err)
+				}
+			}
+		}
+	}()
+
+	log.Printf("Worker %s claimed job %s (attempt %d)", w.opts.Owner, job.ID, job.Attempts)
+	w.process(jobCtx, job)
+	close(done)
+
+	// Let callers waiting for the job to stop (and other replicas, if it was interrupted) proceed at once
+	err := w.store.ReleaseLease(context.WithoutCancel(ctx), job.ID, w.opts.Owner)
+	if err != nil && !errors.Is(err, ErrLeaseLost) {
+		log.Printf("Worker %s failed to release lease on job %s: %v", w.opts.Owner, job.ID, err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+	"sync"
+)
+
+// Diagnostic 생성 코드 검사 결과 (Line, Column은 1부터, 알 수 없으면 0)
+type Diagnostic struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", d.Path, d.Line, d.Column, d.Message)
+	}
+	return fmt.Sprintf("%s: %s", d.Path, d.Message)
+}
+
+// CodeChecker 언어별 생성 코드 검사기
+type CodeChecker interface {
+	// Check target 파일을 검사하여 target의 진단만 반환. related는 target이 참조할 수 있는 이미 생성된 파일
+	Check(target GeneratedFile, related []GeneratedFile) []Diagnostic
+}
+
+var (
+	codeCheckersMu sync.RWMutex
+	codeCheckers   = map[string]CodeChecker{}
+)
+
+func init() {
+	goChecker := NewGoChecker()
+	RegisterCodeChecker("go", goChecker)
+	RegisterCodeChecker("golang", goChecker)
+}
+
+// RegisterCodeChecker 언어 이름(대소문자 무시)에 검사기를 등록
+func RegisterCodeChecker(language string, checker CodeChecker) {
+	codeCheckersMu.Lock()
+	defer codeCheckersMu.Unlock()
+	codeCheckers[strings.ToLower(language)] = checker
+}
+
+// CheckerForLanguage 등록된 검사기를 반환 (없으면 nil, 검사 없이 생성 결과를 그대로 사용)
+func CheckerForLanguage(language string) CodeChecker {
+	codeCheckersMu.RLock()
+	defer codeCheckersMu.RUnlock()
+	return codeCheckers[strings.ToLower(strings.TrimSpace(language))]
+}
+
+// GoChecker go/parser와 go/types로 프로세스 안에서 Go 코드를 타입 검사
+// FileSet과 source importer는 검사마다 새로 만듦 (공유하면 파싱한 파일이 계속 쌓이고 importer는 동시 사용 불가)
+type GoChecker struct{}
+
+func NewGoChecker() *GoChecker {
+	return &GoChecker{}
+}
+
+// Check target과 같은 패키지 이름을 가진 related 파일을 함께 타입 검사
+func (c *GoChecker) Check(target GeneratedFile, related []GeneratedFile) []Diagnostic {
+	fset := token.NewFileSet()
+
+	targetFile, err := parser.ParseFile(fset, target.Path, target.Code, parser.AllErrors)
+	if err != nil {
+		return parseDiagnostics(target.Path, err)
+	}
+
+	files := []*ast.File{targetFile}
+	for _, file := range related {
+		if file.Path == target.Path {
+			continue
+		}
+		// 문법 오류가 있거나 다른 패키지인 파일은 제외 (해당 파일의 진단은 이미 기록됨)
+		parsed, err := parser.ParseFile(fset, file.Path, file.Code, 0)
+		if err != nil || parsed.Name.Name != targetFile.Name.Name {
+			continue
+		}
+		files = append(files, parsed)
+	}
+
+	var diagnostics []Diagnostic
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			typeErr, ok := err.(types.Error)
+			if !ok {
+				diagnostics = append(diagnostics, Diagnostic{Path: target.Path, Message: err.Error()})
+				return
+			}
+			position := typeErr.Fset.Position(typeErr.Pos)
+			// 외부 모듈이나 GOROOT가 없는 환경의 import 실패는 생성 코드의 오류가 아님
+			if position.Filename != target.Path || strings.HasPrefix(typeErr.Msg, "could not import") {
+				return
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Path:    target.Path,
+				Line:    position.Line,
+				Column:  position.Column,
+				Message: typeErr.Msg,
+			})
+		},
+	}
+	config.Check(targetFile.Name.Name, fset, files, nil)
+	return diagnostics
+}
+
+func parseDiagnostics(path string, err error) []Diagnostic {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return []Diagnostic{{Path: path, Message: err.Error()}}
+	}
+	diagnostics := make([]Diagnostic, 0, len(list))
+	for _, scanErr := range list {
+		diagnostics = append(diagnostics, Diagnostic{
+			Path:    path,
+			Line:    scanErr.Pos.Line,
+			Column:  scanErr.Pos.Column,
+			Message: scanErr.Msg,
+		})
+	}
+	return diagnostics
+}
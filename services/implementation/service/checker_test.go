@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestGoCheckerConcurrentChecks(t *testing.T) {
+	checker := NewGoChecker()
+	related := []GeneratedFile{{Path: "repository.go", Code: "package app\n\ntype Repository struct{}\n\nfunc (r Repository) Get(id int) string { return \"\" }\n"}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			target := GeneratedFile{
+				Path: fmt.Sprintf("service_%d.go", i),
+				Code: "package app\n\nimport \"strings\"\n\nfunc Run(r Repository) int {\n\treturn strings.Count(r.Get(1), \"a\") + \"x\"\n}\n",
+			}
+			diagnostics := checker.Check(target, related)
+			if len(diagnostics) != 1 || diagnostics[0].Path != target.Path || diagnostics[0].Line != 6 {
+				errs <- fmt.Errorf("%s: unexpected diagnostics %v", target.Path, diagnostics)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"sync"
+)
+
+// 계획과 생성 코드의 불일치 종류
+const (
+	ConformanceMissing         = "missing"           // 계획한 타입이나 함수가 없음
+	ConformanceReceiver        = "receiver_mismatch" // 함수가 다른 리시버(또는 리시버 없이)로 구현됨
+	ConformanceParams          = "params_mismatch"   // 파라미터 타입이 다름
+	ConformanceReturns         = "returns_mismatch"  // 반환 타입이 다름
+	ConformanceUnplannedSymbol = "unplanned_symbol"  // 계획에 없는 exported 심볼이 추가됨
+)
+
+// ConformanceIssue 계획과 생성 코드의 불일치 하나
+type ConformanceIssue struct {
+	Kind     string
+	Symbol   string // ClassName.Method 또는 함수/타입 이름
+	Expected string
+	Actual   string
+	Line     int // 생성 코드에서의 위치 (없으면 0)
+}
+
+func (i ConformanceIssue) String() string {
+	switch i.Kind {
+	case ConformanceMissing:
+		return fmt.Sprintf("%s is planned but not implemented", i.Symbol)
+	case ConformanceUnplannedSymbol:
+		return fmt.Sprintf("%s is exported but not in the plan", i.Symbol)
+	default:
+		return fmt.Sprintf("%s %s: expected %s, got %s", i.Symbol, i.Kind, i.Expected, i.Actual)
+	}
+}
+
+// ConformanceReport 계획 하나에 대한 생성 코드의 시그니처 적합성 검사 결과
+type ConformanceReport struct {
+	Conforms bool
+	Issues   []ConformanceIssue
+}
+
+// Diagnostics 수정 요청에 전달할 진단으로 변환
+func (r *ConformanceReport) Diagnostics(path string) []Diagnostic {
+	if r == nil {
+		return nil
+	}
+	diagnostics := make([]Diagnostic, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		diagnostics = append(diagnostics, Diagnostic{
+			Path:    path,
+			Line:    issue.Line,
+			Message: "plan conformance: " + issue.String(),
+		})
+	}
+	return diagnostics
+}
+
+// ConformanceChecker 언어별 계획-코드 시그니처 검사기
+type ConformanceChecker interface {
+	// Check 파일이 계획의 타입과 함수를 그대로 구현했는지 검사. 코드를 해석할 수 없으면 nil
+	Check(plan Plan, file GeneratedFile) *ConformanceReport
+}
+
+var (
+	conformanceCheckersMu sync.RWMutex
+	conformanceCheckers   = map[string]ConformanceChecker{}
+)
+
+func init() {
+	RegisterConformanceChecker("go", GoConformanceChecker{})
+	RegisterConformanceChecker("golang", GoConformanceChecker{})
+}
+
+// RegisterConformanceChecker 언어 이름(대소문자 무시)에 검사기를 등록
+func RegisterConformanceChecker(language string, checker ConformanceChecker) {
+	conformanceCheckersMu.Lock()
+	defer conformanceCheckersMu.Unlock()
+	conformanceCheckers[strings.ToLower(language)] = checker
+}
+
+// ConformanceCheckerForLanguage 등록된 검사기를 반환 (없으면 nil)
+func ConformanceCheckerForLanguage(language string) ConformanceChecker {
+	conformanceCheckersMu.RLock()
+	defer conformanceCheckersMu.RUnlock()
+	return conformanceCheckers[strings.ToLower(strings.TrimSpace(language))]
+}
+
+// GoConformanceChecker go/ast로 계획한 함수/메서드의 리시버와 파라미터/반환 타입, 계획에 없는 exported 심볼을 검사
+//
+// 어노테이션의 Params/Returns를 Go 필드 목록으로 해석할 수 없으면 해당 항목은 비교하지 않는다.
+type GoConformanceChecker struct{}
+
+func (GoConformanceChecker) Check(plan Plan, file GeneratedFile) *ConformanceReport {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file.Path, file.Code, 0)
+	if err != nil {
+		// 문법 오류는 컴파일 검사에서 보고
+		return nil
+	}
+
+	line := func(pos token.Pos) int { return fset.Position(pos).Line }
+	report := &ConformanceReport{}
+	addIssue := func(issue ConformanceIssue) { report.Issues = append(report.Issues, issue) }
+
+	// 최상위 선언 수집 (메서드는 리시버 타입 이름.메서드 이름)
+	functions := make(map[string]*ast.FuncDecl)
+	methods := make(map[string]*ast.FuncDecl)
+	methodsByName := make(map[string][]*ast.FuncDecl)
+	typeLines := make(map[string]int)
+	type symbol struct {
+		name string
+		line int
+	}
+	var exported []symbol
+	for _, decl := range parsed.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				functions[decl.Name.Name] = decl
+				if decl.Name.IsExported() {
+					exported = append(exported, symbol{decl.Name.Name, line(decl.Pos())})
+				}
+				continue
+			}
+			receiver := receiverTypeName(decl.Recv.List[0].Type)
+			methods[receiver+"."+decl.Name.Name] = decl
+			methodsByName[decl.Name.Name] = append(methodsByName[decl.Name.Name], decl)
+			if decl.Name.IsExported() {
+				exported = append(exported, symbol{receiver + "." + decl.Name.Name, line(decl.Pos())})
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					typeLines[spec.Name.Name] = line(spec.Pos())
+					if spec.Name.IsExported() {
+						exported = append(exported, symbol{spec.Name.Name, line(spec.Pos())})
+					}
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if name.IsExported() {
+							exported = append(exported, symbol{name.Name, line(name.Pos())})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	planned := make(map[string]bool)
+	if plan.ClassName != "" {
+		planned[plan.ClassName] = true
+		if _, ok := typeLines[plan.ClassName]; !ok {
+			addIssue(ConformanceIssue{Kind: ConformanceMissing, Symbol: plan.ClassName, Expected: "type " + plan.ClassName})
+		}
+	}
+
+	for _, annotation := range plan.Annotations {
+		symbolName := annotation.Name
+		var decl *ast.FuncDecl
+		if plan.ClassName != "" {
+			symbolName = plan.ClassName + "." + annotation.Name
+			decl = methods[symbolName]
+		} else {
+			decl = functions[annotation.Name]
+		}
+		planned[symbolName] = true
+
+		if decl == nil {
+			// 같은 이름이 다른 리시버(또는 리시버 없이)로 구현된 경우
+			if other := wrongReceiver(annotation.Name, plan.ClassName, functions, methodsByName); other != nil {
+				// 잘못된 위치의 구현은 리시버 불일치로만 보고
+				if receiver := declReceiver(other); receiver != "" {
+					planned[receiver+"."+annotation.Name] = true
+				} else {
+					planned[annotation.Name] = true
+				}
+				addIssue(ConformanceIssue{
+					Kind:     ConformanceReceiver,
+					Symbol:   symbolName,
+					Expected: receiverDescription(plan.ClassName),
+					Actual:   receiverDescription(declReceiver(other)),
+					Line:     line(other.Pos()),
+				})
+				continue
+			}
+			addIssue(ConformanceIssue{Kind: ConformanceMissing, Symbol: symbolName, Expected: "func " + symbolName})
+			continue
+		}
+
+		if expected, ok := parseGoFieldTypes(annotation.Params); ok {
+			if actual := fieldTypes(decl.Type.Params); !sameTypes(expected, actual) {
+				addIssue(ConformanceIssue{
+					Kind:     ConformanceParams,
+					Symbol:   symbolName,
+					Expected: "(" + strings.Join(expected, ", ") + ")",
+					Actual:   "(" + strings.Join(actual, ", ") + ")",
+					Line:     line(decl.Pos()),
+				})
+			}
+		}
+		if expected, ok := parseGoFieldTypes(annotation.Returns); ok {
+			if actual := fieldTypes(decl.Type.Results); !sameTypes(expected, actual) {
+				addIssue(ConformanceIssue{
+					Kind:     ConformanceReturns,
+					Symbol:   symbolName,
+					Expected: "(" + strings.Join(expected, ", ") + ")",
+					Actual:   "(" + strings.Join(actual, ", ") + ")",
+					Line:     line(decl.Pos()),
+				})
+			}
+		}
+	}
+
+	for _, sym := range exported {
+		if !planned[sym.name] {
+			addIssue(ConformanceIssue{Kind: ConformanceUnplannedSymbol, Symbol: sym.name, Line: sym.line})
+		}
+	}
+
+	report.Conforms = len(report.Issues) == 0
+	return report
+}
+
+// wrongReceiver 계획과 다른 리시버로 구현된 같은 이름의 함수
+func wrongReceiver(name string, className string, functions map[string]*ast.FuncDecl, methodsByName map[string][]*ast.FuncDecl) *ast.FuncDecl {
+	if className != "" {
+		if decl, ok := functions[name]; ok {
+			return decl
+		}
+	}
+	for _, decl := range methodsByName[name] {
+		if declReceiver(decl) != className {
+			return decl
+		}
+	}
+	return nil
+}
+
+func declReceiver(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+	return receiverTypeName(decl.Recv.List[0].Type)
+}
+
+func receiverDescription(receiver string) string {
+	if receiver == "" {
+		return "no receiver"
+	}
+	return "receiver " + receiver
+}
+
+// receiverTypeName *T, T[K] 등에서 타입 이름만 추출
+func receiverTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	default:
+		return types.ExprString(expr)
+	}
+}
+
+// parseGoFieldTypes 어노테이션의 Params/Returns("id int64, name string", "(string, error)" 등)를 타입 목록으로 해석.
+// 비어 있거나 없음을 뜻하면 빈 목록, Go 필드 목록으로 해석할 수 없으면 ok=false
+func parseGoFieldTypes(spec string) ([]string, bool) {
+	spec = strings.TrimSpace(spec)
+	switch strings.ToLower(spec) {
+	case "", "none", "void", "nothing", "n/a", "-", "없음", "()":
+		return nil, true
+	}
+	if strings.HasPrefix(spec, "(") && strings.HasSuffix(spec, ")") {
+		spec = spec[1 : len(spec)-1]
+	}
+
+	expr, err := parser.ParseExpr("func(" + spec + ")")
+	if err != nil {
+		return nil, false
+	}
+	funcType, ok := expr.(*ast.FuncType)
+	if !ok {
+		return nil, false
+	}
+	return fieldTypes(funcType.Params), true
+}
+
+// fieldTypes 이름을 제외한 필드 타입 목록 (a, b int는 int 두 개)
+func fieldTypes(fields *ast.FieldList) []string {
+	if fields == nil {
+		return nil
+	}
+	var result []string
+	for _, field := range fields.List {
+		typeName := types.ExprString(field.Type)
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			result = append(result, typeName)
+		}
+	}
+	return result
+}
+
+func sameTypes(expected []string, actual []string) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	normalize := func(typeName string) string {
+		typeName = strings.ReplaceAll(typeName, "interface{}", "any")
+		return strings.Join(strings.Fields(typeName), "")
+	}
+	for i := range expected {
+		if normalize(expected[i]) != normalize(actual[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PlanKey 의존 관계에서 계획을 가리키는 이름 (클래스명, 함수 계획이면 함수 이름)
+func PlanKey(plan Plan) string {
+	if plan.ClassName == "" && len(plan.Annotations) > 0 {
+		return plan.Annotations[0].Name
+	}
+	return plan.ClassName
+}
+
+// OrderPlans 의존 관계를 위상 정렬하여 구현 단계별 계획 index 목록을 반환.
+// 같은 단계의 계획은 서로 의존하지 않으므로 병렬로 구현할 수 있음.
+// 목록에 없는 의존 대상은 무시하고, 순환이 있으면 에러를 반환
+func OrderPlans(plans []Plan) ([][]int, error) {
+	indexByKey := make(map[string]int, len(plans))
+	for i, plan := range plans {
+		indexByKey[PlanKey(plan)] = i
+	}
+
+	inDegree := make([]int, len(plans))
+	dependents := make([][]int, len(plans))
+	for i, plan := range plans {
+		seen := make(map[int]bool, len(plan.DependsOn))
+		for _, dependency := range plan.DependsOn {
+			depIndex, ok := indexByKey[dependency]
+			if !ok || seen[depIndex] {
+				continue
+			}
+			seen[depIndex] = true
+			inDegree[i]++
+			dependents[depIndex] = append(dependents[depIndex], i)
+		}
+	}
+
+	var levels [][]int
+	var current []int
+	for i := range plans {
+		if inDegree[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	ordered := 0
+	for len(current) > 0 {
+		levels = append(levels, current)
+		ordered += len(current)
+
+		var next []int
+		for _, index := range current {
+			for _, dependent := range dependents[index] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		sort.Ints(next)
+		current = next
+	}
+
+	if ordered < len(plans) {
+		var cyclic []string
+		for i, degree := range inDegree {
+			if degree > 0 {
+				cyclic = append(cyclic, PlanKey(plans[i]))
+			}
+		}
+		return nil, fmt.Errorf("plan dependency cycle among: %s", strings.Join(cyclic, ", "))
+	}
+	return levels, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// unified diff에서 변경 줄 앞뒤로 보여줄 줄 수
+	diffContext = 3
+
+	// 줄 단위 LCS 표의 최대 크기. 넘으면 변경 구간 전체를 삭제 후 추가로 표시
+	maxDiffCells = 4_000_000
+)
+
+type diffOp struct {
+	kind byte // ' ' 같음, '-' 삭제, '+' 추가
+	text string
+}
+
+// DiffLines before와 after를 줄 단위로 비교한 unified diff와 추가/삭제된 줄 수 (같으면 빈 문자열)
+func DiffLines(path string, before string, after string) (string, int, int) {
+	ops := diffOps(splitLines(before), splitLines(after))
+
+	// 각 op 직전까지의 원본/수정본 줄 수 (hunk 헤더의 시작 줄 계산용)
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+	added, removed := 0, 0
+	for i, op := range ops {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+		switch op.kind {
+		case ' ':
+			oldLines[i+1]++
+			newLines[i+1]++
+		case '-':
+			oldLines[i+1]++
+			removed++
+		case '+':
+			newLines[i+1]++
+			added++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "", 0, 0
+	}
+
+	var b strings.Builder
+	b.WriteString("--- a/" + path + "\n")
+	b.WriteString("+++ b/" + path + "\n")
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// 같은 줄이 diffContext*2 이하로 끼어 있으면 하나의 hunk로 묶음
+		start := max(0, i-diffContext)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(ops) && ops[end+run].kind == ' ' {
+				run++
+			}
+			if end+run == len(ops) || run > diffContext*2 {
+				end = min(len(ops), end+diffContext)
+				break
+			}
+			end += run
+		}
+
+		oldCount := oldLines[end] - oldLines[start]
+		newCount := newLines[end] - newLines[start]
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(oldLines[start], oldCount), hunkRange(newLines[start], newCount))
+		for _, op := range ops[start:end] {
+			b.WriteByte(op.kind)
+			b.WriteString(op.text)
+			b.WriteByte('\n')
+		}
+		i = end
+	}
+	return b.String(), added, removed
+}
+
+// hunkRange unified diff 형식의 시작 줄과 줄 수 (줄이 없으면 직전 줄 번호)
+func hunkRange(before int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffOps 앞뒤의 같은 줄을 제외한 구간만 LCS로 비교
+func diffOps(a []string, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, lcsOps(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func lcsOps(a []string, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lengths[i][j]: a[i:]와 b[j:]의 최장 공통 부분 수열 길이
+	lengths := make([][]int32, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
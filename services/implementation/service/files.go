@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// PlanStatus 계획 하나의 구현 결과
+type PlanStatus string
+
+const (
+	PlanStatusPending   PlanStatus = "pending"   // 다시 구현할 계획 (RetryPlans)
+	PlanStatusCompleted PlanStatus = "completed" // 코드 생성 완료
+	PlanStatusFailed    PlanStatus = "failed"    // LLM 호출 실패
+	PlanStatusSkipped   PlanStatus = "skipped"   // 의존하는 계획이 구현되지 않아 건너뜀
+)
+
+// GeneratedFile 계획 하나를 구현한 파일
+type GeneratedFile struct {
+	Path        string
+	Language    string
+	PlanID      int64 // 원본 계획 ID
+	Code        string
+	Diagnostics []Diagnostic       // 수정 루프 후에도 남은 검사 결과
+	Tests       []TestResult       // 생성된 단위 테스트 실행 결과
+	Conformance *ConformanceReport // 계획과 시그니처 비교 결과 (검사기가 있는 언어만)
+	Status      PlanStatus
+	Error       string // 실패하거나 건너뛴 이유
+	Attempts    int    // 구현 시도 횟수 (재시도 포함)
+
+	plan         *Plan  // 파일을 생성한 계획
+	reused       bool   // 이전 실행의 결과를 그대로 사용 (다시 생성하거나 테스트하지 않음)
+	previousCode string // 다시 구현하는 계획의 이전 코드 (리뷰어 피드백을 반영할 때 사용)
+}
+
+// Reused 이전 실행의 결과를 그대로 사용한 파일인지 여부
+func (f GeneratedFile) Reused() bool {
+	return f.reused
+}
+
+// 언어별 파일 확장자와 파일 이름 규칙 (pascal이면 클래스 이름을 그대로 사용)
+var languageFileRules = map[string]struct {
+	extension string
+	pascal    bool
+}{
+	"go":         {".go", false},
+	"golang":     {".go", false},
+	"python":     {".py", false},
+	"java":       {".java", true},
+	"kotlin":     {".kt", true},
+	"c#":         {".cs", true},
+	"csharp":     {".cs", true},
+	"typescript": {".ts", false},
+	"javascript": {".js", false},
+	"rust":       {".rs", false},
+	"c++":        {".cpp", false},
+	"cpp":        {".cpp", false},
+	"c":          {".c", false},
+	"ruby":       {".rb", false},
+	"swift":      {".swift", true},
+	"php":        {".php", true},
+}
+
+// FilePathForPlan 계획 이름과 언어로 파일 경로를 결정 (예: UserService → user_service.go, UserService.java)
+func FilePathForPlan(language string, plan Plan) string {
+	name := PlanKey(plan)
+	if name == "" {
+		name = "main"
+	}
+
+	rule, ok := languageFileRules[strings.ToLower(strings.TrimSpace(language))]
+	if !ok {
+		rule.extension = ".txt"
+	}
+	if rule.pascal {
+		return name + rule.extension
+	}
+	return toSnakeCase(name) + rule.extension
+}
+
+// uniquePaths 경로가 겹치면 _2, _3 ... 을 붙여 구분
+func uniquePaths(files []GeneratedFile) {
+	used := make(map[string]bool, len(files))
+	for i := range files {
+		candidate := files[i].Path
+		ext := path.Ext(candidate)
+		base := strings.TrimSuffix(candidate, ext)
+		for n := 2; used[candidate]; n++ {
+			candidate = fmt.Sprintf("%s_%d%s", base, n, ext)
+		}
+		used[candidate] = true
+		files[i].Path = candidate
+	}
+}
+
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r):
+			// 앞 글자가 소문자이거나, 약어 뒤에 새 단어가 시작될 때 구분 (HTTPServer → http_server)
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPipelineYAML 프로젝트에 파이프라인 정의가 없을 때 사용하는 기본 파이프라인
+const DefaultPipelineYAML = `stages:
+  - name: generate_code
+  - name: run_tests
+  - name: generate_diagrams
+  - name: analyze_code
+`
+
+// PipelineDefinition 프로젝트별 구현 파이프라인 정의 (YAML). 단계는 나열된 순서대로 실행
+type PipelineDefinition struct {
+	Stages []PipelineStageConfig `yaml:"stages"`
+}
+
+// PipelineStageConfig 파이프라인 단계 하나의 설정
+type PipelineStageConfig struct {
+	Name           string    `yaml:"name"`                     // 등록된 단계 이름 (generate_code, run_tests, generate_diagrams, analyze_code, ...)
+	Optional       bool      `yaml:"optional,omitempty"`       // 실패하면 경고만 남기고 다음 단계를 진행
+	TimeoutSeconds int       `yaml:"timeoutSeconds,omitempty"` // 단계 제한 시간 (0이면 서비스 기본값)
+	Settings       yaml.Node `yaml:"settings,omitempty"`       // 단계별 설정 (단계마다 형식이 다름)
+}
+
+// HasSettings settings가 지정되었는지 여부
+func (c PipelineStageConfig) HasSettings() bool {
+	return c.Settings.Kind != 0
+}
+
+// DecodeSettings settings를 target으로 해석 (지정되지 않았으면 target을 그대로 둠)
+func (c PipelineStageConfig) DecodeSettings(target interface{}) error {
+	if !c.HasSettings() {
+		return nil
+	}
+	// 알 수 없는 설정을 거부하기 위해 다시 직렬화하여 KnownFields로 해석
+	content, err := yaml.Marshal(&c.Settings)
+	if err != nil {
+		return fmt.Errorf("invalid settings of stage %s: %v", c.Name, err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(target); err != nil {
+		return fmt.Errorf("invalid settings of stage %s: %s", c.Name, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	return nil
+}
+
+// ParsePipelineDefinition YAML 파이프라인 정의를 읽고 구조를 검증.
+// 단계 이름과 settings는 단계를 등록한 쪽에서 검증
+func ParsePipelineDefinition(content string) (*PipelineDefinition, error) {
+	var definition PipelineDefinition
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&definition); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("pipeline definition is empty")
+		}
+		return nil, fmt.Errorf("invalid pipeline definition: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if len(definition.Stages) == 0 {
+		return nil, fmt.Errorf("pipeline definition has no stages")
+	}
+
+	seen := make(map[string]bool, len(definition.Stages))
+	for i, stage := range definition.Stages {
+		if strings.TrimSpace(stage.Name) == "" {
+			return nil, fmt.Errorf("stage %d has no name", i+1)
+		}
+		if seen[stage.Name] {
+			return nil, fmt.Errorf("stage %s is listed more than once", stage.Name)
+		}
+		seen[stage.Name] = true
+		if stage.TimeoutSeconds < 0 {
+			return nil, fmt.Errorf("timeoutSeconds of stage %s must not be negative", stage.Name)
+		}
+	}
+	return &definition, nil
+}
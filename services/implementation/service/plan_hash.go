@@ -0,0 +1,57 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// PlanHashes 계획마다 내용(클래스 이름, 어노테이션)과 의존 대상 계획의 해시를 합친 SHA-256 해시.
+// 의존 순서대로 계산하므로 의존 대상의 어노테이션이 바뀌면 그 계획에 (간접적으로) 의존하는 계획의 해시도 바뀜.
+// 계획 ID와 순서는 포함하지 않으므로, 내용이 같으면 다른 개발 계획이나 수정본에서도 같은 값이 나옴.
+// 의존 관계에 순환이 있으면 에러를 반환
+func PlanHashes(plans []Plan) (map[int64]string, error) {
+	levels, err := OrderPlans(plans)
+	if err != nil {
+		return nil, err
+	}
+	indexByKey := make(map[string]int, len(plans))
+	for i, plan := range plans {
+		indexByKey[PlanKey(plan)] = i
+	}
+
+	hashByIndex := make([]string, len(plans))
+	hashes := make(map[int64]string, len(plans))
+	for _, level := range levels {
+		for _, index := range level {
+			plan := plans[index]
+			// 목록에 없는 의존 대상은 이름만 포함
+			dependencies := make(map[string]string, len(plan.DependsOn))
+			for _, dependency := range plan.DependsOn {
+				if depIndex, ok := indexByKey[dependency]; ok {
+					dependencies[dependency] = hashByIndex[depIndex]
+				} else {
+					dependencies[dependency] = ""
+				}
+			}
+			hashByIndex[index] = planHash(plan, dependencies)
+			hashes[plan.ID] = hashByIndex[index]
+		}
+	}
+	return hashes, nil
+}
+
+// planHash 계획 하나의 해시. dependencies는 의존 대상 이름별 해시 (JSON 직렬화 시 이름순으로 정렬됨)
+func planHash(plan Plan, dependencies map[string]string) string {
+	content, _ := json.Marshal(struct {
+		ClassName    string            `json:"className"`
+		Dependencies map[string]string `json:"dependencies"`
+		Annotations  []Annotation      `json:"annotations"`
+	}{
+		ClassName:    plan.ClassName,
+		Dependencies: dependencies,
+		Annotations:  plan.Annotations,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
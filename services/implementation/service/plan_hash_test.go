@@ -0,0 +1,46 @@
+package service
+
+import "testing"
+
+func TestPlanHashesIncludeDependencies(t *testing.T) {
+	plans := []Plan{
+		{ID: 1, ClassName: "Store", Annotations: []Annotation{{Name: "Get", Returns: "Item"}}},
+		{ID: 2, ClassName: "Repository", DependsOn: []string{"Store"}, Annotations: []Annotation{{Name: "Find"}}},
+		{ID: 3, ClassName: "Service", DependsOn: []string{"Repository"}, Annotations: []Annotation{{Name: "Run"}}},
+		{ID: 4, ClassName: "Logger", Annotations: []Annotation{{Name: "Log"}}},
+	}
+	before, err := PlanHashes(plans)
+	if err != nil {
+		t.Fatalf("PlanHashes: %v", err)
+	}
+
+	// ID와 순서가 달라도 내용이 같으면 같은 해시
+	reordered := []Plan{plans[3], plans[2], plans[1], plans[0]}
+	for i := range reordered {
+		reordered[i].ID += 10
+	}
+	again, err := PlanHashes(reordered)
+	if err != nil {
+		t.Fatalf("PlanHashes: %v", err)
+	}
+	for _, plan := range plans {
+		if again[plan.ID+10] != before[plan.ID] {
+			t.Fatalf("hash of %s depends on plan id or order", plan.ClassName)
+		}
+	}
+
+	// Store의 시그니처가 바뀌면 직접, 간접적으로 의존하는 계획의 해시도 바뀜
+	plans[0].Annotations = []Annotation{{Name: "Get", Returns: "*Item"}}
+	after, err := PlanHashes(plans)
+	if err != nil {
+		t.Fatalf("PlanHashes: %v", err)
+	}
+	for _, id := range []int64{1, 2, 3} {
+		if after[id] == before[id] {
+			t.Fatalf("hash of plan %d did not change with its dependency", id)
+		}
+	}
+	if after[4] != before[4] {
+		t.Fatalf("hash of an unrelated plan changed")
+	}
+}
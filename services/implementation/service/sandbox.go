@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// nobody 사용자/그룹 ID
+const sandboxNobody = 65534
+
+// 샌드박스 초기화 프로세스의 argv[0]. 서비스 바이너리를 이 이름으로 다시 실행하면 격리를 마친 뒤 대상을 exec
+const sandboxInitName = "codev42-sandbox-init"
+
+// Sandbox LLM이 생성한 코드(컴파일된 테스트 바이너리)를 격리하여 실행.
+// 서비스 바이너리를 초기화 프로세스로 다시 실행하여 새 user/mount/네트워크/PID/IPC namespace를 만들고,
+// 실행 디렉터리를 루트로 pivot_root한 뒤(대상 바이너리만 읽기 전용으로 마운트, 기존 루트는 분리) 자원 한도를 지정하고
+// 권한 없는 사용자(capability 없음, no_new_privs)로 전환하여 대상을 exec. 서비스 컨테이너의 파일과 네트워크에는 접근할 수 없음
+type Sandbox struct {
+	UID, GID    int           // 대상을 실행할 사용자 (root로 실행 중이면 호스트에서도 같은 ID)
+	CPUTime     time.Duration // 프로세스의 CPU 시간 (모든 스레드 합계)
+	MemoryBytes int64         // 가상 메모리
+	FileBytes   int64         // 쓸 수 있는 파일 하나의 크기
+	OpenFiles   int
+}
+
+// NewSandbox 기본 한도의 샌드박스. cpuTime은 실행 제한 시간보다 커야 제한 시간 초과가 먼저 보고됨
+func NewSandbox(cpuTime time.Duration) *Sandbox {
+	return &Sandbox{
+		UID:         sandboxNobody,
+		GID:         sandboxNobody,
+		CPUTime:     cpuTime,
+		MemoryBytes: 2 << 30,
+		FileBytes:   64 << 20,
+		OpenFiles:   256,
+	}
+}
+
+// Command name을 샌드박스에서 실행하는 명령. name은 정적 링크된 바이너리여야 하고(루트에는 name만 있음),
+// dir은 샌드박스의 루트가 되는 빈 디렉터리로 PrepareDir로 쓰기 권한을 준 디렉터리여야 함
+func (s *Sandbox) Command(ctx context.Context, dir string, env []string, name string, args ...string) (*exec.Cmd, error) {
+	attr, err := s.sysProcAttr()
+	if err != nil {
+		return nil, err
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("sandbox requires the service executable: %v", err)
+	}
+	initArgs := []string{
+		strconv.FormatInt(int64((s.CPUTime+time.Second-1)/time.Second), 10),
+		strconv.FormatInt(s.MemoryBytes, 10),
+		strconv.FormatInt(s.FileBytes, 10),
+		strconv.Itoa(s.OpenFiles),
+		strconv.Itoa(s.UID),
+		strconv.Itoa(s.GID),
+		name,
+	}
+	cmd := exec.CommandContext(ctx, self, append(initArgs, args...)...)
+	cmd.Args[0] = sandboxInitName
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.SysProcAttr = attr
+	return cmd, nil
+}
+
+// PrepareDir 샌드박스 사용자가 dir에 쓸 수 있도록 소유자를 바꿈 (root가 아니면 서비스 사용자가 샌드박스 사용자로 매핑되므로 변경 없음)
+func (s *Sandbox) PrepareDir(dir string) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	return os.Chown(dir, s.UID, s.GID)
+}
+
+// Available 이 환경에서 샌드박스를 만들 수 있는지 확인 (컨테이너의 seccomp/AppArmor 프로필이 user namespace나 mount를 막으면 실패).
+// 대상 없이 격리만 수행하고 종료하는 초기화 프로세스를 실행
+func (s *Sandbox) Available() error {
+	dir, err := os.MkdirTemp("", "codev42-sandbox-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := s.PrepareDir(dir); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd, err := s.Command(ctx, dir, nil, "")
+	if err != nil {
+		return err
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("sandbox is not available: %v %s", err, output)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	capSysAdmin          = 21
+	prSetNoNewPrivs      = 38
+	prCapAmbient         = 47
+	prCapAmbientClearAll = 4
+)
+
+func init() {
+	if len(os.Args) > 0 && os.Args[0] == sandboxInitName {
+		sandboxInit(os.Args[1:])
+	}
+}
+
+// sysProcAttr 초기화 프로세스는 새 user namespace에서 실행되므로 서비스 컨테이너에 capability가 필요 없음.
+// root로 실행 중이면 namespace의 root(mount용)와 nobody를 호스트의 같은 ID로 매핑하고,
+// 아니면 서비스 사용자를 namespace의 nobody로 매핑하고 mount에 필요한 capability만 유지
+func (s *Sandbox) sysProcAttr() (*syscall.SysProcAttr, error) {
+	attr := &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC,
+		Pdeathsig:  syscall.SIGKILL,
+	}
+	if os.Geteuid() == 0 {
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: 0, Size: 1}, {ContainerID: s.UID, HostID: s.UID, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: 0, Size: 1}, {ContainerID: s.GID, HostID: s.GID, Size: 1}}
+		attr.GidMappingsEnableSetgroups = true
+		return attr, nil
+	}
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: s.UID, HostID: os.Getuid(), Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: s.GID, HostID: os.Getgid(), Size: 1}}
+	attr.AmbientCaps = []uintptr{capSysAdmin}
+	return attr, nil
+}
+
+// sandboxInit 샌드박스 초기화 프로세스. 인자는 CPU 시간(초), 메모리, 파일 크기, 파일 수, 사용자, 그룹, 대상, 대상의 인자.
+// 대상이 비어 있으면 격리만 확인하고 종료
+func sandboxInit(args []string) {
+	if err := enterSandbox(args); err != nil {
+		fmt.Fprintf(os.Stderr, "sandbox: %v\n", err)
+		os.Exit(126)
+	}
+	os.Exit(0)
+}
+
+func enterSandbox(args []string) error {
+	if len(args) < 7 {
+		return fmt.Errorf("invalid arguments")
+	}
+	var limits [6]int64
+	for i := range limits {
+		value, err := strconv.ParseInt(args[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid argument %q", args[i])
+		}
+		limits[i] = value
+	}
+	target := args[6]
+
+	// capability, no_new_privs는 스레드별이므로 exec까지 같은 스레드에서 수행
+	runtime.LockOSThread()
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	// 실행 디렉터리를 새 루트로 만들고 대상 바이너리만 읽기 전용으로 마운트
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %v", err)
+	}
+	if err := syscall.Mount(root, root, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to mount root: %v", err)
+	}
+	binary := ""
+	if target != "" {
+		binary = "/" + filepath.Base(target)
+		mountPoint := filepath.Join(root, binary)
+		file, err := os.OpenFile(mountPoint, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o500)
+		if err != nil {
+			return err
+		}
+		file.Close()
+		if err := syscall.Mount(target, mountPoint, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("failed to mount %s: %v", target, err)
+		}
+		if err := syscall.Mount("", mountPoint, "", syscall.MS_REMOUNT|syscall.MS_BIND|syscall.MS_RDONLY|syscall.MS_NOSUID|syscall.MS_NODEV, ""); err != nil {
+			return fmt.Errorf("failed to remount %s read-only: %v", target, err)
+		}
+	}
+	// 기존 루트 위에 새 루트를 올린 뒤 기존 루트를 분리
+	if err := syscall.Chdir(root); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("failed to pivot root: %v", err)
+	}
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach old root: %v", err)
+	}
+	if err := syscall.Chdir("/"); err != nil {
+		return err
+	}
+
+	for resource, limit := range map[int]int64{
+		syscall.RLIMIT_CPU:    limits[0],
+		syscall.RLIMIT_AS:     limits[1],
+		syscall.RLIMIT_FSIZE:  limits[2],
+		syscall.RLIMIT_NOFILE: limits[3],
+	} {
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: uint64(limit), Max: uint64(limit)}); err != nil {
+			return fmt.Errorf("failed to set resource limit %d: %v", resource, err)
+		}
+	}
+
+	// 권한 없는 사용자로 전환하고 capability를 모두 버림 (root면 setuid로 모두 사라짐)
+	if os.Getuid() == 0 {
+		if err := syscall.Setgroups(nil); err != nil {
+			return fmt.Errorf("failed to drop groups: %v", err)
+		}
+		if err := syscall.Setgid(int(limits[5])); err != nil {
+			return fmt.Errorf("failed to switch group: %v", err)
+		}
+		if err := syscall.Setuid(int(limits[4])); err != nil {
+			return fmt.Errorf("failed to switch user: %v", err)
+		}
+	}
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prCapAmbient, prCapAmbientClearAll, 0, 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to clear ambient capabilities: %v", errno)
+	}
+	if err := dropCapabilities(); err != nil {
+		return err
+	}
+	if _, _, errno := syscall.RawSyscall6(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0, 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to set no_new_privs: %v", errno)
+	}
+
+	if binary == "" {
+		return nil
+	}
+	return syscall.Exec(binary, append([]string{binary}, args[7:]...), os.Environ())
+}
+
+// dropCapabilities 현재 스레드의 capability를 모두 버림
+func dropCapabilities() error {
+	header := struct {
+		version uint32
+		pid     int32
+	}{version: 0x20080522} // _LINUX_CAPABILITY_VERSION_3
+	var data [2]struct {
+		effective, permitted, inheritable uint32
+	}
+	if _, _, errno := syscall.RawSyscall(syscall.SYS_CAPSET, uintptr(unsafe.Pointer(&header)), uintptr(unsafe.Pointer(&data[0])), 0); errno != 0 {
+		return fmt.Errorf("failed to drop capabilities: %v", errno)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package service
+
+import (
+	"errors"
+	"syscall"
+)
+
+// sysProcAttr namespace는 Linux에서만 만들 수 있으므로 다른 OS에서는 테스트 단계를 사용하지 않음
+func (s *Sandbox) sysProcAttr() (*syscall.SysProcAttr, error) {
+	return nil, errors.New("sandbox requires Linux namespaces")
+}
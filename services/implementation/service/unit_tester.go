@@ -0,0 +1,644 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// 실패한 테스트 출력은 프롬프트와 결과에 넣기 위해 잘라서 보관
+	maxTestOutputLength = 2000
+
+	// 테스트 실행 제한 시간과 별도로 허용하는 빌드 시간 (빌드 캐시가 비어 있을 때 표준 라이브러리 컴파일 포함)
+	testBuildTimeout = 2 * time.Minute
+)
+
+// TestResult 생성된 테스트 하나의 실행 결과
+type TestResult struct {
+	Name       string // Go 테스트 이름 (서브테스트는 TestName/case)
+	Annotation string // 검증 대상 함수 이름
+	Passed     bool
+	Output     string // 실패 시 출력
+}
+
+// UnitTester 어노테이션마다 테이블 기반 테스트를 생성하고 임시 모듈에서 go test로 실행.
+// 테스트가 실패한 함수는 정해진 횟수만큼 다시 구현
+type UnitTester struct {
+	agent     *WorkerAgent
+	goBinary  string
+	timeout   time.Duration
+	maxRounds int
+	cacheDir  string // 실행 간 공유하는 빌드 캐시
+	homeDir   string // go 명령의 설정/텔레메트리 파일이 쓰이는 HOME
+	sandbox   *Sandbox
+
+	sandboxOnce sync.Once
+	sandboxErr  error
+}
+
+func NewUnitTester(agent *WorkerAgent, goBinary string, timeout time.Duration, maxRounds int) *UnitTester {
+	return &UnitTester{
+		agent:     agent,
+		goBinary:  goBinary,
+		timeout:   timeout,
+		maxRounds: maxRounds,
+		cacheDir:  filepath.Join(os.TempDir(), "codev42-gocache"),
+		homeDir:   filepath.Join(os.TempDir(), "codev42-gohome"),
+		sandbox:   NewSandbox(2 * timeout),
+	}
+}
+
+// Supports Go 코드이고 go 명령과 샌드박스를 사용할 수 있을 때만 테스트 단계를 수행
+func (t *UnitTester) Supports(language string) bool {
+	switch strings.ToLower(strings.TrimSpace(language)) {
+	case "go", "golang":
+	default:
+		return false
+	}
+	if _, err := exec.LookPath(t.goBinary); err != nil {
+		return false
+	}
+	t.sandboxOnce.Do(func() {
+		if t.sandboxErr = t.sandbox.Available(); t.sandboxErr != nil {
+			log.Printf("Unit test stage disabled: %v", t.sandboxErr)
+		}
+	})
+	return t.sandboxErr == nil
+}
+
+// 생성된 테스트 파일과 검증 대상
+type generatedTest struct {
+	path       string
+	code       string
+	fileIndex  int
+	annotation string
+	names      []string // 파일에 정의된 최상위 테스트 함수
+	decls      []string // 파일에 정의된 최상위 선언 (테스트 함수 포함)
+}
+
+// Run 파일마다 테스트를 생성하여 실행하고 결과를 files[i].Tests에 기록.
+// 이전 실행에서 가져온 파일은 테스트 결과를 유지하고, 구현에 실패한 파일은 제외
+func (t *UnitTester) Run(ctx context.Context, language string, files []GeneratedFile) error {
+	tests, buildFailures, err := t.generateTests(ctx, language, files)
+	if err != nil {
+		return err
+	}
+
+	for round := 0; ; round++ {
+		results, err := t.execute(ctx, files, tests)
+		if err != nil {
+			return err
+		}
+		for i := range files {
+			if files[i].reused {
+				continue
+			}
+			files[i].Tests = append(append([]TestResult{}, buildFailures[i]...), results[i]...)
+		}
+
+		failures := failingFunctions(results)
+		if len(failures) == 0 || round >= t.maxRounds {
+			return nil
+		}
+
+		// 실패한 함수가 있는 파일만 다시 구현 (컴파일 검사를 통과한 경우에만 교체)
+		checker := CheckerForLanguage(language)
+		keys := goPackageKeys(files)
+		for index, failed := range failures {
+			file := &files[index]
+			log.Printf("Regenerating %d failing functions in %s (round %d)", len(failed), file.Path, round+1)
+			fixed, err := t.agent.fixFailingFunctions(ctx, language, formatPlan(*file.plan), file.Code, failed)
+			if err != nil {
+				return err
+			}
+			candidate := *file
+			candidate.Code = fixed.Code
+			if checker != nil {
+				if diagnostics := checker.Check(candidate, samePackage(files, keys, keys[index])); len(diagnostics) > 0 {
+					log.Printf("Discarding regenerated %s: %d diagnostics", file.Path, len(diagnostics))
+					continue
+				}
+			}
+			file.Code = fixed.Code
+			file.Diagnostics = nil
+			if conformance := ConformanceCheckerForLanguage(language); conformance != nil {
+				file.Conformance = conformance.Check(*file.plan, *file)
+			}
+		}
+	}
+}
+
+// generateTests 어노테이션마다 테스트 파일을 병렬로 생성. 컴파일되지 않는 테스트는 실행에서 제외하고 실패로 기록
+func (t *UnitTester) generateTests(ctx context.Context, language string, files []GeneratedFile) ([]generatedTest, [][]TestResult, error) {
+	var (
+		mu            sync.Mutex
+		wg            sync.WaitGroup
+		tests         []generatedTest
+		buildFailures = make([][]TestResult, len(files))
+	)
+	errorChan := make(chan error, 1)
+	checker := CheckerForLanguage(language)
+	keys := goPackageKeys(files)
+
+	for i := range files {
+		file := files[i]
+		if file.plan == nil || file.reused || file.Status != PlanStatusCompleted || len(file.Diagnostics) > 0 || keys[i] == "" {
+			continue
+		}
+		packageName := goPackageName(file)
+		related := samePackage(files, keys, keys[i])
+		base := strings.TrimSuffix(file.Path, filepath.Ext(file.Path))
+
+		for _, annotation := range file.plan.Annotations {
+			wg.Add(1)
+			go func(index int, annotation Annotation) {
+				defer wg.Done()
+				result, err := t.agent.generateTest(ctx, language, formatPlan(*file.plan), file.Code, annotation, packageName)
+				if err != nil {
+					select {
+					case errorChan <- fmt.Errorf("failed to generate test for %s: %v", annotation.Name, err):
+					default:
+					}
+					return
+				}
+
+				test := generatedTest{
+					path:       base + "_" + toSnakeCase(annotation.Name) + "_test.go",
+					fileIndex:  index,
+					annotation: annotation.Name,
+				}
+				// 같은 패키지에 합쳐지므로 다른 어노테이션의 테스트와 이름이 겹치지 않게 바꾼 뒤 검사
+				var diagnostics []Diagnostic
+				test.code, test.decls, err = scopeTestDeclarations(test.path, result.Code, file.plan.ClassName, annotation.Name)
+				if err != nil {
+					diagnostics = []Diagnostic{{Path: test.path, Message: err.Error()}}
+				} else if checker != nil {
+					diagnostics = checker.Check(GeneratedFile{Path: test.path, Code: test.code}, related)
+				}
+				test.names = goTestNames(test.path, test.code)
+				if len(test.names) == 0 && len(diagnostics) == 0 {
+					diagnostics = []Diagnostic{{Path: test.path, Message: "no Test functions generated"}}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				if len(diagnostics) > 0 {
+					var output []string
+					for _, diagnostic := range diagnostics {
+						output = append(output, diagnostic.String())
+					}
+					buildFailures[index] = append(buildFailures[index], TestResult{
+						Name:       test.path,
+						Annotation: annotation.Name,
+						Output:     truncateOutput(strings.Join(output, "\n")),
+					})
+					return
+				}
+				tests = append(tests, test)
+			}(i, annotation)
+		}
+	}
+
+	wg.Wait()
+	close(errorChan)
+	if err, ok := <-errorChan; ok {
+		return nil, nil, err
+	}
+
+	// 이름을 바꾼 뒤에도 같은 패키지에 겹치는 선언이 있으면 (같은 클래스와 어노테이션의 계획이 중복) 나중 파일을 실행에서 제외
+	sort.Slice(tests, func(a, b int) bool { return tests[a].path < tests[b].path })
+	declared := make(map[string]map[string]string)
+	accepted := tests[:0]
+	for _, test := range tests {
+		key := keys[test.fileIndex]
+		if declared[key] == nil {
+			declared[key] = make(map[string]string)
+		}
+		var conflicts []string
+		for _, name := range test.decls {
+			if path, ok := declared[key][name]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s is already declared in %s", test.path, name, path))
+			}
+		}
+		if len(conflicts) > 0 {
+			buildFailures[test.fileIndex] = append(buildFailures[test.fileIndex], TestResult{
+				Name:       test.path,
+				Annotation: test.annotation,
+				Output:     truncateOutput(strings.Join(conflicts, "\n")),
+			})
+			continue
+		}
+		for _, name := range test.decls {
+			declared[key][name] = test.path
+		}
+		accepted = append(accepted, test)
+	}
+	return accepted, buildFailures, nil
+}
+
+// scopeTestDeclarations 테스트 파일의 최상위 선언 이름 앞에 클래스와 어노테이션 이름을 붙임.
+// 테스트 파일은 모두 한 패키지에 합쳐지므로 다른 클래스의 같은 이름 어노테이션(New, String 등) 테스트나 보조 함수와 겹치지 않게 함.
+// TestNew → TestUserService_New, TestNewEmpty → TestUserService_New_NewEmpty, newFixture → userService_New_newFixture.
+// 바꾼 코드와 최상위 선언 이름을 반환하며, 문법 오류가 있으면 그대로 반환 (검사기에서 진단)
+func scopeTestDeclarations(path string, code string, className string, annotation string) (string, []string, error) {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, path, code, parser.ParseComments)
+	if err != nil {
+		return code, nil, nil
+	}
+
+	scope := goIdentifier(annotation)
+	if class := goIdentifier(className); class != "" {
+		scope = class + "_" + scope
+	}
+	if scope == "" {
+		return code, nil, nil
+	}
+	first, size := utf8.DecodeRuneInString(scope)
+	helperPrefix := string(unicode.ToLower(first)) + scope[size:] + "_"
+	rename := func(name string) string {
+		if !isGoTestName(name) {
+			return helperPrefix + name
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(name, "Test"), "_")
+		if rest == "" || rest == goIdentifier(annotation) {
+			return "Test" + scope
+		}
+		return "Test" + scope + "_" + rest
+	}
+
+	// 최상위 선언과 파일 안의 참조는 같은 ast.Object를 가리키므로 Object 기준으로 바꿈 (지역 변수로 가려진 이름은 유지)
+	renames := make(map[*ast.Object]string)
+	declare := func(ident *ast.Ident) {
+		if ident.Name == "_" || ident.Obj == nil {
+			return
+		}
+		renames[ident.Obj] = rename(ident.Name)
+	}
+	for _, decl := range parsed.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil || decl.Name.Name == "init" {
+				continue
+			}
+			if decl.Name.Name == "TestMain" {
+				return code, nil, fmt.Errorf("%s: TestMain is not allowed in generated tests", path)
+			}
+			declare(decl.Name)
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					declare(spec.Name)
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						declare(name)
+					}
+				}
+			}
+		}
+	}
+	if len(renames) == 0 {
+		return code, nil, nil
+	}
+
+	ast.Inspect(parsed, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Obj != nil {
+			if name, ok := renames[ident.Obj]; ok {
+				ident.Name = name
+			}
+		}
+		return true
+	})
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, parsed); err != nil {
+		return code, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	decls := make([]string, 0, len(renames))
+	for _, name := range renames {
+		decls = append(decls, name)
+	}
+	sort.Strings(decls)
+	return buf.String(), decls, nil
+}
+
+// goIdentifier name에서 Go 식별자에 쓸 수 없는 문자를 뺌
+func goIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// go test -json 이벤트
+type testEvent struct {
+	Action string
+	Test   string
+	Output string
+}
+
+// execute 코드와 테스트를 패키지(디렉터리와 패키지 이름)별 임시 모듈에 쓰고, 패키지마다 테스트 바이너리를 빌드하여 샌드박스에서 실행.
+// 한 패키지의 빌드 실패는 그 패키지의 테스트만 실패로 기록. 결과는 파일 index별로 반환
+func (t *UnitTester) execute(ctx context.Context, files []GeneratedFile, tests []generatedTest) ([][]TestResult, error) {
+	results := make([][]TestResult, len(files))
+	if len(tests) == 0 {
+		return results, nil
+	}
+
+	dir, err := os.MkdirTemp("", "codev42-impl-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test module: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// go는 TMPDIR 바로 아래의 go.mod를 무시하므로 모듈과 임시 디렉터리를 분리.
+	// 패키지마다 work 아래의 빈 디렉터리가 샌드박스의 루트가 됨 (테스트 바이너리만 마운트)
+	modulesDir := filepath.Join(dir, "modules")
+	tmpDir := filepath.Join(dir, "tmp")
+	binDir := filepath.Join(dir, "bin")
+	workDir := filepath.Join(dir, "work")
+	for _, d := range []string{modulesDir, tmpDir, binDir, workDir} {
+		if err := os.Mkdir(d, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create test module: %v", err)
+		}
+	}
+
+	// 빌드는 생성 코드를 실행하지 않으므로 샌드박스 밖에서 수행. 네트워크(GOPROXY=off), cgo, 툴체인 다운로드는 차단
+	goEnv := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + t.homeDir,
+		"TMPDIR=" + tmpDir,
+		"GOPATH=" + filepath.Join(t.homeDir, "go"),
+		"GOCACHE=" + t.cacheDir,
+		"GOFLAGS=-mod=mod",
+		"GOPROXY=off",
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+		"CGO_ENABLED=0",
+	}
+
+	keys := goPackageKeys(files)
+	var packages []string
+	packageTests := make(map[string][]generatedTest)
+	for _, test := range tests {
+		key := keys[test.fileIndex]
+		if _, ok := packageTests[key]; !ok {
+			packages = append(packages, key)
+		}
+		packageTests[key] = append(packageTests[key], test)
+	}
+	sort.Strings(packages)
+
+	for i, key := range packages {
+		moduleDir := filepath.Join(modulesDir, strconv.Itoa(i))
+		sources := map[string]string{"go.mod": "module generated\n\ngo 1.21\n"}
+		for index, file := range files {
+			if keys[index] == key {
+				sources[file.Path] = file.Code
+			}
+		}
+		for _, test := range packageTests[key] {
+			sources[test.path] = test.code
+		}
+		for name, content := range sources {
+			target := filepath.Join(moduleDir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return nil, fmt.Errorf("failed to create test module: %v", err)
+			}
+			if err := os.WriteFile(target, []byte(content), 0o600); err != nil {
+				return nil, fmt.Errorf("failed to write %s: %v", name, err)
+			}
+		}
+
+		rootDir := filepath.Join(workDir, strconv.Itoa(i))
+		if err := os.Mkdir(rootDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create sandbox root: %v", err)
+		}
+		if err := t.sandbox.PrepareDir(rootDir); err != nil {
+			return nil, fmt.Errorf("failed to prepare sandbox: %v", err)
+		}
+
+		packageDir, _, _ := strings.Cut(key, ":")
+		testBinary := filepath.Join(binDir, strconv.Itoa(i)+".test")
+		if err := t.executePackage(ctx, moduleDir, "./"+packageDir, testBinary, rootDir, goEnv, packageTests[key], results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// executePackage pattern 패키지의 테스트 바이너리를 빌드하고 샌드박스에서 실행하여 결과를 results에 추가
+func (t *UnitTester) executePackage(ctx context.Context, moduleDir string, pattern string, testBinary string, rootDir string, goEnv []string, tests []generatedTest, results [][]TestResult) error {
+	owners := make(map[string]*generatedTest)
+	for i := range tests {
+		for _, name := range tests[i].names {
+			owners[name] = &tests[i]
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, t.timeout+testBuildTimeout)
+	defer cancel()
+
+	build := exec.CommandContext(runCtx, t.goBinary, "test", "-c", "-o", testBinary, pattern)
+	build.Dir = moduleDir
+	build.Env = goEnv
+	build.WaitDelay = 5 * time.Second
+	var stdout, stderr bytes.Buffer
+	build.Stderr = &stderr
+	runErr := build.Run()
+
+	if runErr == nil {
+		if err := os.Chmod(testBinary, 0o755); err != nil {
+			return fmt.Errorf("failed to prepare sandbox: %v", err)
+		}
+		// 테스트 바이너리는 rootDir만 보이고 네트워크, 권한, 자원 한도가 제한된 샌드박스에서 실행
+		cmd, err := t.sandbox.Command(runCtx, rootDir, []string{"HOME=/", "TMPDIR=/"},
+			testBinary, "-test.v=test2json", "-test.count=1", "-test.timeout="+t.timeout.String())
+		if err != nil {
+			return err
+		}
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &stderr
+		cmd.WaitDelay = 5 * time.Second
+		runErr = cmd.Run()
+
+		// 출력은 샌드박스 밖에서 go test -json과 같은 이벤트로 변환
+		convert := exec.CommandContext(runCtx, t.goBinary, "tool", "test2json", "-t")
+		convert.Env = goEnv
+		convert.Stdin = &output
+		convert.Stdout = &stdout
+		convert.Stderr = &stderr
+		if err := convert.Run(); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("failed to convert test output: %v", err)
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	outputs := make(map[string]*strings.Builder)
+	finished := make(map[string]bool)
+	var packageOutput strings.Builder
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if event.Test == "" {
+			packageOutput.WriteString(event.Output)
+			continue
+		}
+		top, _, _ := strings.Cut(event.Test, "/")
+		owner, ok := owners[top]
+		if !ok {
+			continue
+		}
+		if outputs[event.Test] == nil {
+			outputs[event.Test] = &strings.Builder{}
+		}
+		switch event.Action {
+		case "output":
+			outputs[event.Test].WriteString(event.Output)
+		case "pass", "fail", "skip":
+			finished[event.Test] = true
+			result := TestResult{
+				Name:       event.Test,
+				Annotation: owner.annotation,
+				Passed:     event.Action != "fail",
+			}
+			if !result.Passed {
+				result.Output = truncateOutput(outputs[event.Test].String())
+			}
+			results[owner.fileIndex] = append(results[owner.fileIndex], result)
+		}
+	}
+
+	// 빌드 실패, 패닉, 타임아웃으로 끝나지 않은 테스트는 패키지 출력과 함께 실패로 기록
+	failure := packageOutput.String() + stderr.String()
+	if runErr != nil && failure == "" {
+		failure = runErr.Error()
+	}
+	for _, test := range tests {
+		for _, name := range test.names {
+			if finished[name] {
+				continue
+			}
+			output := failure
+			if outputs[name] != nil {
+				output = outputs[name].String() + failure
+			}
+			results[test.fileIndex] = append(results[test.fileIndex], TestResult{
+				Name:       name,
+				Annotation: test.annotation,
+				Output:     truncateOutput(output),
+			})
+		}
+	}
+	return nil
+}
+
+// failingFunctions 파일 index별로 실패한 함수와 실패 출력
+func failingFunctions(results [][]TestResult) map[int]map[string]string {
+	failures := make(map[int]map[string]string)
+	for index, fileResults := range results {
+		for _, result := range fileResults {
+			if result.Passed {
+				continue
+			}
+			if failures[index] == nil {
+				failures[index] = make(map[string]string)
+			}
+			failures[index][result.Annotation] += result.Name + ":\n" + result.Output + "\n"
+		}
+	}
+	return failures
+}
+
+// goPackageKeys 파일마다 패키지를 구분하는 키 (디렉터리:패키지 이름). 같은 키의 파일만 함께 빌드하고 테스트.
+// 구현되지 않았거나 경로가 모듈 밖을 가리키는 파일은 빈 문자열
+func goPackageKeys(files []GeneratedFile) []string {
+	keys := make([]string, len(files))
+	for i, file := range files {
+		if file.Status != PlanStatusCompleted || !filepath.IsLocal(filepath.FromSlash(file.Path)) {
+			continue
+		}
+		keys[i] = path.Dir(filepath.ToSlash(file.Path)) + ":" + goPackageName(file)
+	}
+	return keys
+}
+
+// samePackage key 패키지에 속한 파일
+func samePackage(files []GeneratedFile, keys []string, key string) []GeneratedFile {
+	var related []GeneratedFile
+	for i, file := range files {
+		if keys[i] == key {
+			related = append(related, file)
+		}
+	}
+	return related
+}
+
+// goPackageName 생성 코드의 패키지 이름 (알 수 없으면 main)
+func goPackageName(file GeneratedFile) string {
+	parsed, err := parser.ParseFile(token.NewFileSet(), file.Path, file.Code, parser.PackageClauseOnly)
+	if err != nil {
+		return "main"
+	}
+	return parsed.Name.Name
+}
+
+// goTestNames 테스트 파일에 정의된 최상위 TestXxx 함수 이름
+func goTestNames(path string, code string) []string {
+	parsed, err := parser.ParseFile(token.NewFileSet(), path, code, 0)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, decl := range parsed.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok && fn.Recv == nil && isGoTestName(fn.Name.Name) {
+			names = append(names, fn.Name.Name)
+		}
+	}
+	return names
+}
+
+// isGoTestName go test가 테스트 함수로 실행하는 이름인지 여부 (Test 뒤가 소문자이면 테스트가 아님)
+func isGoTestName(name string) bool {
+	if !strings.HasPrefix(name, "Test") {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(name[len("Test"):])
+	return next == utf8.RuneError || !unicode.IsLower(next)
+}
+
+func truncateOutput(output string) string {
+	if len(output) > maxTestOutputLength {
+		return output[:maxTestOutputLength] + "\n..."
+	}
+	return output
+}
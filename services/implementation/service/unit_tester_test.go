@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"codev42-llm/client"
+)
+
+// promptProvider 프롬프트에 따라 응답하는 LLM (테스트는 병렬로 생성되므로 순서 대신 내용으로 응답을 고름)
+type promptProvider struct {
+	respond func(prompt string) string
+}
+
+func (p promptProvider) ChatJSON(ctx context.Context, req client.ChatRequest) (string, error) {
+	return p.respond(req.Prompt), nil
+}
+
+func (p promptProvider) Embed(ctx context.Context, req client.EmbeddingRequest) ([]float64, error) {
+	return nil, errors.New("embedding is not supported")
+}
+
+// newSandboxedTester go 명령과 샌드박스를 사용할 수 없는 환경에서는 건너뜀
+func newSandboxedTester(t *testing.T) *UnitTester {
+	t.Helper()
+	tester := NewUnitTester(nil, "go", 30*time.Second, 0)
+	if _, err := exec.LookPath(tester.goBinary); err != nil {
+		t.Skip("go is not installed")
+	}
+	if err := tester.sandbox.Available(); err != nil {
+		t.Skip(err)
+	}
+	return tester
+}
+
+func TestUnitTesterRunsGeneratedTestsInSandbox(t *testing.T) {
+	tester := newSandboxedTester(t)
+
+	// 샌드박스 밖에서 열린 포트에 접속할 수 있으면 네트워크가 격리되지 않은 것
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	// 서비스 컨테이너의 파일 (누구나 읽을 수 있어도 샌드박스에서는 보이지 않아야 함)
+	secretDir, err := os.MkdirTemp("", "codev42-secret-")
+	if err != nil {
+		t.Fatalf("failed to create secret: %v", err)
+	}
+	defer os.RemoveAll(secretDir)
+	secret := filepath.Join(secretDir, "secret")
+	if err := errors.Join(os.Chmod(secretDir, 0o755), os.WriteFile(secret, []byte("secret"), 0o644)); err != nil {
+		t.Fatalf("failed to write secret: %v", err)
+	}
+
+	files := []GeneratedFile{{
+		Path:   "calc.go",
+		Status: PlanStatusCompleted,
+		Code:   "package calc\n\nfunc Add(a, b int) int { return a + b }\n",
+	}}
+	tests := []generatedTest{
+		{
+			path:       "calc_add_test.go",
+			annotation: "Add",
+			names:      []string{"TestAdd", "TestAddWrong"},
+			code: `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	if Add(1, 2) != 3 {
+		t.Fatal("wrong sum")
+	}
+}
+
+func TestAddWrong(t *testing.T) {
+	t.Fatalf("expected %d", Add(1, 1)+1)
+}
+`,
+		},
+		{
+			path:       "calc_sandbox_test.go",
+			annotation: "Sandbox",
+			names:      []string{"TestSandbox"},
+			code: fmt.Sprintf(`package calc
+
+import (
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSandbox(t *testing.T) {
+	if conn, err := net.DialTimeout("tcp", %q, time.Second); err == nil {
+		conn.Close()
+		t.Fatal("network is reachable")
+	}
+	if os.Geteuid() == 0 {
+		t.Fatal("running as root")
+	}
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_AS, &limit); err != nil || limit.Max != %d {
+		t.Fatalf("memory is not limited: %%+v %%v", limit, err)
+	}
+	if err := syscall.Setrlimit(syscall.RLIMIT_AS, &syscall.Rlimit{Cur: limit.Max * 2, Max: limit.Max * 2}); err == nil {
+		t.Fatal("memory limit can be raised")
+	}
+	if _, err := os.Stat(%q); err == nil {
+		t.Fatal("service files are visible")
+	}
+	entries, err := os.ReadDir("/")
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("root contains more than the test binary: %%v %%v", entries, err)
+	}
+	if err := os.WriteFile("/"+entries[0].Name(), nil, 0o600); err == nil {
+		t.Fatal("test binary is writable")
+	}
+	if err := syscall.Mount("none", "/", "tmpfs", 0, ""); err == nil {
+		t.Fatal("mount is allowed")
+	}
+}
+`, listener.Addr().String(), uint64(tester.sandbox.MemoryBytes), secret),
+		},
+	}
+
+	results, err := tester.execute(context.Background(), files, tests)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+
+	got := make(map[string]TestResult)
+	for _, result := range results[0] {
+		got[result.Name] = result
+	}
+	if result := got["TestAdd"]; !result.Passed || result.Annotation != "Add" {
+		t.Fatalf("TestAdd: %+v", result)
+	}
+	if result := got["TestAddWrong"]; result.Passed || !strings.Contains(result.Output, "expected 3") {
+		t.Fatalf("TestAddWrong: %+v", result)
+	}
+	if result := got["TestSandbox"]; !result.Passed {
+		t.Fatalf("generated code escaped the sandbox: %s", result.Output)
+	}
+}
+
+func TestUnitTesterKillsTimedOutTests(t *testing.T) {
+	tester := newSandboxedTester(t)
+	tester.timeout = time.Second
+
+	files := []GeneratedFile{{Path: "loop.go", Status: PlanStatusCompleted, Code: "package loop\n\nfunc Spin() {\n\tfor {\n\t}\n}\n"}}
+	tests := []generatedTest{{
+		path:       "loop_spin_test.go",
+		annotation: "Spin",
+		names:      []string{"TestSpin"},
+		code:       "package loop\n\nimport \"testing\"\n\nfunc TestSpin(t *testing.T) { Spin() }\n",
+	}}
+
+	results, err := tester.execute(context.Background(), files, tests)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(results[0]) != 1 || results[0][0].Passed || !strings.Contains(results[0][0].Output, "test timed out") {
+		t.Fatalf("expected timed out test, got %+v", results[0])
+	}
+}
+
+func TestUnitTesterScopesTestsOfSameAnnotationInDifferentClasses(t *testing.T) {
+	tester := newSandboxedTester(t)
+
+	// 두 클래스의 String 테스트가 같은 테스트 함수, 타입, 보조 함수를 선언
+	testCode := func(class string, want string) string {
+		return fmt.Sprintf(`package shapes
+
+import "testing"
+
+type testCase struct {
+	name string
+	want string
+}
+
+func newCases() []testCase {
+	return []testCase{{name: "default", want: %q}}
+}
+
+func TestString(t *testing.T) {
+	for _, tc := range newCases() {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (%s{}).String(); got != tc.want {
+				t.Fatalf("got %%q, want %%q", got, tc.want)
+			}
+		})
+	}
+}
+`, want, class)
+	}
+	tester.agent = &WorkerAgent{LLM: promptProvider{respond: func(prompt string) string {
+		code := testCode("Circle", "circle")
+		if strings.Contains(prompt, "className: Box") {
+			code = testCode("Box", "box")
+		}
+		content, _ := json.Marshal(ImplementResult{Code: code})
+		return string(content)
+	}}}
+
+	files := []GeneratedFile{
+		{
+			Path:   "box.go",
+			Status: PlanStatusCompleted,
+			Code:   "package shapes\n\ntype Box struct{}\n\nfunc (Box) String() string { return \"box\" }\n",
+			plan:   &Plan{ID: 1, ClassName: "Box", Annotations: []Annotation{{Name: "String", Returns: "string"}}},
+		},
+		{
+			Path:   "circle.go",
+			Status: PlanStatusCompleted,
+			Code:   "package shapes\n\ntype Circle struct{}\n\nfunc (Circle) String() string { return \"circle\" }\n",
+			plan:   &Plan{ID: 2, ClassName: "Circle", Annotations: []Annotation{{Name: "String", Returns: "string"}}},
+		},
+	}
+	if err := tester.Run(context.Background(), "go", files); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	for i, want := range []string{"TestBox_String/default", "TestCircle_String/default"} {
+		passed := false
+		for _, result := range files[i].Tests {
+			if !result.Passed {
+				t.Fatalf("%s: test %s failed: %s", files[i].Path, result.Name, result.Output)
+			}
+			passed = passed || result.Name == want
+		}
+		if !passed {
+			t.Fatalf("%s: expected %s to pass, got %+v", files[i].Path, want, files[i].Tests)
+		}
+	}
+}
+
+func TestUnitTesterBuildsEachPackageSeparately(t *testing.T) {
+	tester := newSandboxedTester(t)
+
+	// 다른 디렉터리의 같은 파일 이름, 같은 디렉터리의 다른 패키지 이름, 빌드되지 않는 패키지
+	source := func(pkg string, value int) string {
+		return fmt.Sprintf("package %s\n\nfunc Value() int { return %d }\n", pkg, value)
+	}
+	test := func(pkg string, name string, value int) string {
+		return fmt.Sprintf("package %s\n\nimport \"testing\"\n\nfunc %s(t *testing.T) {\n\tif Value() != %d {\n\t\tt.Fatal(\"wrong value\")\n\t}\n}\n", pkg, name, value)
+	}
+	files := []GeneratedFile{
+		{Path: "alpha/util.go", Status: PlanStatusCompleted, Code: source("alpha", 1)},
+		{Path: "beta/util.go", Status: PlanStatusCompleted, Code: source("beta", 2)},
+		{Path: "gamma.go", Status: PlanStatusCompleted, Code: source("gamma", 3)},
+		{Path: "delta.go", Status: PlanStatusCompleted, Code: source("delta", 4)},
+		{Path: "broken.go", Status: PlanStatusCompleted, Code: "package broken\n"},
+	}
+	tests := []generatedTest{
+		{path: "alpha/util_value_test.go", fileIndex: 0, annotation: "Value", names: []string{"TestValue"}, code: test("alpha", "TestValue", 1)},
+		{path: "beta/util_value_test.go", fileIndex: 1, annotation: "Value", names: []string{"TestValue"}, code: test("beta", "TestValue", 2)},
+		{path: "gamma_value_test.go", fileIndex: 2, annotation: "Value", names: []string{"TestGamma"}, code: test("gamma", "TestGamma", 3)},
+		{path: "delta_value_test.go", fileIndex: 3, annotation: "Value", names: []string{"TestDelta"}, code: test("delta", "TestDelta", 4)},
+		{path: "broken_value_test.go", fileIndex: 4, annotation: "Value", names: []string{"TestBroken"}, code: test("broken", "TestBroken", 5)},
+	}
+
+	results, err := tester.execute(context.Background(), files, tests)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if len(results[i]) != 1 || !results[i][0].Passed {
+			t.Fatalf("%s: expected its test to pass, got %+v", files[i].Path, results[i])
+		}
+	}
+	if len(results[4]) != 1 || results[4][0].Passed || !strings.Contains(results[4][0].Output, "undefined: Value") {
+		t.Fatalf("expected build failure of broken package, got %+v", results[4])
+	}
+}
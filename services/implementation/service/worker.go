@@ -4,13 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"strings"
 	"sync"
 	"time"
 
-	"codev42-implementation/client"
+	"codev42-llm/client"
 
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go"
 )
 
 type ImplementResult struct {
@@ -19,8 +20,11 @@ type ImplementResult struct {
 
 // Plan represents a development plan for implementation
 type Plan struct {
+	ID          int64        `json:"id"`
 	ClassName   string       `json:"className"`
 	Annotations []Annotation `json:"annotations"`
+	DependsOn   []string     `json:"dependsOn"`          // 먼저 구현되어야 하는 계획 이름 (클래스명, 함수 계획이면 함수 이름)
+	Feedback    string       `json:"feedback,omitempty"` // 리뷰어가 거절하며 남긴 피드백 (있으면 이전 코드를 피드백에 맞게 수정)
 }
 
 // Annotation represents a function annotation
@@ -32,14 +36,16 @@ type Annotation struct {
 }
 
 type WorkerAgent struct {
-	Client *openai.Client
-}
+	LLM client.LLMProvider
 
-func NewWorkerAgent(apiKey string) *WorkerAgent {
-	openaiClient := client.GetClient(apiKey)
+	// 검사기가 있는 언어에서 진단이 남아 있을 때 코드를 다시 생성하는 최대 횟수
+	MaxRepairRounds int
+}
 
+func NewWorkerAgent(llm client.LLMProvider, maxRepairRounds int) *WorkerAgent {
 	return &WorkerAgent{
-		Client: openaiClient.Client(),
+		LLM:             llm,
+		MaxRepairRounds: maxRepairRounds,
 	}
 }
 
@@ -53,100 +59,321 @@ func GenerateImplementResultSchema[T any]() interface{} {
 	return schema
 }
 
-func (agent WorkerAgent) call(language string, devPlan string) (*ImplementResult, error) {
+func (agent WorkerAgent) call(ctx context.Context, language string, devPlan string, dependencyCode string) (*ImplementResult, error) {
 	prompt := "개발 계획: " + devPlan
 	prompt += "언어: " + language
+	if dependencyCode != "" {
+		prompt += "\n이미 구현된 의존 코드:\n" + dependencyCode
+	}
 	prompt += `
 	개발 계획에 따라 개발 결과물을 만들어야 합니다. 정확히 코드가 원하는 Parameters와 ReturnType에 맞춰서 만들어야합니다.
 	개발 계획에 포함되지 않은 어떠한 메소드나 클래스를 추가하지 마세요
 	코드 외에 다른 정보는 추가하지 마세요.
 	`
-	print("> ")
-	println(prompt)
+	if dependencyCode != "" {
+		prompt += `의존 코드는 다시 작성하지 말고, 그 안의 타입과 함수를 이름과 시그니처 그대로 사용하세요.
+	`
+	}
+	return agent.generate(ctx, prompt)
+}
+
+// repair 컴파일 검사와 시그니처 검사 진단을 전달하여 이전에 생성한 코드를 수정
+func (agent WorkerAgent) repair(ctx context.Context, language string, devPlan string, dependencyCode string, code string, diagnostics []Diagnostic) (*ImplementResult, error) {
+	prompt := "개발 계획: " + devPlan
+	prompt += "언어: " + language
+	if dependencyCode != "" {
+		prompt += "\n이미 구현된 의존 코드:\n" + dependencyCode
+	}
+	prompt += "\n이전에 생성한 코드:\n" + code
+	prompt += "\n검사 결과 (컴파일 오류와 개발 계획과의 불일치):\n"
+	for _, diagnostic := range diagnostics {
+		prompt += diagnostic.String() + "\n"
+	}
+	prompt += `
+	이전에 생성한 코드의 검사 결과를 모두 수정한 전체 코드를 만들어야 합니다.
+	개발 계획의 Parameters와 ReturnType은 바꾸지 말고, 개발 계획에 포함되지 않은 메소드나 클래스를 추가하지 마세요.
+	코드 외에 다른 정보는 추가하지 마세요.
+	`
+	return agent.generate(ctx, prompt)
+}
+
+// revise 리뷰어가 거절한 이전 코드를 피드백에 맞게 다시 생성
+func (agent WorkerAgent) revise(ctx context.Context, language string, devPlan string, dependencyCode string, code string, feedback string) (*ImplementResult, error) {
+	prompt := "개발 계획: " + devPlan
+	prompt += "언어: " + language
+	if dependencyCode != "" {
+		prompt += "\n이미 구현된 의존 코드:\n" + dependencyCode
+	}
+	prompt += "\n이전에 생성한 코드:\n" + code
+	prompt += "\n리뷰어 피드백:\n" + feedback
+	prompt += `
+	리뷰어 피드백을 모두 반영하여 이전에 생성한 코드를 수정한 전체 코드를 만들어야 합니다.
+	개발 계획의 Parameters와 ReturnType은 바꾸지 말고, 개발 계획에 포함되지 않은 메소드나 클래스를 추가하지 마세요.
+	코드 외에 다른 정보는 추가하지 마세요.
+	`
+	return agent.generate(ctx, prompt)
+}
+
+// generateTest 어노테이션 하나에 대한 테이블 기반 테스트 파일 생성
+func (agent WorkerAgent) generateTest(ctx context.Context, language string, devPlan string, code string, annotation Annotation, packageName string) (*ImplementResult, error) {
+	prompt := "개발 계획: " + devPlan
+	prompt += "언어: " + language
+	prompt += "\n구현된 코드:\n" + code
+	prompt += "\n테스트할 함수: " + annotation.Name
+	prompt += "\n함수 설명: " + annotation.Description
+	prompt += "\n패키지: " + packageName
+	prompt += `
+	함수 설명대로 동작하는지 확인하는 테이블 기반(table-driven) 단위 테스트 파일을 만들어야 합니다.
+	테스트 함수 이름은 Test로 시작하고, 각 케이스는 t.Run으로 실행하세요.
+	표준 라이브러리만 사용하고, 네트워크나 파일 시스템에 접근하지 마세요.
+	구현된 코드를 다시 작성하지 말고, 테스트 파일 코드 외에 다른 정보는 추가하지 마세요.
+	`
+	return agent.generate(ctx, prompt)
+}
 
+// fixFailingFunctions 테스트가 실패한 함수만 다시 구현한 전체 코드 생성
+func (agent WorkerAgent) fixFailingFunctions(ctx context.Context, language string, devPlan string, code string, failures map[string]string) (*ImplementResult, error) {
+	prompt := "개발 계획: " + devPlan
+	prompt += "언어: " + language
+	prompt += "\n이전에 생성한 코드:\n" + code
+	prompt += "\n테스트에 실패한 함수:\n"
+	for name, output := range failures {
+		prompt += "functionName: " + name + "\n" + output + "\n"
+	}
+	prompt += `
+	테스트에 실패한 함수가 함수 설명대로 동작하도록 다시 구현한 전체 코드를 만들어야 합니다.
+	다른 함수와 개발 계획의 Parameters, ReturnType은 바꾸지 마세요.
+	코드 외에 다른 정보는 추가하지 마세요.
+	`
+	return agent.generate(ctx, prompt)
+}
+
+func (agent WorkerAgent) generate(ctx context.Context, prompt string) (*ImplementResult, error) {
 	var ImplementResultResponseSchema = GenerateImplementResultSchema[ImplementResult]()
 
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        openai.F("development_result"),
-		Description: openai.F("code and description of development result from the dev plan"),
-		Schema:      openai.F(ImplementResultResponseSchema),
-		Strict:      openai.Bool(true),
-	}
-
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type:       openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(schemaParam),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4oMini),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4oMini,
+		Prompt:            prompt,
+		SchemaName:        "development_result",
+		SchemaDescription: "code and description of development result from the dev plan",
+		Schema:            ImplementResultResponseSchema,
 	})
 
 	if err != nil {
-		fmt.Println("err: ", err)
+		log.Printf("Failed to generate code: %v", err)
 		return nil, err
 	}
 
 	ImplementResult := &ImplementResult{}
-	fmt.Println("chat.Choices[0].Message.Content: ", chat.Choices[0].Message)
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), ImplementResult)
+	err = json.Unmarshal([]byte(content), ImplementResult)
 	if err != nil {
 		return nil, err
 	}
 	return ImplementResult, nil
 }
 
-func (agent WorkerAgent) ImplementPlan(language string, plans []Plan) ([]*ImplementResult, error) {
-	var wg sync.WaitGroup
-	resultChan := make(chan *ImplementResult, len(plans))
-	errorChan := make(chan error, len(plans))
+// PlanGeneratedFunc 계획 하나의 구현(검사/수정 포함)이 끝나거나 실패할 때마다 호출 (file.Status로 구분).
+// 여러 고루틴에서 동시에 호출될 수 있음
+type PlanGeneratedFunc func(planIndex int, file GeneratedFile)
+
+// ImplementPlan 의존 관계의 위상 순서대로 계획을 구현. 같은 단계의 계획은 병렬로 구현하고,
+// 각 계획에는 의존하는 계획의 생성 코드를 함께 전달. 계획마다 파일 하나를 구현 순서대로 반환.
+// 계획 하나가 실패해도 나머지는 계속 구현하며, 실패한 계획에 의존하는 계획은 건너뜀.
+// previous는 이전 실행이나 코드 캐시의 파일로, 계획 ID가 같은 파일은 Status가 pending이 아니면 다시 생성하지 않음.
+// pending인 파일의 계획에 Feedback이 있으면 처음부터 생성하지 않고 이전 코드를 피드백에 맞게 수정.
+// 단, 재사용한 파일이 의존하는 계획이 다시 생성되었고 컴파일 검사를 통과하지 못하면 다시 구현
+func (agent WorkerAgent) ImplementPlan(ctx context.Context, language string, plans []Plan, previous []GeneratedFile, onGenerated PlanGeneratedFunc) ([]GeneratedFile, error) {
+	levels, err := OrderPlans(plans)
+	if err != nil {
+		return nil, err
+	}
+
+	previousByPlan := make(map[int64]GeneratedFile, len(previous))
+	for _, file := range previous {
+		previousByPlan[file.PlanID] = file
+	}
 
+	indexByKey := make(map[string]int, len(plans))
+	files := make([]GeneratedFile, len(plans))
 	for i, plan := range plans {
-		wg.Add(1)
-		go func(plan Plan, index int) {
-			defer wg.Done()
-			fmt.Printf("Processing: %s\n", plan.ClassName)
-			planString := "className: " + plan.ClassName + "\n"
-			for _, annotation := range plan.Annotations {
-				planString += "functionName: " + annotation.Name + "\n"
-				planString += "functionDescription: " + annotation.Description + "\n"
-				planString += "functionParameters: " + annotation.Params + "\n"
-				planString += "functionReturnType: " + annotation.Returns + "\n"
+		indexByKey[PlanKey(plan)] = i
+		files[i] = GeneratedFile{
+			Path:     FilePathForPlan(language, plan),
+			Language: language,
+			PlanID:   plan.ID,
+			plan:     &plans[i],
+		}
+	}
+	uniquePaths(files)
+	for i := range files {
+		prev, ok := previousByPlan[files[i].PlanID]
+		if !ok {
+			continue
+		}
+		// 경로는 이전 결과를 유지하고, 다시 구현할 계획은 시도 횟수와 (피드백 반영을 위해) 이전 코드만 이어받음
+		if prev.Status == PlanStatusPending {
+			files[i].Path = prev.Path
+			files[i].Attempts = prev.Attempts
+			files[i].previousCode = prev.Code
+			continue
+		}
+		if prev.Path == "" {
+			prev.Path = files[i].Path
+		}
+		prev.plan = &plans[i]
+		prev.reused = true
+		files[i] = prev
+	}
+
+	checker := CheckerForLanguage(language)
+	var ordered []GeneratedFile
+	for _, level := range levels {
+		var wg sync.WaitGroup
+
+		// 이전 단계에서 생성된 파일은 이번 단계의 검사에 함께 사용
+		related := append([]GeneratedFile{}, ordered...)
+
+		for _, index := range level {
+			if files[index].reused {
+				if !stale(files, index, indexByKey, checker, related) {
+					continue
+				}
+				log.Printf("Plan %d no longer compiles with regenerated dependencies, regenerating", index)
+				files[index] = GeneratedFile{
+					Path:     files[index].Path,
+					Language: language,
+					PlanID:   files[index].PlanID,
+					Attempts: files[index].Attempts,
+					plan:     &plans[index],
+				}
 			}
-			fmt.Printf("Plan %d started\n", index)
-			startTime := time.Now()
-			ImplementResult, err := agent.call(language, planString)
-			fmt.Println("ImplementResult: ", ImplementResult)
-			endTime := time.Now()
-			elapsedTime := endTime.Sub(startTime)
-			fmt.Printf("Plan %d completed in %s\n", index, elapsedTime)
-			if err != nil {
-				errorChan <- err
-				return
+
+			// 의존 코드는 이전 단계에서 모두 생성됨
+			dependencyCode := ""
+			var missing []string
+			for _, dependency := range plans[index].DependsOn {
+				depIndex, ok := indexByKey[dependency]
+				if !ok {
+					continue
+				}
+				if files[depIndex].Status != PlanStatusCompleted {
+					missing = append(missing, dependency)
+					continue
+				}
+				dependencyCode += "// " + dependency + "\n" + files[depIndex].Code + "\n"
 			}
-			resultChan <- ImplementResult
-		}(plan, i)
+			if len(missing) > 0 {
+				files[index].Status = PlanStatusSkipped
+				files[index].Error = fmt.Sprintf("dependencies not implemented: %s", strings.Join(missing, ", "))
+				if onGenerated != nil {
+					onGenerated(index, files[index])
+				}
+				continue
+			}
+
+			wg.Add(1)
+			go func(plan Plan, index int, dependencyCode string) {
+				defer wg.Done()
+				// 같은 단계의 고루틴은 서로 다른 index에만 기록
+				file := &files[index]
+				file.Attempts++
+				if err := agent.implementFile(ctx, language, plan, index, file, dependencyCode, checker, related); err != nil {
+					file.Code = ""
+					file.Diagnostics = nil
+					file.Conformance = nil
+					file.Status = PlanStatusFailed
+					file.Error = err.Error()
+				} else {
+					file.Status = PlanStatusCompleted
+					file.Error = ""
+				}
+				if onGenerated != nil && ctx.Err() == nil {
+					onGenerated(index, *file)
+				}
+			}(plans[index], index, dependencyCode)
+		}
+
+		wg.Wait()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		for _, index := range level {
+			ordered = append(ordered, files[index])
+		}
 	}
+	return ordered, nil
+}
 
-	wg.Wait()
-	close(resultChan)
-	close(errorChan)
+// stale 재사용한 파일의 의존 계획이 이번에 다시 생성되었고, 그 코드와 함께 컴파일되지 않는지 여부
+func stale(files []GeneratedFile, index int, indexByKey map[string]int, checker CodeChecker, related []GeneratedFile) bool {
+	file := files[index]
+	if checker == nil || file.Status != PlanStatusCompleted {
+		return false
+	}
+	regenerated := false
+	for _, dependency := range file.plan.DependsOn {
+		if depIndex, ok := indexByKey[dependency]; ok && !files[depIndex].reused && files[depIndex].Status == PlanStatusCompleted {
+			regenerated = true
+			break
+		}
+	}
+	return regenerated && len(checker.Check(file, related)) > 0
+}
 
-	var results []*ImplementResult
-	for result := range resultChan {
-		results = append(results, result)
+// implementFile 계획 하나의 코드를 생성하고, 검사기가 있으면 컴파일 진단과 계획 불일치가 남지 않을 때까지 수정 요청
+func (agent WorkerAgent) implementFile(ctx context.Context, language string, plan Plan, index int, file *GeneratedFile, dependencyCode string, checker CodeChecker, related []GeneratedFile) error {
+	planString := formatPlan(plan)
+	log.Printf("Plan %d (%s) started (attempt %d)", index, plan.ClassName, file.Attempts)
+	startTime := time.Now()
+	var implementResult *ImplementResult
+	var err error
+	if plan.Feedback != "" && file.previousCode != "" {
+		implementResult, err = agent.revise(ctx, language, planString, dependencyCode, file.previousCode, plan.Feedback)
+	} else {
+		implementResult, err = agent.call(ctx, language, planString, dependencyCode)
+	}
+	if err != nil {
+		return err
 	}
-	fmt.Println("results: ", results)
-	if len(errorChan) > 0 {
-		var errors []string
-		for err := range errorChan {
-			errors = append(errors, err.Error())
+
+	file.Code = implementResult.Code
+	conformance := ConformanceCheckerForLanguage(language)
+	inspect := func() []Diagnostic {
+		if checker != nil {
+			file.Diagnostics = checker.Check(*file, related)
+		}
+		if conformance != nil {
+			file.Conformance = conformance.Check(plan, *file)
+		}
+		return append(append([]Diagnostic{}, file.Diagnostics...), file.Conformance.Diagnostics(file.Path)...)
+	}
+
+	diagnostics := inspect()
+	for round := 1; len(diagnostics) > 0 && round <= agent.MaxRepairRounds; round++ {
+		log.Printf("Plan %d repair round %d: %d diagnostics", index, round, len(diagnostics))
+		repaired, err := agent.repair(ctx, language, planString, dependencyCode, file.Code, diagnostics)
+		if err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to implement plan: %v", errors)
+		file.Code = repaired.Code
+		diagnostics = inspect()
+	}
+
+	endTime := time.Now()
+	elapsedTime := endTime.Sub(startTime)
+	log.Printf("Plan %d completed in %s", index, elapsedTime)
+	return nil
+}
+
+// formatPlan 프롬프트에 넣을 계획 설명
+func formatPlan(plan Plan) string {
+	planString := "className: " + plan.ClassName + "\n"
+	for _, annotation := range plan.Annotations {
+		planString += "functionName: " + annotation.Name + "\n"
+		planString += "functionDescription: " + annotation.Description + "\n"
+		planString += "functionParameters: " + annotation.Params + "\n"
+		planString += "functionReturnType: " + annotation.Returns + "\n"
 	}
-	return results, nil
+	return planString
 }
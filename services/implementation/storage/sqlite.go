@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"fmt"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewSQLiteConnection : 로컬 개발/테스트용 SQLite 연결 (MySQL 대체)
+func NewSQLiteConnection(path string) (*RDBConnection, error) {
+	gormDB, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite db: %w", err)
+	}
+
+	return &RDBConnection{DB: gormDB}, nil
+}
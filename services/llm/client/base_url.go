@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 공인 주소로 볼 수 없는 대역 (netip.Addr의 판별 함수에 없는 것)
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 (내부 IPv4로 변환될 수 있음)
+}
+
+// ParseAllowedHosts 쉼표로 구분된 호스트 목록 (예: "api.openai.com,*.example.com")
+func ParseAllowedHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// hostAllowed host가 목록의 호스트와 같거나 "*.도메인" 항목의 하위 도메인인지 여부
+func hostAllowed(host string, allowedHosts []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range allowedHosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// publicAddress 서비스 밖(인터넷)의 주소인지 여부. loopback, 사설, link-local(클라우드 메타데이터 포함) 등은 거부
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTenantBaseURL 테넌트가 지정한 BaseURL의 형식 검사. https만 허용하고, allowedHosts가 있으면 목록의 호스트만 허용
+func checkTenantBaseURL(rawURL string, allowedHosts []string) (*url.URL, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %v", err)
+	}
+	if parsed.Scheme != "https" {
+		return nil, fmt.Errorf("base url must use https")
+	}
+	if parsed.User != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("base url must have a host and no user info")
+	}
+	if len(allowedHosts) > 0 && !hostAllowed(parsed.Hostname(), allowedHosts) {
+		return nil, fmt.Errorf("base url host %s is not allowed", parsed.Hostname())
+	}
+	return parsed, nil
+}
+
+// ValidateTenantBaseURL 테넌트 자격 증명 등록 시 BaseURL 검사 (비어 있으면 OpenAI 기본 엔드포인트).
+// 허용 목록에 없는 호스트는 조회한 주소가 모두 공인 주소여야 함 (요청할 때 연결하는 주소도 다시 검사)
+func ValidateTenantBaseURL(ctx context.Context, rawURL string, allowedHosts []string) error {
+	if rawURL == "" {
+		return nil
+	}
+	parsed, err := checkTenantBaseURL(rawURL, allowedHosts)
+	if err != nil {
+		return err
+	}
+	if hostAllowed(parsed.Hostname(), allowedHosts) {
+		return nil
+	}
+	_, err = publicAddresses(ctx, parsed.Hostname())
+	return err
+}
+
+// publicAddresses host의 주소를 조회하여 모두 공인 주소일 때만 반환
+func publicAddresses(ctx context.Context, host string) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		addrs, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %v", host, err)
+		}
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return nil, fmt.Errorf("base url host %s resolves to non-public address %s", host, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// newTenantHTTPClient 테넌트 BaseURL 요청용 HTTP 클라이언트.
+// 허용 목록에 없는 호스트는 연결 직전에 조회한 공인 주소로만 연결하여 DNS를 바꿔 내부 주소로 보내는 요청도 막고,
+// 리다이렉트도 같은 규칙으로 검사. 프록시는 사용하지 않음
+func newTenantHTTPClient(allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	dial := func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if hostAllowed(host, allowedHosts) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		addrs, err := publicAddresses(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	return &http.Client{
+		Timeout: 60 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dial,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+			MaxConnsPerHost:     20,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			_, err := checkTenantBaseURL(req.URL.String(), allowedHosts)
+			return err
+		},
+	}
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// credentialCacheTTL 다른 레플리카에서 변경된 자격 증명이 반영되기까지의 최대 지연
+const credentialCacheTTL = time.Minute
+
+// Credential 복호화된 테넌트별 LLM provider 자격 증명
+type Credential struct {
+	TenantID string
+	Provider string
+	APIKey   string
+	BaseURL  string
+}
+
+// CredentialResolver 테넌트 ID로 자격 증명 조회 (등록되지 않았으면 nil, nil)
+type CredentialResolver interface {
+	ResolveCredential(ctx context.Context, tenantID string) (*Credential, error)
+}
+
+// providerCredentialRecord provider_credentials 테이블 (API key는 암호화해서 저장)
+type providerCredentialRecord struct {
+	TenantID        string    `gorm:"primaryKey;type:varchar(255)"`
+	Provider        string    `gorm:"type:varchar(32);not null"`
+	EncryptedAPIKey string    `gorm:"type:text;not null"`
+	BaseURL         string    `gorm:"type:varchar(512)"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (providerCredentialRecord) TableName() string {
+	return "provider_credentials"
+}
+
+// Cipher AES-256-GCM 기반 자격 증명 암호화
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher base64로 인코딩된 32바이트 키로 Cipher 생성
+func NewCipher(encodedKey string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encryption key: %v", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %v", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt nonce를 앞에 붙인 암호문을 base64로 반환
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %v", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt Encrypt로 만든 암호문 복호화
+func (c *Cipher) Decrypt(encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode ciphertext: %v", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt credential: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+type cachedCredential struct {
+	credential *Credential
+	expiresAt  time.Time
+}
+
+// CredentialStore provider_credentials 테이블 기반 CredentialResolver (조회 결과는 TTL 동안 캐시)
+type CredentialStore struct {
+	db     *gorm.DB
+	cipher *Cipher
+
+	mu    sync.Mutex
+	cache map[string]cachedCredential
+}
+
+func NewCredentialStore(db *gorm.DB, cipher *Cipher) *CredentialStore {
+	return &CredentialStore{
+		db:     db,
+		cipher: cipher,
+		cache:  make(map[string]cachedCredential),
+	}
+}
+
+// SaveCredential 테넌트 자격 증명을 암호화해서 저장 (이미 있으면 교체)
+func (s *CredentialStore) SaveCredential(ctx context.Context, credential Credential) error {
+	encryptedKey, err := s.cipher.Encrypt(credential.APIKey)
+	if err != nil {
+		return err
+	}
+
+	record := &providerCredentialRecord{
+		TenantID:        credential.TenantID,
+		Provider:        credential.Provider,
+		EncryptedAPIKey: encryptedKey,
+		BaseURL:         credential.BaseURL,
+	}
+	err = s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"provider", "encrypted_api_key", "base_url", "updated_at"}),
+	}).Create(record).Error
+	if err != nil {
+		return fmt.Errorf("failed to save credential: %v", err)
+	}
+
+	s.invalidate(credential.TenantID)
+	return nil
+}
+
+// DeleteCredential 테넌트 자격 증명 삭제 (삭제된 행이 있으면 true)
+func (s *CredentialStore) DeleteCredential(ctx context.Context, tenantID string) (bool, error) {
+	result := s.db.WithContext(ctx).Delete(&providerCredentialRecord{}, "tenant_id = ?", tenantID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete credential: %v", result.Error)
+	}
+
+	s.invalidate(tenantID)
+	return result.RowsAffected > 0, nil
+}
+
+func (s *CredentialStore) ResolveCredential(ctx context.Context, tenantID string) (*Credential, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[tenantID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.credential, nil
+	}
+
+	var record providerCredentialRecord
+	err := s.db.WithContext(ctx).First(&record, "tenant_id = ?", tenantID).Error
+	var credential *Credential
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// 등록되지 않은 테넌트도 캐시해서 매 호출마다 조회하지 않도록 함
+	case err != nil:
+		return nil, fmt.Errorf("failed to get credential: %v", err)
+	default:
+		apiKey, err := s.cipher.Decrypt(record.EncryptedAPIKey)
+		if err != nil {
+			return nil, err
+		}
+		credential = &Credential{
+			TenantID: record.TenantID,
+			Provider: record.Provider,
+			APIKey:   apiKey,
+			BaseURL:  record.BaseURL,
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedCredential{credential: credential, expiresAt: time.Now().Add(credentialCacheTTL)}
+	s.mu.Unlock()
+	return credential, nil
+}
+
+func (s *CredentialStore) invalidate(tenantID string) {
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+}
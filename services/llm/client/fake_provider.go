@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+)
+
+const defaultFakeEmbeddingDimensions = 8
+
+// FakeProvider 네트워크 없이 미리 정해진 응답을 돌려주는 LLMProvider (테스트/오프라인 실행용)
+//
+// 응답은 스키마 이름(ChatRequest.SchemaName)별 큐로 관리되며, 큐의 마지막 응답은 반복해서 반환된다.
+// Embedding은 입력 문자열의 해시로부터 결정적으로 생성된다.
+type FakeProvider struct {
+	mu        sync.Mutex
+	responses map[string][]string
+	calls     []ChatRequest
+}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		responses: make(map[string][]string),
+	}
+}
+
+// Script 스키마 이름에 대한 응답을 순서대로 추가
+func (f *FakeProvider) Script(schemaName string, responses ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[schemaName] = append(f.responses[schemaName], responses...)
+}
+
+// LoadScript {"스키마 이름": [응답 JSON, ...]} 형식의 파일에서 응답을 읽음
+func (f *FakeProvider) LoadScript(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read fake llm script: %v", err)
+	}
+
+	var script map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &script); err != nil {
+		return fmt.Errorf("failed to parse fake llm script: %v", err)
+	}
+
+	for schemaName, responses := range script {
+		for _, response := range responses {
+			f.Script(schemaName, string(response))
+		}
+	}
+	return nil
+}
+
+// Calls 지금까지 받은 chat 요청 목록
+func (f *FakeProvider) Calls() []ChatRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := make([]ChatRequest, len(f.calls))
+	copy(calls, f.calls)
+	return calls
+}
+
+func (f *FakeProvider) ChatJSON(ctx context.Context, req ChatRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, req)
+
+	queue := f.responses[req.SchemaName]
+	if len(queue) == 0 {
+		return "", fmt.Errorf("no scripted response for schema %s", req.SchemaName)
+	}
+	response := queue[0]
+	if len(queue) > 1 {
+		f.responses[req.SchemaName] = queue[1:]
+	}
+	return response, nil
+}
+
+func (f *FakeProvider) Embed(ctx context.Context, req EmbeddingRequest) ([]float64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	dimensions := int(req.Dimensions)
+	if dimensions <= 0 {
+		dimensions = defaultFakeEmbeddingDimensions
+	}
+
+	// 입력 해시를 seed로 [-1, 1] 범위 값을 만든 뒤 정규화
+	embedding := make([]float64, dimensions)
+	var norm float64
+	for i := range embedding {
+		var block [8]byte
+		binary.BigEndian.PutUint64(block[:], uint64(i))
+		sum := sha256.Sum256(append([]byte(req.Input), block[:]...))
+		value := float64(binary.BigEndian.Uint64(sum[:8]))/math.MaxUint64*2 - 1
+		embedding[i] = value
+		norm += value * value
+	}
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range embedding {
+			embedding[i] /= norm
+		}
+	}
+	return embedding, nil
+}
@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LimitConfig LLM 호출 제한 (0이면 해당 항목은 제한 없음)
+type LimitConfig struct {
+	MaxConcurrency    int // 동시에 진행 중인 호출 수
+	RequestsPerMinute int // 분당 호출 수
+	TokensPerMinute   int // 분당 토큰 수 (프롬프트와 응답 길이로 추정)
+}
+
+// Limiter 서비스 전체와 테넌트별 LLM 호출 제한
+//
+// 한도를 넘은 호출은 실패하지 않고 context가 끝날 때까지 차례를 기다린다.
+type Limiter struct {
+	service *limit
+	tenant  LimitConfig
+
+	mu      sync.Mutex
+	tenants map[string]*limit
+}
+
+// NewLimiter service는 서비스 전체 한도, tenant는 테넌트마다 적용되는 한도
+func NewLimiter(service LimitConfig, tenant LimitConfig) *Limiter {
+	return &Limiter{
+		service: newLimit(service),
+		tenant:  tenant,
+		tenants: make(map[string]*limit),
+	}
+}
+
+// Acquire 테넌트와 서비스 한도 안에서 호출할 수 있을 때까지 대기.
+// 반환된 release는 호출이 끝나면 응답의 추정 토큰 수와 함께 한 번 호출해야 함
+func (l *Limiter) Acquire(ctx context.Context, tenantID string, tokens int) (func(responseTokens int), error) {
+	// 테넌트 한도를 먼저 기다려, 한 테넌트의 대기가 서비스 전체 슬롯을 차지하지 않게 함
+	tenant := l.tenantLimit(tenantID)
+	if err := tenant.acquire(ctx, tokens); err != nil {
+		return nil, err
+	}
+	if err := l.service.acquire(ctx, tokens); err != nil {
+		tenant.release(0)
+		return nil, err
+	}
+
+	return func(responseTokens int) {
+		l.service.release(responseTokens)
+		tenant.release(responseTokens)
+	}, nil
+}
+
+func (l *Limiter) tenantLimit(tenantID string) *limit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tenant, ok := l.tenants[tenantID]
+	if !ok {
+		tenant = newLimit(l.tenant)
+		l.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+// limit 동시 호출 슬롯과 분당 호출/토큰 버킷
+type limit struct {
+	slots    chan struct{} // nil이면 동시 호출 제한 없음
+	requests *bucket
+	tokens   *bucket
+}
+
+func newLimit(config LimitConfig) *limit {
+	l := &limit{
+		requests: newBucket(config.RequestsPerMinute),
+		tokens:   newBucket(config.TokensPerMinute),
+	}
+	if config.MaxConcurrency > 0 {
+		l.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+	return l
+}
+
+func (l *limit) acquire(ctx context.Context, tokens int) error {
+	if err := l.requests.wait(ctx, 1); err != nil {
+		return err
+	}
+	if err := l.tokens.wait(ctx, tokens); err != nil {
+		l.requests.refund(1)
+		return err
+	}
+	if l.slots == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		l.requests.refund(1)
+		l.tokens.refund(tokens)
+		return ctx.Err()
+	}
+}
+
+// release 슬롯을 반환하고 응답 토큰을 버킷에서 차감 (다음 호출이 그만큼 늦어짐)
+func (l *limit) release(responseTokens int) {
+	l.tokens.charge(responseTokens)
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// bucket 분당 한도를 초 단위로 채우는 토큰 버킷 (nil이면 제한 없음)
+//
+// 대기 중인 호출은 먼저 예약하고 기다리므로 요청 순서대로 처리된다.
+type bucket struct {
+	mu        sync.Mutex
+	capacity  float64
+	perSecond float64
+	available float64
+	last      time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity:  float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		available: float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+// wait n만큼 예약하고, 버킷이 다시 채워질 때까지 대기 (취소되면 예약을 되돌림)
+func (b *bucket) wait(ctx context.Context, n int) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+
+	delay := b.reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.refund(n)
+		return ctx.Err()
+	}
+}
+
+func (b *bucket) reserve(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.available -= b.clamp(n)
+	if b.available >= 0 {
+		return 0
+	}
+	return time.Duration(-b.available / b.perSecond * float64(time.Second))
+}
+
+func (b *bucket) refund(n int) {
+	if b == nil || n <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.available = min(b.capacity, b.available+b.clamp(n))
+}
+
+// charge 대기 없이 n만큼 차감 (최대 1분치까지 빚으로 남김)
+func (b *bucket) charge(n int) {
+	if b == nil || n <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.available = max(-b.capacity, b.available-b.clamp(n))
+}
+
+// clamp 한 번에 버킷 용량보다 많이 요청하면 영원히 기다리게 되므로 용량으로 제한
+func (b *bucket) clamp(n int) float64 {
+	return min(float64(n), b.capacity)
+}
+
+func (b *bucket) refill() {
+	now := time.Now()
+	b.available = min(b.capacity, b.available+now.Sub(b.last).Seconds()*b.perSecond)
+	b.last = now
+}
+
+// EstimateTokens 토크나이저 없이 문자열 길이로 토큰 수를 대략 추정 (약 4바이트당 1토큰)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// RateLimitedProvider Limiter로 호출 수와 토큰 사용량을 제한하는 LLMProvider
+//
+// 요청 context의 테넌트별 한도와 서비스 전체 한도를 함께 적용한다.
+type RateLimitedProvider struct {
+	provider LLMProvider
+	limiter  *Limiter
+}
+
+func NewRateLimitedProvider(provider LLMProvider, limiter *Limiter) *RateLimitedProvider {
+	return &RateLimitedProvider{
+		provider: provider,
+		limiter:  limiter,
+	}
+}
+
+func (p *RateLimitedProvider) ChatJSON(ctx context.Context, req ChatRequest) (string, error) {
+	release, err := p.limiter.Acquire(ctx, TenantFromContext(ctx), EstimateTokens(req.Prompt))
+	if err != nil {
+		return "", err
+	}
+	content, err := p.provider.ChatJSON(ctx, req)
+	release(EstimateTokens(content))
+	return content, err
+}
+
+func (p *RateLimitedProvider) Embed(ctx context.Context, req EmbeddingRequest) ([]float64, error) {
+	release, err := p.limiter.Acquire(ctx, TenantFromContext(ctx), EstimateTokens(req.Input))
+	if err != nil {
+		return nil, err
+	}
+	embedding, err := p.provider.Embed(ctx, req)
+	release(0)
+	return embedding, err
+}
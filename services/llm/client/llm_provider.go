@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// 에이전트가 기본으로 사용하는 모델 (OpenAI 호환 서버에서는 ProviderConfig로 덮어쓸 수 있음)
+const (
+	ModelGPT4o               = "gpt-4o-2024-11-20"
+	ModelGPT4oMini           = "gpt-4o-mini"
+	ModelTextEmbedding3Small = "text-embedding-3-small"
+)
+
+// ChatRequest JSON schema 형식의 응답을 요구하는 chat completion 요청
+type ChatRequest struct {
+	Model             string
+	Prompt            string
+	SchemaName        string
+	SchemaDescription string
+	Schema            interface{}
+	Temperature       *float64
+}
+
+// EmbeddingRequest 단일 입력에 대한 embedding 요청
+type EmbeddingRequest struct {
+	Model      string
+	Input      string
+	Dimensions int64
+	User       string
+}
+
+// LLMProvider chat completion과 embedding을 제공하는 LLM 백엔드
+type LLMProvider interface {
+	// ChatJSON 스키마에 맞는 JSON 문자열을 반환
+	ChatJSON(ctx context.Context, req ChatRequest) (string, error)
+	// Embed 입력 문자열의 embedding 벡터를 반환
+	Embed(ctx context.Context, req EmbeddingRequest) ([]float64, error)
+}
+
+// ProviderConfig LLM provider 선택 및 접속 정보
+type ProviderConfig struct {
+	Provider       string // openai, fake
+	APIKey         string
+	BaseURL        string // 비어 있으면 OpenAI 기본 엔드포인트
+	ChatModel      string // 비어 있으면 에이전트가 요청한 모델 사용
+	EmbeddingModel string
+	FakeScriptPath string // fake provider 응답 스크립트 (JSON)
+
+	// 테넌트 자격 증명의 BaseURL로 허용하는 호스트 ("*.example.com" 형식 가능).
+	// 비어 있으면 공인 주소로만 연결되는 https 호스트를 허용
+	TenantAllowedHosts []string
+}
+
+// NewLLMProvider 설정에 맞는 LLMProvider 생성
+func NewLLMProvider(config ProviderConfig) (LLMProvider, error) {
+	switch config.Provider {
+	case "", "openai":
+		return NewOpenAIProvider(config), nil
+	case "fake":
+		fake := NewFakeProvider()
+		if config.FakeScriptPath != "" {
+			if err := fake.LoadScript(config.FakeScriptPath); err != nil {
+				return nil, err
+			}
+		}
+		return fake, nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider: %s", config.Provider)
+	}
+}
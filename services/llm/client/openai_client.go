@@ -0,0 +1,83 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+type OpenAIClient struct {
+	client *openai.Client
+}
+
+var (
+	clients   = make(map[string]*OpenAIClient)
+	clientsMu sync.Mutex
+
+	httpClient     *http.Client
+	httpClientOnce sync.Once
+)
+
+// sharedHTTPClient 모든 OpenAI 클라이언트가 커넥션 풀을 공유하도록 HTTP 클라이언트를 한 번만 생성
+func sharedHTTPClient() *http.Client {
+	httpClientOnce.Do(func() {
+		// 커스텀 HTTP 클라이언트 생성
+		httpClient = &http.Client{
+			Timeout: 60 * time.Second,
+			Transport: &http.Transport{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 10,
+				MaxConnsPerHost:     20,
+				IdleConnTimeout:     90 * time.Second,
+				DisableKeepAlives:   false,
+			},
+		}
+	})
+	return httpClient
+}
+
+// clientKey API key 원문 대신 해시를 풀 키로 사용
+func clientKey(apiKey string, baseURL string) string {
+	sum := sha256.Sum256([]byte(baseURL + "\x00" + apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetClient returns a pooled OpenAI client for the given credentials
+// baseURL이 비어 있지 않으면 OpenAI 호환 서버로 요청을 보낸다
+func GetClient(apiKey string, baseURL string) *OpenAIClient {
+	return pooledClient(clientKey(apiKey, baseURL), apiKey, baseURL, sharedHTTPClient())
+}
+
+// pooledClient key로 풀링된 클라이언트를 반환하고, 없으면 httpClient를 사용하는 클라이언트를 생성
+func pooledClient(key string, apiKey string, baseURL string, httpClient *http.Client) *OpenAIClient {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if instance, ok := clients[key]; ok {
+		return instance
+	}
+
+	opts := []option.RequestOption{
+		option.WithAPIKey(apiKey),
+		option.WithHTTPClient(httpClient), // 커스텀 HTTP 클라이언트 사용
+	}
+	if baseURL != "" {
+		opts = append(opts, option.WithBaseURL(baseURL))
+	}
+
+	instance := &OpenAIClient{
+		client: openai.NewClient(opts...),
+	}
+	clients[key] = instance
+	return instance
+}
+
+// Client returns the underlying OpenAI client
+func (o *OpenAIClient) Client() *openai.Client {
+	return o.client
+}
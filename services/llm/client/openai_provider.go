@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+)
+
+// OpenAIProvider OpenAI 및 OpenAI 호환 서버(BaseURL 지정)를 사용하는 LLMProvider
+type OpenAIProvider struct {
+	client         *openai.Client
+	chatModel      string
+	embeddingModel string
+}
+
+func NewOpenAIProvider(config ProviderConfig) *OpenAIProvider {
+	return &OpenAIProvider{
+		client:         GetClient(config.APIKey, config.BaseURL).Client(),
+		chatModel:      config.ChatModel,
+		embeddingModel: config.EmbeddingModel,
+	}
+}
+
+// ChatJSON strict JSON schema 응답 형식으로 chat completion 호출
+func (p *OpenAIProvider) ChatJSON(ctx context.Context, req ChatRequest) (string, error) {
+	model := req.Model
+	if p.chatModel != "" {
+		model = p.chatModel
+	}
+
+	params := openai.ChatCompletionNewParams{
+		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
+			openai.UserMessage(req.Prompt),
+		}),
+		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
+			openai.ResponseFormatJSONSchemaParam{
+				Type: openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
+				JSONSchema: openai.F(openai.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:        openai.F(req.SchemaName),
+					Description: openai.F(req.SchemaDescription),
+					Schema:      openai.F(req.Schema),
+					Strict:      openai.Bool(true),
+				}),
+			},
+		),
+		Model: openai.F(openai.ChatModel(model)),
+	}
+	if req.Temperature != nil {
+		params.Temperature = openai.F(*req.Temperature)
+	}
+
+	chat, err := p.client.Chat.Completions.New(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	if len(chat.Choices) == 0 {
+		return "", fmt.Errorf("empty chat completion response")
+	}
+	return chat.Choices[0].Message.Content, nil
+}
+
+// Embed float 인코딩으로 embedding 호출
+func (p *OpenAIProvider) Embed(ctx context.Context, req EmbeddingRequest) ([]float64, error) {
+	model := req.Model
+	if p.embeddingModel != "" {
+		model = p.embeddingModel
+	}
+
+	params := openai.EmbeddingNewParams{
+		Input:          openai.F[openai.EmbeddingNewParamsInputUnion](shared.UnionString(req.Input)),
+		Model:          openai.F(openai.EmbeddingModel(model)),
+		EncodingFormat: openai.F(openai.EmbeddingNewParamsEncodingFormatFloat),
+	}
+	if req.Dimensions > 0 {
+		params.Dimensions = openai.F(req.Dimensions)
+	}
+	if req.User != "" {
+		params.User = openai.F(req.User)
+	}
+
+	response, err := p.client.Embeddings.New(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Data) == 0 {
+		return nil, fmt.Errorf("empty embedding response")
+	}
+	return response.Data[0].Embedding, nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ProviderPool 요청 context의 테넌트에 맞는 LLMProvider로 호출을 위임
+//
+// 테넌트가 없거나 자격 증명이 등록되지 않았으면 서비스 기본 provider를 사용한다.
+type ProviderPool struct {
+	defaultProvider LLMProvider
+	resolver        CredentialResolver
+	config          ProviderConfig
+	tenantHTTP      *http.Client // 테넌트 BaseURL 요청은 내부 주소로 연결하지 않는 HTTP 클라이언트 사용
+}
+
+// NewProviderPool resolver가 nil이면 항상 기본 provider 사용
+func NewProviderPool(defaultProvider LLMProvider, resolver CredentialResolver, config ProviderConfig) *ProviderPool {
+	return &ProviderPool{
+		defaultProvider: defaultProvider,
+		resolver:        resolver,
+		config:          config,
+		tenantHTTP:      newTenantHTTPClient(config.TenantAllowedHosts),
+	}
+}
+
+func (p *ProviderPool) ChatJSON(ctx context.Context, req ChatRequest) (string, error) {
+	provider, err := p.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return provider.ChatJSON(ctx, req)
+}
+
+func (p *ProviderPool) Embed(ctx context.Context, req EmbeddingRequest) ([]float64, error) {
+	provider, err := p.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.Embed(ctx, req)
+}
+
+func (p *ProviderPool) resolve(ctx context.Context) (LLMProvider, error) {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == "" || p.resolver == nil {
+		return p.defaultProvider, nil
+	}
+
+	credential, err := p.resolver.ResolveCredential(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credential for tenant %s: %v", tenantID, err)
+	}
+	if credential == nil {
+		return p.defaultProvider, nil
+	}
+
+	// 등록 이후 허용 목록이 바뀌었을 수 있으므로 요청할 때마다 다시 검사 (연결 주소는 tenantHTTP에서 검사)
+	if credential.BaseURL != "" {
+		if _, err := checkTenantBaseURL(credential.BaseURL, p.config.TenantAllowedHosts); err != nil {
+			return nil, fmt.Errorf("credential of tenant %s: %v", tenantID, err)
+		}
+	}
+
+	// OpenAI 클라이언트는 자격 증명별로 풀링됨 (서비스 기본 클라이언트와 구분)
+	key := "tenant:" + clientKey(credential.APIKey, credential.BaseURL)
+	return &OpenAIProvider{
+		client:         pooledClient(key, credential.APIKey, credential.BaseURL, p.tenantHTTP).Client(),
+		chatModel:      p.config.ChatModel,
+		embeddingModel: p.config.EmbeddingModel,
+	}, nil
+}
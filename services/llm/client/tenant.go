@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// TenantMetadataKey 요청 테넌트(프로젝트 또는 사용자) ID를 전달하는 gRPC metadata 키
+const TenantMetadataKey = "x-tenant-id"
+
+type tenantContextKey struct{}
+
+// WithTenant 테넌트 ID를 context에 저장하고, 이후 나가는 gRPC 호출에도 metadata로 전달
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	if tenantID == "" {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, tenantContextKey{}, tenantID)
+	return metadata.AppendToOutgoingContext(ctx, TenantMetadataKey, tenantID)
+}
+
+// TenantFromContext WithTenant로 저장된 값 또는 들어온 gRPC metadata에서 테넌트 ID 조회
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(string); ok {
+		return tenantID
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TenantMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -3,11 +3,32 @@ package configs
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 type Config struct {
 	OpenAiKey string
 
+	// LLM provider 설정 (openai, fake)
+	LLMProvider       string
+	OpenAIBaseURL     string
+	LLMChatModel      string
+	LLMEmbeddingModel string
+	FakeLLMScript     string
+
+	// LLM 호출 제한 (서비스 전체 / 테넌트별, 0이면 제한 없음). 한도를 넘은 호출은 대기
+	LLMMaxConcurrency          int
+	LLMRequestsPerMinute       int
+	LLMTokensPerMinute         int
+	LLMTenantMaxConcurrency    int
+	LLMTenantRequestsPerMinute int
+	LLMTenantTokensPerMinute   int
+
+	// 테넌트별 자격 증명 암호화 키 (base64, 32바이트). 비어 있으면 기본 키만 사용
+	CredentialEncryptionKey string
+	// 테넌트 자격 증명의 BaseURL로 허용하는 호스트 (쉼표로 구분). 비어 있으면 공인 주소의 https 호스트만 허용
+	TenantLLMAllowedHosts string
+
 	MySQLUser     string
 	MySQLPassword string
 	MySQLHost     string
@@ -15,6 +36,16 @@ type Config struct {
 	MySQLDB       string
 
 	GRPCPort string
+
+	// 계획 수립 시 기존 코드 검색에 사용할 벡터 저장소 (none, pinecone, milvus)
+	VectorDB       string
+	PineconeApiKey string
+	MilvusHost     string
+	MilvusPort     string
+	RAGTopK        int
+
+	// 계획 삭제 시 구현 Job 정리를 위한 Implementation 서비스 엔드포인트
+	ImplementationServiceAddr string
 }
 
 func GetEnv(key, defaultValue string) string {
@@ -25,10 +56,31 @@ func GetEnv(key, defaultValue string) string {
 	return value
 }
 
+func GetEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("environment variable %s must be an integer: %v", key, err)
+	}
+	return parsed, nil
+}
+
 func GetConfig() (*Config, error) {
 	config := &Config{
 		OpenAiKey: GetEnv("OPENAI_API_KEY", ""),
 
+		LLMProvider:       GetEnv("LLM_PROVIDER", "openai"),
+		OpenAIBaseURL:     GetEnv("OPENAI_BASE_URL", ""),
+		LLMChatModel:      GetEnv("LLM_CHAT_MODEL", ""),
+		LLMEmbeddingModel: GetEnv("LLM_EMBEDDING_MODEL", ""),
+		FakeLLMScript:     GetEnv("FAKE_LLM_SCRIPT", ""),
+
+		CredentialEncryptionKey: GetEnv("CREDENTIAL_ENCRYPTION_KEY", ""),
+		TenantLLMAllowedHosts:   GetEnv("TENANT_LLM_ALLOWED_HOSTS", ""),
+
 		MySQLUser:     GetEnv("MYSQL_USER", "mainuser"),
 		MySQLPassword: GetEnv("MYSQL_PASSWORD", "user123"),
 		MySQLHost:     GetEnv("MYSQL_HOST", "localhost"),
@@ -36,9 +88,46 @@ func GetConfig() (*Config, error) {
 		MySQLDB:       GetEnv("MYSQL_DB", "codev"),
 
 		GRPCPort: GetEnv("GRPC_PORT", "9091"),
+
+		VectorDB:       GetEnv("VECTOR_DB", "none"),
+		PineconeApiKey: GetEnv("PINECONE_API_KEY", ""),
+		MilvusHost:     GetEnv("MILVUS_HOST", "localhost"),
+		MilvusPort:     GetEnv("MILVUS_PORT", "19530"),
+
+		ImplementationServiceAddr: GetEnv("IMPLEMENTATION_SERVICE_ADDR", "localhost:9092"),
+	}
+
+	intSettings := []struct {
+		key          string
+		defaultValue int
+		target       *int
+	}{
+		{"LLM_MAX_CONCURRENCY", 16, &config.LLMMaxConcurrency},
+		{"LLM_REQUESTS_PER_MINUTE", 0, &config.LLMRequestsPerMinute},
+		{"LLM_TOKENS_PER_MINUTE", 0, &config.LLMTokensPerMinute},
+		{"LLM_TENANT_MAX_CONCURRENCY", 8, &config.LLMTenantMaxConcurrency},
+		{"LLM_TENANT_REQUESTS_PER_MINUTE", 0, &config.LLMTenantRequestsPerMinute},
+		{"LLM_TENANT_TOKENS_PER_MINUTE", 0, &config.LLMTenantTokensPerMinute},
+	}
+	for _, setting := range intSettings {
+		value, err := GetEnvInt(setting.key, setting.defaultValue)
+		if err != nil {
+			return nil, err
+		}
+		*setting.target = value
+	}
+
+	ragTopK, err := GetEnvInt("RAG_TOP_K", 5)
+	if err != nil {
+		return nil, err
+	}
+	config.RAGTopK = ragTopK
+
+	if config.VectorDB == "pinecone" && config.PineconeApiKey == "" {
+		return nil, fmt.Errorf("environment variable PINECONE_API_KEY is required when VECTOR_DB is pinecone")
 	}
 
-	if config.OpenAiKey == "" {
+	if config.OpenAiKey == "" && config.LLMProvider != "fake" {
 		return nil, fmt.Errorf("environment variable OPENAI_API_KEY is required but not set")
 	}
 
@@ -3,6 +3,7 @@ module codev42-plan
 go 1.25.0
 
 require (
+	codev42-llm v0.0.0
 	ariga.io/atlas-provider-gorm v0.6.0
 	github.com/invopop/jsonschema v0.13.0
 	github.com/milvus-io/milvus-sdk-go/v2 v2.4.2
@@ -10,7 +11,9 @@ require (
 	github.com/pinecone-io/go-pinecone v1.1.1
 	google.golang.org/grpc v1.77.0
 	google.golang.org/protobuf v1.36.10
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/driver/mysql v1.6.0
+	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.31.1
 )
 
@@ -103,8 +106,8 @@ require (
 	google.golang.org/genproto v0.0.0-20250804133106-a7a43d27e69b // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20251022142026-3a174f9686a8 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20251022142026-3a174f9686a8 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gorm.io/driver/postgres v1.5.11 // indirect
-	gorm.io/driver/sqlite v1.6.0 // indirect
 	gorm.io/driver/sqlserver v1.5.4 // indirect
 )
+
+replace codev42-llm => ../llm
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"codev42-plan/proto/plan"
+	"codev42-plan/service"
+)
+
+// 개발 계획을 문서로 내보내기
+func (h *PlanHandler) ExportPlan(ctx context.Context, request *plan.ExportPlanRequest) (*plan.ExportPlanResponse, error) {
+	format, err := service.ParsePlanFormat(request.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	devPlan, err := h.planSvc.GetDevPlanByID(ctx, request.DevPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %v", err)
+	}
+
+	content, err := service.ExportDevPlan(devPlan, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export plan: %v", err)
+	}
+
+	return &plan.ExportPlanResponse{
+		Format:  format,
+		Content: content,
+	}, nil
+}
+
+// 문서에서 새 개발 계획 가져오기
+func (h *PlanHandler) ImportPlan(ctx context.Context, request *plan.ImportPlanRequest) (*plan.ImportPlanResponse, error) {
+	// 1. 문서 파싱 및 검증 (오류는 줄 번호 포함)
+	devPlan, err := service.ImportDevPlan(request.Content, request.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import plan: %v", err)
+	}
+	devPlan.ProjectID = request.ProjectId
+	devPlan.Branch = request.Branch
+
+	// 2. 프로젝트가 존재하는지 확인, 없으면 생성
+	if err := h.ensureProject(ctx, request.ProjectId, request.Branch); err != nil {
+		return nil, err
+	}
+
+	// 3. 데이터베이스에 저장
+	if err := h.planSvc.CreateDevPlanWithDetails(ctx, devPlan); err != nil {
+		return nil, fmt.Errorf("failed to save plan: %v", err)
+	}
+
+	response := createPBResponse(devPlan)
+	return &plan.ImportPlanResponse{
+		DevPlanId: response.DevPlanId,
+		Language:  response.Language,
+		Plans:     response.Plans,
+	}, nil
+}
@@ -3,9 +3,12 @@ package handler
 import (
 	"context"
 	"fmt"
+	"log"
 
+	"codev42-llm/client"
 	"codev42-plan/configs"
 	"codev42-plan/model"
+	"codev42-plan/proto/implementation"
 	"codev42-plan/proto/plan"
 	"codev42-plan/service"
 	"codev42-plan/storage"
@@ -18,23 +21,44 @@ type PlanHandler struct {
 	DB          *storage.RDBConnection
 	planSvc     *service.PlanService
 	masterAgent *service.MasterAgent
+
+	// 계획 수립 시 기존 코드를 검색 (VECTOR_DB 미설정 시 nil)
+	codeRetriever *service.CodeRetriever
+
+	// 테넌트별 LLM 자격 증명 (CREDENTIAL_ENCRYPTION_KEY 미설정 시 nil)
+	credentialStore *client.CredentialStore
+
+	// 계획 삭제 시 구현 Job/결과 정리용
+	implementationClient implementation.ImplementationServiceClient
 }
 
-func NewPlanHandler(config configs.Config, db *storage.RDBConnection) *PlanHandler {
+func NewPlanHandler(config configs.Config, db *storage.RDBConnection, llm client.LLMProvider, credentialStore *client.CredentialStore, implementationClient implementation.ImplementationServiceClient, vectorDB repo.VectorDB) *PlanHandler {
 	// 저장소 초기화
 	devPlanRepo := repo.NewDevPlanRepository(db)
 	planRepo := repo.NewPlanRepository(db)
 	annotationRepo := repo.NewAnnotationRepository(db)
+	revisionRepo := repo.NewDevPlanRevisionRepository(db)
+	dependencyRepo := repo.NewPlanDependencyRepository(db)
 
 	// 서비스 초기화
-	planSvc := service.NewPlanService(devPlanRepo, planRepo, annotationRepo)
-	masterAgent := service.NewMasterAgent(config.OpenAiKey)
+	planSvc := service.NewPlanService(db, devPlanRepo, planRepo, annotationRepo, revisionRepo, dependencyRepo)
+	masterAgent := service.NewMasterAgent(llm)
+
+	var codeRetriever *service.CodeRetriever
+	if vectorDB != nil {
+		codeRetriever = service.NewCodeRetriever(llm, vectorDB, repo.NewCodeRepo(db), config.RAGTopK)
+	}
 
 	return &PlanHandler{
 		Config:      config,
 		DB:          db,
 		planSvc:     planSvc,
 		masterAgent: masterAgent,
+
+		codeRetriever: codeRetriever,
+
+		credentialStore:      credentialStore,
+		implementationClient: implementationClient,
 	}
 }
 
@@ -62,6 +86,7 @@ func convertServiceDevPlanToModelDevPlan(projectID, branch string, devPlan *serv
 						}
 						return annotations
 					}(),
+					DependsOn: plan.DependsOn,
 				}
 			}
 			return plans
@@ -86,6 +111,8 @@ func createPBResponse(devPlan *model.DevPlan) *plan.GeneratePlanResponse {
 		pbPlans[i] = &plan.Plan{
 			ClassName:   modelPlan.ClassName,
 			Annotations: pbAnnotations,
+			DependsOn:   modelPlan.DependsOn,
+			PlanId:      modelPlan.ID,
 		}
 	}
 
@@ -96,33 +123,51 @@ func createPBResponse(devPlan *model.DevPlan) *plan.GeneratePlanResponse {
 	}
 }
 
+// 프로젝트가 존재하는지 확인하고 없으면 생성
+func (h *PlanHandler) ensureProject(ctx context.Context, projectID, branch string) error {
+	projectRepo := repo.NewProjectRepo(h.DB)
+	if _, err := projectRepo.GetProjectByID(ctx, projectID, branch); err == nil {
+		return nil
+	}
+	project := &model.Project{
+		ID:     projectID,
+		Branch: branch,
+		Name:   projectID,
+	}
+	if err := projectRepo.CreateProject(ctx, project); err != nil {
+		return fmt.Errorf("failed to create project: %v", err)
+	}
+	return nil
+}
+
 // 새로운 개발 계획 생성
 func (h *PlanHandler) GeneratePlan(ctx context.Context, request *plan.GeneratePlanRequest) (*plan.GeneratePlanResponse, error) {
-	// 1. 마스터 에이전트를 사용하여 계획 생성
-	devPlan, err := h.masterAgent.Call(request.Prompt)
+	// 1. 프롬프트와 관련된 프로젝트의 기존 코드 검색 (실패해도 계획 수립은 계속 진행)
+	var relatedCodes []service.RelatedCode
+	if h.codeRetriever != nil {
+		codes, err := h.codeRetriever.Retrieve(ctx, request.ProjectId, request.Branch, request.Prompt)
+		if err != nil {
+			log.Printf("failed to retrieve related code for %s/%s: %v", request.ProjectId, request.Branch, err)
+		}
+		relatedCodes = codes
+	}
+
+	// 2. 마스터 에이전트를 사용하여 계획 생성
+	devPlan, err := h.masterAgent.Call(ctx, request.Prompt, relatedCodes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate plan: %v", err)
 	}
 
-	// 2. 프로젝트가 존재하는지 확인, 없으면 생성
-	projectRepo := repo.NewProjectRepo(h.DB)
-	project, err := projectRepo.GetProjectByID(ctx, request.ProjectId, request.Branch)
-	if err != nil {
-		// 프로젝트가 존재하지 않으면 생성
-		project = &model.Project{
-			ID:     request.ProjectId,
-			Branch: request.Branch,
-			Name:   request.ProjectId,
-		}
-		if err := projectRepo.CreateProject(ctx, project); err != nil {
-			return nil, fmt.Errorf("failed to create project: %v", err)
-		}
+	// 3. 프로젝트가 존재하는지 확인, 없으면 생성
+	if err := h.ensureProject(ctx, request.ProjectId, request.Branch); err != nil {
+		return nil, err
 	}
 
-	// 3. service DevPlan을 model DevPlan으로 변환
+	// 4. service DevPlan을 model DevPlan으로 변환
 	modelDevPlan := convertServiceDevPlanToModelDevPlan(request.ProjectId, request.Branch, devPlan, request.Prompt)
+	service.PruneUnknownDependencies(modelDevPlan.Plans)
 
-	// 4. 데이터베이스에 저장
+	// 5. 데이터베이스에 저장
 	if err := h.planSvc.CreateDevPlanWithDetails(ctx, modelDevPlan); err != nil {
 		return nil, fmt.Errorf("failed to save plan: %v", err)
 	}
@@ -148,6 +193,7 @@ func (h *PlanHandler) ModifyPlan(ctx context.Context, request *plan.ModifyPlanRe
 		modelPlans[i] = model.Plan{
 			ClassName:   pbPlan.ClassName,
 			Annotations: annotations,
+			DependsOn:   pbPlan.DependsOn,
 		}
 	}
 
@@ -192,6 +238,8 @@ func (h *PlanHandler) GetPlanById(ctx context.Context, request *plan.GetPlanById
 		pbPlans[i] = &plan.Plan{
 			ClassName:   modelPlan.ClassName,
 			Annotations: pbAnnotations,
+			DependsOn:   modelPlan.DependsOn,
+			PlanId:      modelPlan.ID,
 		}
 	}
 
@@ -224,3 +272,147 @@ func (h *PlanHandler) GetPlanList(ctx context.Context, request *plan.GetPlanList
 		DevPlanList: pbList,
 	}, nil
 }
+
+// 개발 계획 삭제
+func (h *PlanHandler) DeletePlan(ctx context.Context, request *plan.DeletePlanRequest) (*plan.DeletePlanResponse, error) {
+	// 계획이 남아 있는 동안 Job을 먼저 지워 실패 시 다시 시도할 수 있게 함.
+	// 실행 중인 Job은 Implementation 서비스가 취소하고 워커가 멈출 때까지 기다리며,
+	// 그 뒤에 남은 구현 실행 기록은 DevPlan 삭제 시 외래 키(ON DELETE CASCADE)로 함께 삭제됨
+	deletedJobs, err := h.deleteImplementationJobs(ctx, []int64{request.DevPlanId})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.planSvc.DeleteDevPlan(ctx, request.DevPlanId); err != nil {
+		return nil, fmt.Errorf("failed to delete plan: %v", err)
+	}
+
+	return &plan.DeletePlanResponse{
+		Status:      "success",
+		DeletedJobs: deletedJobs,
+	}, nil
+}
+
+// 프로젝트 브랜치의 모든 개발 계획 삭제
+func (h *PlanHandler) DeletePlansByBranch(ctx context.Context, request *plan.DeletePlansByBranchRequest) (*plan.DeletePlansByBranchResponse, error) {
+	if request.ProjectId == "" || request.Branch == "" {
+		return nil, fmt.Errorf("project id and branch are required")
+	}
+
+	devPlans, err := h.planSvc.GetDevPlansByProjectID(ctx, request.ProjectId, request.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan list: %v", err)
+	}
+	devPlanIDs := make([]int64, len(devPlans))
+	for i, dp := range devPlans {
+		devPlanIDs[i] = dp.ID
+	}
+
+	deletedJobs, err := h.deleteImplementationJobs(ctx, devPlanIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedIDs, err := h.planSvc.DeleteDevPlansByBranch(ctx, request.ProjectId, request.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete plans: %v", err)
+	}
+
+	return &plan.DeletePlansByBranchResponse{
+		Status:      "success",
+		DevPlanIds:  deletedIDs,
+		DeletedJobs: deletedJobs,
+	}, nil
+}
+
+// Implementation 서비스에 저장된 Job과 결과(다이어그램 포함), 구현 실행 기록 삭제
+func (h *PlanHandler) deleteImplementationJobs(ctx context.Context, devPlanIDs []int64) (int64, error) {
+	if len(devPlanIDs) == 0 {
+		return 0, nil
+	}
+
+	resp, err := h.implementationClient.DeleteJobsByDevPlan(
+		client.WithTenant(ctx, client.TenantFromContext(ctx)),
+		&implementation.DeleteJobsByDevPlanRequest{DevPlanIds: devPlanIDs},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete implementation jobs: %v", err)
+	}
+	return resp.DeletedJobs, nil
+}
+
+// 테넌트별 LLM provider 자격 증명 등록
+func (h *PlanHandler) RegisterProviderCredential(ctx context.Context, request *plan.RegisterProviderCredentialRequest) (*plan.RegisterProviderCredentialResponse, error) {
+	if h.credentialStore == nil {
+		return nil, fmt.Errorf("credential store is not configured")
+	}
+	tenantID, err := credentialTenant(ctx, request.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	if request.ApiKey == "" {
+		return nil, fmt.Errorf("api key is required")
+	}
+
+	provider := request.Provider
+	if provider == "" {
+		provider = "openai"
+	}
+	// OpenAI 호환 서버는 BaseUrl로 지정 (서비스가 대신 요청하므로 내부 주소는 거부)
+	if provider != "openai" {
+		return nil, fmt.Errorf("unsupported llm provider: %s", provider)
+	}
+	if err := client.ValidateTenantBaseURL(ctx, request.BaseUrl, client.ParseAllowedHosts(h.Config.TenantLLMAllowedHosts)); err != nil {
+		return nil, err
+	}
+
+	err = h.credentialStore.SaveCredential(ctx, client.Credential{
+		TenantID: tenantID,
+		Provider: provider,
+		APIKey:   request.ApiKey,
+		BaseURL:  request.BaseUrl,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register credential: %v", err)
+	}
+
+	return &plan.RegisterProviderCredentialResponse{
+		TenantId: tenantID,
+		Status:   "success",
+	}, nil
+}
+
+// 테넌트 LLM provider 자격 증명 삭제
+func (h *PlanHandler) DeleteProviderCredential(ctx context.Context, request *plan.DeleteProviderCredentialRequest) (*plan.DeleteProviderCredentialResponse, error) {
+	if h.credentialStore == nil {
+		return nil, fmt.Errorf("credential store is not configured")
+	}
+
+	tenantID, err := credentialTenant(ctx, request.TenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := h.credentialStore.DeleteCredential(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete credential: %v", err)
+	}
+
+	return &plan.DeleteProviderCredentialResponse{
+		TenantId: tenantID,
+		Deleted:  deleted,
+	}, nil
+}
+
+// credentialTenant 요청 테넌트(게이트웨이가 인증하여 x-tenant-id로 전달)의 자격 증명만 관리하도록 확인.
+// 요청의 TenantId가 비어 있으면 요청 테넌트를 사용
+func credentialTenant(ctx context.Context, requested string) (string, error) {
+	tenantID := client.TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", fmt.Errorf("tenant is not authenticated")
+	}
+	if requested != "" && requested != tenantID {
+		return "", fmt.Errorf("cannot manage credentials of another tenant")
+	}
+	return tenantID, nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"codev42-plan/proto/plan"
+	"codev42-plan/service"
+)
+
+func convertServiceAnnotationsToPB(annotations []service.Annotation) []*plan.Annotation {
+	pbAnnotations := make([]*plan.Annotation, len(annotations))
+	for i, annotation := range annotations {
+		pbAnnotations[i] = &plan.Annotation{
+			Name:        annotation.Name,
+			Params:      annotation.Params,
+			Returns:     annotation.Returns,
+			Description: annotation.Description,
+		}
+	}
+	return pbAnnotations
+}
+
+func convertPBAnnotationsToService(pbAnnotations []*plan.Annotation) []service.Annotation {
+	annotations := make([]service.Annotation, len(pbAnnotations))
+	for i, pbAnnotation := range pbAnnotations {
+		annotations[i] = service.Annotation{
+			Name:        pbAnnotation.Name,
+			Params:      pbAnnotation.Params,
+			Returns:     pbAnnotation.Returns,
+			Description: pbAnnotation.Description,
+		}
+	}
+	return annotations
+}
+
+// service.PlanDelta를 plan.PlanDelta로 변환
+func convertPlanDeltaToPB(delta *service.PlanDelta) *plan.PlanDelta {
+	pbAdded := make([]*plan.Plan, len(delta.AddedPlans))
+	for i, added := range delta.AddedPlans {
+		pbAdded[i] = &plan.Plan{
+			ClassName:   added.ClassName,
+			Annotations: convertServiceAnnotationsToPB(added.Annotations),
+			DependsOn:   added.DependsOn,
+		}
+	}
+
+	pbChanged := make([]*plan.PlanChange, len(delta.ChangedPlans))
+	for i, change := range delta.ChangedPlans {
+		pbChanged[i] = &plan.PlanChange{
+			Name:               change.Name,
+			AddedAnnotations:   convertServiceAnnotationsToPB(change.AddedAnnotations),
+			ChangedAnnotations: convertServiceAnnotationsToPB(change.ChangedAnnotations),
+			RemovedAnnotations: change.RemovedAnnotations,
+		}
+	}
+
+	return &plan.PlanDelta{
+		AddedPlans:   pbAdded,
+		RemovedPlans: delta.RemovedPlans,
+		ChangedPlans: pbChanged,
+	}
+}
+
+// plan.PlanDelta를 service.PlanDelta로 변환
+func convertPBPlanDeltaToService(pbDelta *plan.PlanDelta) *service.PlanDelta {
+	delta := &service.PlanDelta{}
+	if pbDelta == nil {
+		return delta
+	}
+
+	for _, pbAdded := range pbDelta.AddedPlans {
+		delta.AddedPlans = append(delta.AddedPlans, service.Plan{
+			ClassName:   pbAdded.ClassName,
+			Annotations: convertPBAnnotationsToService(pbAdded.Annotations),
+			DependsOn:   pbAdded.DependsOn,
+		})
+	}
+	delta.RemovedPlans = pbDelta.RemovedPlans
+	for _, pbChange := range pbDelta.ChangedPlans {
+		delta.ChangedPlans = append(delta.ChangedPlans, service.PlanChange{
+			Name:               pbChange.Name,
+			AddedAnnotations:   convertPBAnnotationsToService(pbChange.AddedAnnotations),
+			ChangedAnnotations: convertPBAnnotationsToService(pbChange.ChangedAnnotations),
+			RemovedAnnotations: pbChange.RemovedAnnotations,
+		})
+	}
+	return delta
+}
+
+// 피드백을 반영한 개발 계획 변경 사항 제안
+func (h *PlanHandler) RefinePlan(ctx context.Context, request *plan.RefinePlanRequest) (*plan.RefinePlanResponse, error) {
+	if request.Feedback == "" {
+		return nil, fmt.Errorf("feedback is required")
+	}
+
+	// 1. 제안의 기준이 되는 리비전과 현재 계획 조회
+	baseRevision, err := h.planSvc.CurrentRevision(ctx, request.DevPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current revision: %v", err)
+	}
+	devPlan, err := h.planSvc.GetDevPlanByID(ctx, request.DevPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan: %v", err)
+	}
+
+	// 2. 마스터 에이전트로 변경 사항 생성
+	delta, err := h.masterAgent.Refine(ctx, service.ToServiceDevPlan(devPlan), request.Feedback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refine plan: %v", err)
+	}
+
+	// 3. 현재 계획에 적용할 수 없는 제안은 반환하지 않음
+	if err := service.ValidatePlanDelta(devPlan, delta); err != nil {
+		return nil, fmt.Errorf("refinement does not apply to the current plan: %v", err)
+	}
+
+	return &plan.RefinePlanResponse{
+		DevPlanId:    devPlan.ID,
+		BaseRevision: baseRevision,
+		Summary:      delta.Summary,
+		Delta:        convertPlanDeltaToPB(delta),
+	}, nil
+}
+
+// 제안된 변경 사항 수락
+func (h *PlanHandler) AcceptPlanRefinement(ctx context.Context, request *plan.AcceptPlanRefinementRequest) (*plan.AcceptPlanRefinementResponse, error) {
+	revision, err := h.planSvc.AcceptRefinement(ctx, request.DevPlanId, request.BaseRevision, convertPBPlanDeltaToService(request.Delta))
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept refinement: %v", err)
+	}
+
+	return &plan.AcceptPlanRefinementResponse{
+		Status:   "success",
+		Revision: revision.Revision,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"codev42-plan/model"
+	"codev42-plan/proto/plan"
+)
+
+// model.SnapshotAnnotation을 plan.Annotation으로 변환 (nil이면 nil)
+func convertSnapshotAnnotationToPB(annotation *model.SnapshotAnnotation) *plan.Annotation {
+	if annotation == nil {
+		return nil
+	}
+	return &plan.Annotation{
+		Name:        annotation.Name,
+		Params:      annotation.Params,
+		Returns:     annotation.Returns,
+		Description: annotation.Description,
+	}
+}
+
+// 스냅샷의 계획 목록을 pb 형식으로 변환
+func convertSnapshotPlansToPB(snapshotPlans []model.SnapshotPlan) []*plan.Plan {
+	pbPlans := make([]*plan.Plan, len(snapshotPlans))
+	for i, snapshotPlan := range snapshotPlans {
+		pbAnnotations := make([]*plan.Annotation, len(snapshotPlan.Annotations))
+		for j := range snapshotPlan.Annotations {
+			pbAnnotations[j] = convertSnapshotAnnotationToPB(&snapshotPlan.Annotations[j])
+		}
+		pbPlans[i] = &plan.Plan{
+			ClassName:   snapshotPlan.ClassName,
+			Annotations: pbAnnotations,
+			DependsOn:   snapshotPlan.DependsOn,
+		}
+	}
+	return pbPlans
+}
+
+// 개발 계획의 리비전 목록 조회
+func (h *PlanHandler) ListPlanRevisions(ctx context.Context, request *plan.ListPlanRevisionsRequest) (*plan.ListPlanRevisionsResponse, error) {
+	revisions, err := h.planSvc.ListRevisions(ctx, request.DevPlanId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plan revisions: %v", err)
+	}
+
+	pbRevisions := make([]*plan.PlanRevisionElement, len(revisions))
+	for i, revision := range revisions {
+		pbRevisions[i] = &plan.PlanRevisionElement{
+			Revision:  revision.Revision,
+			Action:    revision.Action,
+			CreatedAt: revision.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &plan.ListPlanRevisionsResponse{
+		Revisions: pbRevisions,
+	}, nil
+}
+
+// 특정 리비전의 계획 스냅샷 조회
+func (h *PlanHandler) GetPlanRevision(ctx context.Context, request *plan.GetPlanRevisionRequest) (*plan.GetPlanRevisionResponse, error) {
+	revision, snapshot, err := h.planSvc.GetRevision(ctx, request.DevPlanId, request.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plan revision: %v", err)
+	}
+
+	return &plan.GetPlanRevisionResponse{
+		DevPlanId: revision.DevPlanID,
+		Revision:  revision.Revision,
+		Action:    revision.Action,
+		CreatedAt: revision.CreatedAt.Format(time.RFC3339),
+		Language:  snapshot.Language,
+		Plans:     convertSnapshotPlansToPB(snapshot.Plans),
+	}, nil
+}
+
+// 두 리비전 비교
+func (h *PlanHandler) DiffPlanRevisions(ctx context.Context, request *plan.DiffPlanRevisionsRequest) (*plan.DiffPlanRevisionsResponse, error) {
+	diff, err := h.planSvc.DiffRevisions(ctx, request.DevPlanId, request.FromRevision, request.ToRevision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff plan revisions: %v", err)
+	}
+
+	pbPlans := make([]*plan.PlanDiff, len(diff.Plans))
+	for i, planDiff := range diff.Plans {
+		pbAnnotations := make([]*plan.AnnotationDiff, len(planDiff.Annotations))
+		for j, annotationDiff := range planDiff.Annotations {
+			pbAnnotations[j] = &plan.AnnotationDiff{
+				Name:       annotationDiff.Name,
+				ChangeType: annotationDiff.ChangeType,
+				Before:     convertSnapshotAnnotationToPB(annotationDiff.Before),
+				After:      convertSnapshotAnnotationToPB(annotationDiff.After),
+			}
+		}
+		pbPlans[i] = &plan.PlanDiff{
+			ClassName:       planDiff.ClassName,
+			ChangeType:      planDiff.ChangeType,
+			Annotations:     pbAnnotations,
+			DependsOnBefore: planDiff.DependsOnBefore,
+			DependsOnAfter:  planDiff.DependsOnAfter,
+		}
+	}
+
+	return &plan.DiffPlanRevisionsResponse{
+		DevPlanId:    request.DevPlanId,
+		FromRevision: diff.FromRevision,
+		ToRevision:   diff.ToRevision,
+		FromLanguage: diff.FromLanguage,
+		ToLanguage:   diff.ToLanguage,
+		Plans:        pbPlans,
+	}, nil
+}
+
+// 개발 계획을 특정 리비전으로 되돌림
+func (h *PlanHandler) RevertPlan(ctx context.Context, request *plan.RevertPlanRequest) (*plan.RevertPlanResponse, error) {
+	revision, err := h.planSvc.RevertDevPlan(ctx, request.DevPlanId, request.Revision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revert plan: %v", err)
+	}
+
+	return &plan.RevertPlanResponse{
+		Status:   "success",
+		Revision: revision.Revision,
+	}, nil
+}
@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"net/url"
+	"time"
 
 	_ "ariga.io/atlas-provider-gorm/gormschema"
 
+	"codev42-llm/client"
 	"codev42-plan/configs"
 	"codev42-plan/handler"
+	"codev42-plan/proto/implementation"
 	"codev42-plan/proto/plan"
+	"codev42-plan/service"
 	"codev42-plan/storage"
+	"codev42-plan/storage/repo"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
 
@@ -23,6 +30,22 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	providerConfig := client.ProviderConfig{
+		Provider:       config.LLMProvider,
+		APIKey:         config.OpenAiKey,
+		BaseURL:        config.OpenAIBaseURL,
+		ChatModel:      config.LLMChatModel,
+		EmbeddingModel: config.LLMEmbeddingModel,
+		FakeScriptPath: config.FakeLLMScript,
+
+		TenantAllowedHosts: client.ParseAllowedHosts(config.TenantLLMAllowedHosts),
+	}
+	llmProvider, err := client.NewLLMProvider(providerConfig)
+	if err != nil {
+		log.Fatalf("Failed to create LLM provider: %v", err)
+	}
+	log.Printf("Using %s LLM provider", config.LLMProvider)
+
 	dsn := fmt.Sprintf(
 		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		config.MySQLUser,
@@ -47,6 +70,55 @@ func main() {
 
 	log.Println("Successfully connected to MySQL")
 
+	// 테넌트별 자격 증명 (x-tenant-id metadata로 요청마다 선택)
+	var credentialStore *client.CredentialStore
+	var credentialResolver client.CredentialResolver
+	if config.CredentialEncryptionKey != "" {
+		cipher, err := client.NewCipher(config.CredentialEncryptionKey)
+		if err != nil {
+			log.Fatalf("Failed to create credential cipher: %v", err)
+		}
+		credentialStore = client.NewCredentialStore(rdbConnection.DB, cipher)
+		credentialResolver = credentialStore
+		log.Printf("Per-tenant LLM credentials enabled")
+	}
+	// 서비스 전체와 테넌트별 호출 한도를 넘은 LLM 호출은 대기열에서 차례를 기다림
+	limiter := client.NewLimiter(
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMMaxConcurrency,
+			RequestsPerMinute: config.LLMRequestsPerMinute,
+			TokensPerMinute:   config.LLMTokensPerMinute,
+		},
+		client.LimitConfig{
+			MaxConcurrency:    config.LLMTenantMaxConcurrency,
+			RequestsPerMinute: config.LLMTenantRequestsPerMinute,
+			TokensPerMinute:   config.LLMTenantTokensPerMinute,
+		},
+	)
+	llm := client.NewRateLimitedProvider(client.NewProviderPool(llmProvider, credentialResolver, providerConfig), limiter)
+
+	// 계획 수립 시 기존 코드 검색용 벡터 저장소
+	vectorDB, err := newVectorDB(config)
+	if err != nil {
+		log.Fatalf("Failed to set up vector DB: %v", err)
+	}
+	if vectorDB != nil {
+		defer vectorDB.Close()
+		log.Printf("Retrieval-augmented planning enabled with %s (top %d)", config.VectorDB, config.RAGTopK)
+	}
+
+	// 계획 삭제 시 연결된 구현 Job을 함께 정리
+	log.Printf("Connecting to Implementation Service at %s", config.ImplementationServiceAddr)
+	implementationConn, err := grpc.NewClient(
+		config.ImplementationServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		log.Fatalf("Failed to connect to Implementation Service: %v", err)
+	}
+	defer implementationConn.Close()
+	implementationClient := implementation.NewImplementationServiceClient(implementationConn)
+
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", config.GRPCPort))
 	if err != nil {
 		log.Fatalf("Failed to create TCP listener: %v", err)
@@ -54,7 +126,7 @@ func main() {
 
 	grpcServer := grpc.NewServer()
 
-	planHandler := handler.NewPlanHandler(*config, rdbConnection)
+	planHandler := handler.NewPlanHandler(*config, rdbConnection, llm, credentialStore, implementationClient, vectorDB)
 	plan.RegisterPlanServiceServer(grpcServer, planHandler)
 
 	reflection.Register(grpcServer)
@@ -64,3 +136,34 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// newVectorDB VECTOR_DB 설정에 맞는 벡터 저장소 연결 (none이면 nil)
+func newVectorDB(config *configs.Config) (repo.VectorDB, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var vectorDB repo.VectorDB
+	switch config.VectorDB {
+	case "", "none":
+		return nil, nil
+	case "pinecone":
+		pineconeConn, err := storage.NewPineconeConnection(ctx, config.PineconeApiKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to pinecone: %v", err)
+		}
+		vectorDB = repo.NewPineconeRepo(pineconeConn)
+	case "milvus":
+		milvusConn, err := storage.NewMilvusConnection(ctx, fmt.Sprintf("%s:%s", config.MilvusHost, config.MilvusPort))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to milvus: %v", err)
+		}
+		vectorDB = repo.NewMilvusRepo(milvusConn)
+	default:
+		return nil, fmt.Errorf("unknown vector db: %s", config.VectorDB)
+	}
+
+	if err := vectorDB.InitCollection(ctx, service.CodeCollectionName, service.CodeEmbeddingDimensions); err != nil {
+		return nil, fmt.Errorf("failed to init collection: %v", err)
+	}
+	return vectorDB, nil
+}
@@ -24,6 +24,19 @@ type Plan struct {
 	UpdatedAt   time.Time    `gorm:"autoUpdateTime"`
 	ClassName   string       `gorm:"type:varchar(255);not null"`
 	Annotations []Annotation `gorm:"foreignKey:PlanID;references:ID"`
+
+	// 의존하는 같은 DevPlan의 계획 이름 (클래스 이름, 함수 계획이면 함수 이름). plan_dependencies에 ID로 저장
+	DependsOn []string `gorm:"-"`
+}
+
+// PlanDependency Plan 간 의존 관계 (PlanID가 DependsOnPlanID를 사용)
+type PlanDependency struct {
+	ID              int64     `gorm:"primaryKey"`
+	PlanID          int64     `gorm:"not null;uniqueIndex:idx_plan_dependencies_edge"`
+	Plan            Plan      `gorm:"foreignKey:PlanID"`
+	DependsOnPlanID int64     `gorm:"not null;uniqueIndex:idx_plan_dependencies_edge;index"`
+	DependsOnPlan   Plan      `gorm:"foreignKey:DependsOnPlanID"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
 }
 
 type DevPlan struct {
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+// DevPlanRevision.Action 값
+const (
+	RevisionActionCreate = "create"
+	RevisionActionModify = "modify"
+	RevisionActionRevert = "revert"
+	RevisionActionRefine = "refine"
+)
+
+// DevPlanRevision DevPlan 생성/수정 시점의 전체 스냅샷
+type DevPlanRevision struct {
+	ID        int64     `gorm:"primaryKey"`
+	DevPlanID int64     `gorm:"not null;uniqueIndex:idx_dev_plan_revisions_revision"`
+	DevPlan   DevPlan   `gorm:"foreignKey:DevPlanID"`
+	Revision  int32     `gorm:"not null;uniqueIndex:idx_dev_plan_revisions_revision"`
+	Action    string    `gorm:"type:varchar(32);not null"`
+	Snapshot  []byte    `gorm:"type:json;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RevisionSnapshot DevPlanRevision.Snapshot에 저장되는 DevPlan 내용
+type RevisionSnapshot struct {
+	Language string         `json:"language"`
+	Plans    []SnapshotPlan `json:"plans"`
+}
+
+type SnapshotPlan struct {
+	ClassName   string               `json:"className"`
+	Annotations []SnapshotAnnotation `json:"annotations"`
+	DependsOn   []string             `json:"dependsOn,omitempty"`
+}
+
+type SnapshotAnnotation struct {
+	Name        string `json:"name"`
+	Params      string `json:"params"`
+	Returns     string `json:"returns"`
+	Description string `json:"description"`
+}
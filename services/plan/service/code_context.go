@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"codev42-llm/client"
+	"codev42-plan/storage/repo"
+)
+
+// 코드 embedding이 저장된 컬렉션 (agent의 SaveCode와 동일한 설정)
+const (
+	CodeCollectionName      = "code"
+	CodeEmbeddingDimensions = 128
+)
+
+// candidateMultiplier 다른 프로젝트의 코드가 걸러지는 것을 감안해 topK보다 넉넉하게 검색
+const candidateMultiplier = 4
+
+// RelatedCode 계획 수립 시 참고할 기존 코드 조각
+type RelatedCode struct {
+	FilePath        string
+	FuncDeclaration string
+	Code            string
+}
+
+// CodeRetriever 프롬프트와 관련된 프로젝트 코드를 벡터 검색으로 조회
+type CodeRetriever struct {
+	LLM      client.LLMProvider
+	VectorDB repo.VectorDB
+	CodeRepo *repo.CodeRepo
+	TopK     int
+}
+
+func NewCodeRetriever(llm client.LLMProvider, vectorDB repo.VectorDB, codeRepo *repo.CodeRepo, topK int) *CodeRetriever {
+	return &CodeRetriever{
+		LLM:      llm,
+		VectorDB: vectorDB,
+		CodeRepo: codeRepo,
+		TopK:     topK,
+	}
+}
+
+// Retrieve 프롬프트를 embedding하여 ProjectId/Branch에 속한 코드 중 유사도가 높은 순으로 최대 TopK개 반환
+func (r *CodeRetriever) Retrieve(ctx context.Context, projectID string, branch string, prompt string) ([]RelatedCode, error) {
+	embedding, err := r.LLM.Embed(ctx, client.EmbeddingRequest{
+		Model:      client.ModelTextEmbedding3Small,
+		Input:      prompt,
+		Dimensions: CodeEmbeddingDimensions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed prompt: %v", err)
+	}
+
+	searchVector := make([]float32, len(embedding))
+	for i, v := range embedding {
+		searchVector[i] = float32(v)
+	}
+
+	ids, err := r.VectorDB.SearchByVector(ctx, CodeCollectionName, searchVector, r.TopK*candidateMultiplier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search code embeddings: %v", err)
+	}
+
+	codes, err := r.CodeRepo.GetProjectCodesByIDs(ctx, ids, projectID, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load related codes: %v", err)
+	}
+	codeByID := make(map[int64]repo.ProjectCode, len(codes))
+	for _, code := range codes {
+		codeByID[code.ID] = code
+	}
+
+	// 검색 결과 순서(유사도 순)를 유지
+	related := make([]RelatedCode, 0, r.TopK)
+	for _, id := range ids {
+		code, ok := codeByID[id]
+		if !ok {
+			continue
+		}
+		related = append(related, RelatedCode{
+			FilePath:        code.FilePath,
+			FuncDeclaration: code.FuncDeclaration,
+			Code:            code.CodeChunk,
+		})
+		if len(related) == r.TopK {
+			break
+		}
+	}
+	return related, nil
+}
+
+// maxRelatedCodeLength 프롬프트에 넣는 코드 조각 하나의 최대 길이
+const maxRelatedCodeLength = 2000
+
+// formatRelatedCodes 기존 코드를 계획 프롬프트에 넣을 문자열로 변환
+func formatRelatedCodes(relatedCodes []RelatedCode) string {
+	formatted := ""
+	for i, code := range relatedCodes {
+		chunk := code.Code
+		if len(chunk) > maxRelatedCodeLength {
+			chunk = chunk[:maxRelatedCodeLength] + "\n..."
+		}
+		formatted += "[" + strconv.Itoa(i+1) + "] " + code.FilePath + " - " + code.FuncDeclaration + "\n" + chunk + "\n\n"
+	}
+	return formatted
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"codev42-plan/model"
+)
+
+// DependencyCycleError 계획 의존 관계에 순환이 있을 때의 검증 에러
+type DependencyCycleError struct {
+	Cycle []string // 순환 경로 (처음과 마지막 이름이 같음)
+}
+
+func (e *DependencyCycleError) Error() string {
+	return "plan dependency cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// ValidatePlanDependencies는 의존 대상이 같은 DevPlan에 하나씩 존재하고 순환이 없는지 확인합니다.
+func ValidatePlanDependencies(plans []model.Plan) error {
+	count := make(map[string]int, len(plans))
+	for _, plan := range plans {
+		count[planKey(plan)]++
+	}
+
+	edges := make(map[string][]string, len(plans))
+	for _, plan := range plans {
+		key := planKey(plan)
+		for _, dependency := range plan.DependsOn {
+			switch count[dependency] {
+			case 0:
+				return fmt.Errorf("plan %q depends on unknown plan %q", key, dependency)
+			case 1:
+			default:
+				return fmt.Errorf("plan %q depends on ambiguous plan %q", key, dependency)
+			}
+			edges[key] = append(edges[key], dependency)
+		}
+	}
+
+	// DFS로 순환 탐색 (0: 미방문, 1: 방문 중, 2: 완료)
+	state := make(map[string]int, len(plans))
+	var path []string
+	var visit func(key string) error
+	visit = func(key string) error {
+		state[key] = 1
+		path = append(path, key)
+		for _, next := range edges[key] {
+			switch state[next] {
+			case 0:
+				if err := visit(next); err != nil {
+					return err
+				}
+			case 1:
+				start := 0
+				for i, name := range path {
+					if name == next {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]string{}, path[start:]...), next)
+				return &DependencyCycleError{Cycle: cycle}
+			}
+		}
+		path = path[:len(path)-1]
+		state[key] = 2
+		return nil
+	}
+
+	for _, plan := range plans {
+		if state[planKey(plan)] == 0 {
+			if err := visit(planKey(plan)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PruneUnknownDependencies는 같은 DevPlan에 없는 의존 대상과 자기 자신에 대한 의존을 제거합니다.
+// LLM이 생성한 계획은 존재하지 않는 외부 클래스를 의존 대상으로 적을 수 있으므로 저장 전에 정리합니다.
+func PruneUnknownDependencies(plans []model.Plan) {
+	known := make(map[string]bool, len(plans))
+	for _, plan := range plans {
+		known[planKey(plan)] = true
+	}
+	for i := range plans {
+		key := planKey(plans[i])
+		var dependsOn []string
+		for _, dependency := range plans[i].DependsOn {
+			if known[dependency] && dependency != key {
+				dependsOn = append(dependsOn, dependency)
+			}
+		}
+		plans[i].DependsOn = dependsOn
+	}
+}
+
+// saveDependencies는 저장된 Plan의 ID로 DependsOn을 plan_dependencies에 기록합니다.
+func (s *PlanService) saveDependencies(ctx context.Context, plans []model.Plan) error {
+	idByKey := make(map[string]int64, len(plans))
+	for _, plan := range plans {
+		idByKey[planKey(plan)] = plan.ID
+	}
+
+	var dependencies []model.PlanDependency
+	for _, plan := range plans {
+		seen := make(map[string]bool, len(plan.DependsOn))
+		for _, dependency := range plan.DependsOn {
+			if seen[dependency] {
+				continue
+			}
+			seen[dependency] = true
+			dependencies = append(dependencies, model.PlanDependency{
+				PlanID:          plan.ID,
+				DependsOnPlanID: idByKey[dependency],
+			})
+		}
+	}
+	return s.dependencyRepo.CreateDependencies(ctx, dependencies)
+}
+
+// attachDependencies는 plan_dependencies를 읽어 각 Plan의 DependsOn을 채웁니다.
+func (s *PlanService) attachDependencies(ctx context.Context, devPlan *model.DevPlan) error {
+	dependencies, err := s.dependencyRepo.GetDependenciesByDevPlanID(ctx, devPlan.ID)
+	if err != nil {
+		return err
+	}
+
+	keyByID := make(map[int64]string, len(devPlan.Plans))
+	for _, plan := range devPlan.Plans {
+		keyByID[plan.ID] = planKey(plan)
+	}
+	dependsOn := make(map[int64][]string, len(devPlan.Plans))
+	for _, dependency := range dependencies {
+		dependsOn[dependency.PlanID] = append(dependsOn[dependency.PlanID], keyByID[dependency.DependsOnPlanID])
+	}
+	for i := range devPlan.Plans {
+		devPlan.Plans[i].DependsOn = dependsOn[devPlan.Plans[i].ID]
+	}
+	return nil
+}
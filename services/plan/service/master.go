@@ -1,13 +1,12 @@
 package service
 
 import (
-	"codev42-plan/client"
+	"codev42-llm/client"
 	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/invopop/jsonschema"
-	"github.com/openai/openai-go"
 )
 
 type Annotation struct {
@@ -20,6 +19,7 @@ type Annotation struct {
 type Plan struct {
 	ClassName   string       `json:"클래스 이름" jsonschema_description:"클래스 이름 (비어있으면 함수)"`
 	Annotations []Annotation `json:"어노테이션" jsonschema_description:"함수와 클래스 메서드에 대한 구조화된 어노테이션"`
+	DependsOn   []string     `json:"의존 계획" jsonschema_description:"이 계획이 사용하는 같은 개발 계획 내 다른 계획의 클래스 이름 (함수 계획이면 함수 이름)"`
 }
 
 type DevPlan struct {
@@ -28,14 +28,12 @@ type DevPlan struct {
 }
 
 type MasterAgent struct {
-	Client *openai.Client
+	LLM client.LLMProvider
 }
 
-func NewMasterAgent(apiKey string) *MasterAgent {
-	openaiClient := client.GetClient(apiKey)
-
+func NewMasterAgent(llm client.LLMProvider) *MasterAgent {
 	return &MasterAgent{
-		Client: openaiClient.Client(),
+		LLM: llm,
 	}
 }
 
@@ -51,36 +49,33 @@ func GenerateDevPlanSchema[T any]() interface{} {
 
 var DevPlanResponseSchema = GenerateDevPlanSchema[DevPlan]()
 
-func (agent MasterAgent) Call(prompt string) (*DevPlan, error) {
+// Call 프롬프트로 개발 계획을 수립. relatedCodes가 있으면 기존 코드의 타입과 함수를 재사용하도록 함께 전달
+func (agent MasterAgent) Call(ctx context.Context, prompt string, relatedCodes []RelatedCode) (*DevPlan, error) {
 	prompt = "프롬프트: " + prompt
+	if len(relatedCodes) > 0 {
+		prompt += "\n프로젝트의 기존 코드:\n" + formatRelatedCodes(relatedCodes)
+	}
 	prompt += `
 	다음 규칙에 따라 개발 계획을 수립해야 합니다
 	규칙: 프롬프트에 대해 함수와 클래스의 어노테이션을 포함한 개발 계획을 목록으로 작성하세요
 	어노테이션은 @name, @params, @returns, @description을 따릅니다
 	클래스를 위한 개발인 경우, ClassName을 제공하고 어노테이션은 메소드 목록이어야 합니다
 	함수를 위한 개발인 경우, ClassName은 비워두고 어노테이션은 하나의 항목만 포함하는 목록이어야 합니다
+	계획이 다른 계획의 클래스나 함수를 사용하면 의존 계획에 그 이름을 적고, 서로 순환하여 의존하지 않도록 하세요
 	`
+	if len(relatedCodes) > 0 {
+		prompt += `기존 코드에 이미 있는 타입과 함수는 새로 만들지 말고 같은 이름과 시그니처로 사용하세요
+	`
+	}
 	print("> ")
 	println(prompt)
 
-	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
-		Name:        openai.F("development_plan"),
-		Description: openai.F("A development plan with annotations of functions and classes"),
-		Schema:      openai.F(DevPlanResponseSchema),
-		Strict:      openai.Bool(true),
-	}
-
-	chat, err := agent.Client.Chat.Completions.New(context.TODO(), openai.ChatCompletionNewParams{
-		Messages: openai.F([]openai.ChatCompletionMessageParamUnion{
-			openai.UserMessage(prompt),
-		}),
-		ResponseFormat: openai.F[openai.ChatCompletionNewParamsResponseFormatUnion](
-			openai.ResponseFormatJSONSchemaParam{
-				Type:       openai.F(openai.ResponseFormatJSONSchemaTypeJSONSchema),
-				JSONSchema: openai.F(schemaParam),
-			},
-		),
-		Model: openai.F(openai.ChatModelGPT4o2024_11_20),
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "development_plan",
+		SchemaDescription: "A development plan with annotations of functions and classes",
+		Schema:            DevPlanResponseSchema,
 	})
 
 	if err != nil {
@@ -88,10 +83,64 @@ func (agent MasterAgent) Call(prompt string) (*DevPlan, error) {
 	}
 
 	devPlan := &DevPlan{}
-	fmt.Printf("Chat: %v\n", chat)
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), devPlan)
+	fmt.Printf("Chat: %v\n", content)
+	err = json.Unmarshal([]byte(content), devPlan)
 	if err != nil {
 		return nil, err
 	}
 	return devPlan, nil
 }
+
+// PlanChange 기존 계획 하나에 대한 어노테이션 변경
+type PlanChange struct {
+	Name               string       `json:"대상" jsonschema_description:"변경할 계획의 클래스 이름 (함수 계획이면 함수 이름)"`
+	AddedAnnotations   []Annotation `json:"추가된 어노테이션" jsonschema_description:"새로 추가할 함수나 메서드"`
+	ChangedAnnotations []Annotation `json:"변경된 어노테이션" jsonschema_description:"이름이 같은 기존 함수나 메서드를 대체할 어노테이션"`
+	RemovedAnnotations []string     `json:"삭제된 어노테이션" jsonschema_description:"삭제할 함수나 메서드 이름"`
+}
+
+// PlanDelta 피드백을 반영하기 위한 개발 계획 변경 사항
+type PlanDelta struct {
+	Summary      string       `json:"요약" jsonschema_description:"변경 사항에 대한 한 문단 요약"`
+	AddedPlans   []Plan       `json:"추가된 계획" jsonschema_description:"새로 추가할 클래스나 함수 계획"`
+	RemovedPlans []string     `json:"삭제된 계획" jsonschema_description:"삭제할 계획의 클래스 이름 (함수 계획이면 함수 이름)"`
+	ChangedPlans []PlanChange `json:"변경된 계획" jsonschema_description:"일부 어노테이션이 바뀌는 기존 계획"`
+}
+
+var PlanDeltaResponseSchema = GenerateDevPlanSchema[PlanDelta]()
+
+// Refine 현재 개발 계획과 자연어 피드백으로 변경 사항(delta)을 제안
+func (agent MasterAgent) Refine(ctx context.Context, current *DevPlan, feedback string) (*PlanDelta, error) {
+	currentJSON, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := "현재 개발 계획: " + string(currentJSON)
+	prompt += "\n피드백: " + feedback
+	prompt += `
+	다음 규칙에 따라 피드백을 반영한 개발 계획의 변경 사항만 작성해야 합니다
+	규칙: 현재 개발 계획 전체를 다시 작성하지 말고, 추가/삭제/변경되는 계획과 어노테이션만 포함하세요
+	계획은 클래스 이름으로, 함수 계획(클래스 이름이 비어있는 계획)은 함수 이름으로 지정합니다
+	변경된 어노테이션은 기존 어노테이션과 이름이 같아야 하며, 이름을 바꾸려면 기존 것을 삭제하고 새로 추가하세요
+	어노테이션은 @name, @params, @returns, @description을 따릅니다
+	함수를 위한 계획을 추가하는 경우, ClassName은 비워두고 어노테이션은 하나의 항목만 포함하는 목록이어야 합니다
+	`
+
+	content, err := agent.LLM.ChatJSON(ctx, client.ChatRequest{
+		Model:             client.ModelGPT4o,
+		Prompt:            prompt,
+		SchemaName:        "development_plan_delta",
+		SchemaDescription: "Changes to a development plan that apply the user's feedback",
+		Schema:            PlanDeltaResponseSchema,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delta := &PlanDelta{}
+	if err := json.Unmarshal([]byte(content), delta); err != nil {
+		return nil, err
+	}
+	return delta, nil
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"codev42-plan/model"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 계획 내보내기/가져오기 형식
+const (
+	PlanFormatMarkdown = "markdown"
+	PlanFormatJSON     = "json"
+	PlanFormatYAML     = "yaml"
+)
+
+// ParsePlanFormat 형식 이름을 정규화 (md, yml 등의 별칭 허용)
+func ParsePlanFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "markdown", "md":
+		return PlanFormatMarkdown, nil
+	case "json":
+		return PlanFormatJSON, nil
+	case "yaml", "yml":
+		return PlanFormatYAML, nil
+	default:
+		return "", fmt.Errorf("unsupported plan format: %q", format)
+	}
+}
+
+// PlanImportError 가져오기 실패 위치를 담은 에러 (Line은 1부터, 위치를 알 수 없으면 0)
+type PlanImportError struct {
+	Line    int
+	Message string
+}
+
+func (e *PlanImportError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// 파일로 주고받는 DevPlan 문서 구조 (JSON/YAML 공용)
+type planDocument struct {
+	Language string             `json:"language" yaml:"language"`
+	Prompt   string             `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Plans    []planDocumentPlan `json:"plans" yaml:"plans"`
+}
+
+type planDocumentPlan struct {
+	ClassName   string                   `json:"className,omitempty" yaml:"className,omitempty"`
+	DependsOn   []string                 `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	Annotations []planDocumentAnnotation `json:"annotations" yaml:"annotations"`
+
+	line         int
+	dependsOnRaw string // Markdown의 @dependsOn 원문 (쉼표 구분)
+}
+
+type planDocumentAnnotation struct {
+	Name        string `json:"name" yaml:"name"`
+	Params      string `json:"params,omitempty" yaml:"params,omitempty"`
+	Returns     string `json:"returns,omitempty" yaml:"returns,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	line int
+}
+
+// UnmarshalYAML 검증 에러에 쓸 줄 번호를 기록
+func (p *planDocumentPlan) UnmarshalYAML(node *yaml.Node) error {
+	type plain planDocumentPlan
+	if err := checkKnownFields(node, "className", "dependsOn", "annotations"); err != nil {
+		return err
+	}
+	if err := node.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.line = node.Line
+	return nil
+}
+
+// UnmarshalYAML 검증 에러에 쓸 줄 번호를 기록
+func (a *planDocumentAnnotation) UnmarshalYAML(node *yaml.Node) error {
+	type plain planDocumentAnnotation
+	if err := checkKnownFields(node, "name", "params", "returns", "description"); err != nil {
+		return err
+	}
+	if err := node.Decode((*plain)(a)); err != nil {
+		return err
+	}
+	a.line = node.Line
+	return nil
+}
+
+// checkKnownFields 오타 등 알 수 없는 키를 거부 (node.Decode는 decoder의 KnownFields를 따르지 않음)
+func checkKnownFields(node *yaml.Node, fields ...string) error {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		known := false
+		for _, field := range fields {
+			if key.Value == field {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("line %d: unknown field %q", key.Line, key.Value)
+		}
+	}
+	return nil
+}
+
+// ExportDevPlan DevPlan을 지정한 형식의 문서로 변환
+func ExportDevPlan(devPlan *model.DevPlan, format string) (string, error) {
+	format, err := ParsePlanFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	doc := planDocument{
+		Language: devPlan.Language,
+		Prompt:   devPlan.Prompt,
+		Plans:    make([]planDocumentPlan, len(devPlan.Plans)),
+	}
+	for i, plan := range devPlan.Plans {
+		annotations := make([]planDocumentAnnotation, len(plan.Annotations))
+		for j, annotation := range plan.Annotations {
+			annotations[j] = planDocumentAnnotation{
+				Name:        annotation.Name,
+				Params:      annotation.Params,
+				Returns:     annotation.Returns,
+				Description: annotation.Description,
+			}
+		}
+		doc.Plans[i] = planDocumentPlan{
+			ClassName:   plan.ClassName,
+			DependsOn:   plan.DependsOn,
+			Annotations: annotations,
+		}
+	}
+
+	switch format {
+	case PlanFormatJSON:
+		data, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal plan: %v", err)
+		}
+		return string(data) + "\n", nil
+	case PlanFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(doc); err != nil {
+			return "", fmt.Errorf("failed to marshal plan: %v", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return "", fmt.Errorf("failed to marshal plan: %v", err)
+		}
+		return buf.String(), nil
+	default:
+		return exportMarkdown(doc), nil
+	}
+}
+
+// ImportDevPlan 문서를 읽어 저장 전의 DevPlan으로 변환 (ProjectID/Branch는 호출자가 설정)
+// 문법 오류와 검증 오류는 줄 번호가 담긴 *PlanImportError로 반환
+func ImportDevPlan(content string, format string) (*model.DevPlan, error) {
+	format, err := ParsePlanFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc *planDocument
+	switch format {
+	case PlanFormatJSON:
+		doc, err = parseJSONDocument(content)
+	case PlanFormatYAML:
+		doc, err = parseYAMLDocument(content)
+	default:
+		doc, err = parseMarkdownDocument(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePlanDocument(doc); err != nil {
+		return nil, err
+	}
+
+	devPlan := &model.DevPlan{
+		Language: doc.Language,
+		Prompt:   doc.Prompt,
+		Plans:    make([]model.Plan, len(doc.Plans)),
+	}
+	for i, plan := range doc.Plans {
+		annotations := make([]model.Annotation, len(plan.Annotations))
+		for j, annotation := range plan.Annotations {
+			annotations[j] = model.Annotation{
+				Name:        annotation.Name,
+				Params:      annotation.Params,
+				Returns:     annotation.Returns,
+				Description: annotation.Description,
+			}
+		}
+		devPlan.Plans[i] = model.Plan{
+			ClassName:   plan.ClassName,
+			Annotations: annotations,
+			DependsOn:   plan.DependsOn,
+		}
+	}
+	return devPlan, nil
+}
+
+// validatePlanDocument 필수 값과 의존 관계를 확인
+func validatePlanDocument(doc *planDocument) error {
+	if strings.TrimSpace(doc.Language) == "" {
+		return &PlanImportError{Line: 1, Message: "language is required"}
+	}
+	if len(doc.Plans) == 0 {
+		return &PlanImportError{Line: 1, Message: "at least one plan is required"}
+	}
+
+	count := make(map[string]int, len(doc.Plans))
+	lineByKey := make(map[string]int, len(doc.Plans))
+	plans := make([]model.Plan, len(doc.Plans))
+	for i, plan := range doc.Plans {
+		if plan.ClassName == "" && len(plan.Annotations) == 0 {
+			return &PlanImportError{Line: plan.line, Message: fmt.Sprintf("plan %d has neither a class name nor annotations", i+1)}
+		}
+		names := make(map[string]bool, len(plan.Annotations))
+		for _, annotation := range plan.Annotations {
+			if strings.TrimSpace(annotation.Name) == "" {
+				return &PlanImportError{Line: annotation.line, Message: "annotation name is required"}
+			}
+			if names[annotation.Name] {
+				return &PlanImportError{Line: annotation.line, Message: fmt.Sprintf("duplicate annotation %q", annotation.Name)}
+			}
+			names[annotation.Name] = true
+		}
+
+		plans[i] = model.Plan{ClassName: plan.ClassName, DependsOn: plan.DependsOn}
+		if plan.ClassName == "" {
+			plans[i].Annotations = []model.Annotation{{Name: plan.Annotations[0].Name}}
+		}
+		key := planKey(plans[i])
+		count[key]++
+		if _, ok := lineByKey[key]; !ok {
+			lineByKey[key] = plan.line
+		}
+	}
+
+	// 의존 대상 오류는 해당 계획 위치로 보고
+	for i, plan := range plans {
+		for _, dependency := range plan.DependsOn {
+			switch count[dependency] {
+			case 0:
+				return &PlanImportError{Line: doc.Plans[i].line, Message: fmt.Sprintf("plan %q depends on unknown plan %q", planKey(plan), dependency)}
+			case 1:
+			default:
+				return &PlanImportError{Line: doc.Plans[i].line, Message: fmt.Sprintf("plan %q depends on ambiguous plan %q", planKey(plan), dependency)}
+			}
+		}
+	}
+
+	if err := ValidatePlanDependencies(plans); err != nil {
+		line := 0
+		var cycleErr *DependencyCycleError
+		if errors.As(err, &cycleErr) {
+			line = lineByKey[cycleErr.Cycle[0]]
+		}
+		return &PlanImportError{Line: line, Message: err.Error()}
+	}
+	return nil
+}
+
+func parseJSONDocument(content string) (*planDocument, error) {
+	// 문법 오류는 encoding/json으로 잡아 오프셋을 줄 번호로 변환
+	var raw any
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &PlanImportError{Line: lineAtOffset(content, syntaxErr.Offset), Message: syntaxErr.Error()}
+		}
+		return nil, &PlanImportError{Message: err.Error()}
+	}
+	// JSON은 YAML의 부분집합이므로 줄 번호를 얻기 위해 YAML 디코더로 구조를 읽음
+	return parseYAMLDocument(content)
+}
+
+func parseYAMLDocument(content string) (*planDocument, error) {
+	var doc planDocument
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, yamlImportError(err)
+	}
+	return &doc, nil
+}
+
+// yamlImportError yaml 에러 메시지("yaml: line N: ...")에서 줄 번호를 분리
+func yamlImportError(err error) error {
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		err = errors.New(typeErr.Errors[0])
+	}
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	var line int
+	if n, _ := fmt.Sscanf(message, "line %d:", &line); n == 1 {
+		message = strings.TrimSpace(message[strings.Index(message, ":")+1:])
+		return &PlanImportError{Line: line, Message: message}
+	}
+	if errors.Is(err, io.EOF) {
+		message = "document is empty"
+	}
+	return &PlanImportError{Message: message}
+}
+
+func lineAtOffset(content string, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return strings.Count(content[:offset], "\n") + 1
+}
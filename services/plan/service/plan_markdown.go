@@ -0,0 +1,225 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown 계획 문서 형식
+//
+//	# Dev Plan
+//
+//	@language go
+//	@prompt 사용자 관리 API
+//
+//	## Plan UserService
+//	@dependsOn UserRepository, Logger
+//
+//	@name CreateUser
+//	@params name string, email string
+//	@returns *User, error
+//	@description 사용자를 생성합니다.
+//	여러 줄 설명은 다음 줄에 이어서 작성합니다.
+//
+// 함수 계획은 클래스 이름 없이 "## Plan"으로 시작합니다.
+// @name, @language를 제외한 값은 여러 줄로 쓸 수 있으며, 두 번째 줄부터 '@', '#', '\'로 시작하는 줄은 앞에 '\'를 붙여 이스케이프합니다.
+
+const (
+	markdownTitle      = "# Dev Plan"
+	markdownPlanPrefix = "## Plan"
+)
+
+func exportMarkdown(doc planDocument) string {
+	var b strings.Builder
+	b.WriteString(markdownTitle + "\n\n")
+	writeMarkdownField(&b, "language", doc.Language)
+	if doc.Prompt != "" {
+		writeMarkdownField(&b, "prompt", doc.Prompt)
+	}
+
+	for _, plan := range doc.Plans {
+		b.WriteString("\n" + markdownPlanPrefix)
+		if plan.ClassName != "" {
+			b.WriteString(" " + plan.ClassName)
+		}
+		b.WriteString("\n")
+		if len(plan.DependsOn) > 0 {
+			writeMarkdownField(&b, "dependsOn", strings.Join(plan.DependsOn, ", "))
+		}
+
+		for _, annotation := range plan.Annotations {
+			b.WriteString("\n")
+			writeMarkdownField(&b, "name", annotation.Name)
+			if annotation.Params != "" {
+				writeMarkdownField(&b, "params", annotation.Params)
+			}
+			if annotation.Returns != "" {
+				writeMarkdownField(&b, "returns", annotation.Returns)
+			}
+			if annotation.Description != "" {
+				writeMarkdownField(&b, "description", annotation.Description)
+			}
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdownField(b *strings.Builder, key string, value string) {
+	lines := strings.Split(value, "\n")
+	b.WriteString("@" + key)
+	if lines[0] != "" {
+		b.WriteString(" " + lines[0])
+	}
+	b.WriteString("\n")
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "@") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, `\`) {
+			line = `\` + line
+		}
+		b.WriteString(line + "\n")
+	}
+}
+
+// markdownParser 줄 단위로 문서를 읽으며 현재 위치(계획, 어노테이션, 값)를 추적
+type markdownParser struct {
+	doc        planDocument
+	plan       *planDocumentPlan
+	annotation *planDocumentAnnotation
+	field      *string         // 다음 줄이 이어 붙을 값
+	seen       map[string]bool // 현재 블록에서 이미 나온 지시자
+}
+
+func parseMarkdownDocument(content string) (*planDocument, error) {
+	p := &markdownParser{seen: make(map[string]bool)}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if err := p.parseLine(i+1, strings.TrimRight(line, " \t")); err != nil {
+			return nil, err
+		}
+	}
+	p.finishField()
+
+	for i := range p.doc.Plans {
+		plan := &p.doc.Plans[i]
+		for _, name := range strings.Split(plan.dependsOnRaw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				plan.DependsOn = append(plan.DependsOn, name)
+			}
+		}
+	}
+	return &p.doc, nil
+}
+
+func (p *markdownParser) parseLine(lineNo int, line string) error {
+	switch {
+	case line == markdownTitle || strings.HasPrefix(line, markdownTitle+" "):
+		if len(p.doc.Plans) > 0 || p.doc.Language != "" {
+			return &PlanImportError{Line: lineNo, Message: "document title must come first"}
+		}
+		p.finishField()
+		return nil
+
+	case line == markdownPlanPrefix || strings.HasPrefix(line, markdownPlanPrefix+" "):
+		p.finishField()
+		p.doc.Plans = append(p.doc.Plans, planDocumentPlan{
+			ClassName: strings.TrimSpace(strings.TrimPrefix(line, markdownPlanPrefix)),
+			line:      lineNo,
+		})
+		p.plan = &p.doc.Plans[len(p.doc.Plans)-1]
+		p.annotation = nil
+		p.seen = make(map[string]bool)
+		return nil
+
+	case strings.HasPrefix(line, "#"):
+		return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("unexpected heading %q (expected %q)", line, markdownPlanPrefix+" <ClassName>")}
+
+	case strings.HasPrefix(line, "@"):
+		p.finishField()
+		key, value, _ := strings.Cut(line[1:], " ")
+		return p.parseDirective(lineNo, key, strings.TrimSpace(value))
+	}
+
+	if p.field != nil {
+		if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		*p.field += "\n" + line
+		return nil
+	}
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+	return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("unexpected text %q outside of a directive", line)}
+}
+
+func (p *markdownParser) parseDirective(lineNo int, key string, value string) error {
+	scope := "document"
+	switch {
+	case p.annotation != nil:
+		scope = "annotation"
+	case p.plan != nil:
+		scope = "plan"
+	}
+
+	// @name은 새 어노테이션을 시작
+	if key == "name" {
+		if p.plan == nil {
+			return &PlanImportError{Line: lineNo, Message: "@name must be inside a plan section"}
+		}
+		p.plan.Annotations = append(p.plan.Annotations, planDocumentAnnotation{Name: value, line: lineNo})
+		p.annotation = &p.plan.Annotations[len(p.plan.Annotations)-1]
+		p.seen = map[string]bool{"name": true}
+		return nil
+	}
+
+	var field *string
+	switch key {
+	case "language", "prompt":
+		if scope != "document" {
+			return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("@%s must come before the first plan section", key)}
+		}
+		if key == "language" {
+			field = &p.doc.Language
+		} else {
+			field = &p.doc.Prompt
+		}
+	case "dependsOn":
+		if scope != "plan" {
+			return &PlanImportError{Line: lineNo, Message: "@dependsOn must come right after the plan heading"}
+		}
+		field = &p.plan.dependsOnRaw
+	case "params", "returns", "description":
+		if scope != "annotation" {
+			return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("@%s must follow @name", key)}
+		}
+		switch key {
+		case "params":
+			field = &p.annotation.Params
+		case "returns":
+			field = &p.annotation.Returns
+		default:
+			field = &p.annotation.Description
+		}
+	default:
+		return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("unknown directive @%s", key)}
+	}
+
+	if p.seen[key] {
+		return &PlanImportError{Line: lineNo, Message: fmt.Sprintf("duplicate @%s", key)}
+	}
+	p.seen[key] = true
+	*field = value
+	// language는 한 줄 값, 나머지는 다음 줄로 이어 쓸 수 있음
+	if key != "language" {
+		p.field = field
+	}
+	return nil
+}
+
+// finishField 이어 붙이던 값의 끝 빈 줄을 정리
+func (p *markdownParser) finishField() {
+	if p.field != nil {
+		*p.field = strings.TrimRight(*p.field, "\n")
+		p.field = nil
+	}
+}
@@ -4,55 +4,135 @@ import (
 	"context"
 
 	"codev42-plan/model"
+	"codev42-plan/storage"
 	"codev42-plan/storage/repo"
+
+	"gorm.io/gorm"
 )
 
 type PlanService struct {
+	db             *storage.RDBConnection
 	devPlanRepo    repo.DevPlanRepository
 	planRepo       repo.PlanRepository
 	annotationRepo repo.AnnotationRepository
+	revisionRepo   repo.DevPlanRevisionRepository
+	dependencyRepo repo.PlanDependencyRepository
 }
 
 // NewPlanService 생성
 func NewPlanService(
+	db *storage.RDBConnection,
 	devPlanRepo repo.DevPlanRepository,
 	planRepo repo.PlanRepository,
 	annotationRepo repo.AnnotationRepository,
+	revisionRepo repo.DevPlanRevisionRepository,
+	dependencyRepo repo.PlanDependencyRepository,
 ) *PlanService {
 	return &PlanService{
+		db:             db,
 		devPlanRepo:    devPlanRepo,
 		planRepo:       planRepo,
 		annotationRepo: annotationRepo,
+		revisionRepo:   revisionRepo,
+		dependencyRepo: dependencyRepo,
+	}
+}
+
+// withTx는 모든 저장소가 주어진 트랜잭션을 사용하는 PlanService를 반환합니다.
+func (s *PlanService) withTx(tx *gorm.DB) *PlanService {
+	return &PlanService{
+		db:             &storage.RDBConnection{DB: tx},
+		devPlanRepo:    s.devPlanRepo.WithTx(tx),
+		planRepo:       s.planRepo.WithTx(tx),
+		annotationRepo: s.annotationRepo.WithTx(tx),
+		revisionRepo:   s.revisionRepo.WithTx(tx),
+		dependencyRepo: s.dependencyRepo.WithTx(tx),
 	}
 }
 
+// transaction은 fn을 하나의 트랜잭션에서 실행합니다. fn이 에러를 반환하면 모든 변경이 롤백됩니다.
+func (s *PlanService) transaction(ctx context.Context, fn func(txSvc *PlanService) error) error {
+	return s.db.WithTransaction(ctx, func(tx *gorm.DB) error {
+		return fn(s.withTx(tx))
+	})
+}
+
+// CreateDevPlanWithDetails는 DevPlan과 Plan, Annotation을 하나의 트랜잭션으로 생성합니다.
 func (s *PlanService) CreateDevPlanWithDetails(ctx context.Context, devPlan *model.DevPlan) error {
-	if err := s.devPlanRepo.CreateDevPlan(ctx, devPlan); err != nil {
+	if err := ValidatePlanDependencies(devPlan.Plans); err != nil {
 		return err
 	}
 
-	for i := range devPlan.Plans {
-		devPlan.Plans[i].DevPlanID = devPlan.ID
-		if err := s.planRepo.CreatePlan(ctx, &devPlan.Plans[i]); err != nil {
+	return s.transaction(ctx, func(txSvc *PlanService) error {
+		if err := txSvc.devPlanRepo.CreateDevPlan(ctx, devPlan); err != nil {
 			return err
 		}
 
-		for j := range devPlan.Plans[i].Annotations {
-			devPlan.Plans[i].Annotations[j].PlanID = devPlan.Plans[i].ID
-			if err := s.annotationRepo.CreateAnnotation(ctx, &devPlan.Plans[i].Annotations[j]); err != nil {
-				return err
-			}
+		for i := range devPlan.Plans {
+			devPlan.Plans[i].ID = 0
+			devPlan.Plans[i].DevPlanID = devPlan.ID
 		}
+		if err := txSvc.createPlansWithAnnotations(ctx, devPlan.Plans); err != nil {
+			return err
+		}
+		if err := txSvc.saveDependencies(ctx, devPlan.Plans); err != nil {
+			return err
+		}
+
+		_, err := txSvc.recordRevision(ctx, devPlan, model.RevisionActionCreate)
+		return err
+	})
+}
+
+// createPlansWithAnnotations는 Plan을 배치로 생성한 뒤 생성된 ID로 Annotation을 배치 생성합니다.
+func (s *PlanService) createPlansWithAnnotations(ctx context.Context, plans []model.Plan) error {
+	if err := s.planRepo.CreatePlans(ctx, plans); err != nil {
+		return err
 	}
 
-	return nil
+	var annotations []*model.Annotation
+	for i := range plans {
+		for j := range plans[i].Annotations {
+			annotation := &plans[i].Annotations[j]
+			annotation.ID = 0
+			annotation.PlanID = plans[i].ID
+			annotations = append(annotations, annotation)
+		}
+	}
+	return s.annotationRepo.CreateAnnotations(ctx, annotations)
 }
 
+// UpdateDevPlanWithDetails는 DevPlan을 수정하고 수정된 상태를 리비전으로 남깁니다.
+// 수정과 리비전 기록은 하나의 트랜잭션으로 처리됩니다.
 func (s *PlanService) UpdateDevPlanWithDetails(ctx context.Context, devPlan *model.DevPlan) error {
+	return s.transaction(ctx, func(txSvc *PlanService) error {
+		if err := txSvc.ensureBaselineRevision(ctx, devPlan.ID); err != nil {
+			return err
+		}
+		if err := txSvc.updateDevPlan(ctx, devPlan); err != nil {
+			return err
+		}
+
+		_, err := txSvc.recordRevision(ctx, devPlan, model.RevisionActionModify)
+		return err
+	})
+}
+
+// updateDevPlan은 전달되지 않은 Plan/Annotation을 삭제하면서 DevPlan을 덮어씁니다.
+func (s *PlanService) updateDevPlan(ctx context.Context, devPlan *model.DevPlan) error {
+	if err := ValidatePlanDependencies(devPlan.Plans); err != nil {
+		return err
+	}
+
 	if err := s.devPlanRepo.UpdateDevPlan(ctx, devPlan); err != nil {
 		return err
 	}
 
+	// 0. 의존 관계는 Plan 정리가 끝난 뒤 다시 기록합니다
+	if err := s.dependencyRepo.DeleteDependenciesByDevPlanID(ctx, devPlan.ID); err != nil {
+		return err
+	}
+
 	// 1. 기존 Plan들을 가져옵니다
 	existingPlans, err := s.planRepo.GetPlansByDevPlanID(ctx, devPlan.ID)
 	if err != nil {
@@ -66,6 +146,8 @@ func (s *PlanService) UpdateDevPlanWithDetails(ctx context.Context, devPlan *mod
 	}
 
 	// 3. 새로운 Plan들을 처리합니다
+	var newPlans []model.Plan
+	var newPlanIndexes []int
 	for i := range devPlan.Plans {
 		devPlan.Plans[i].DevPlanID = devPlan.ID
 
@@ -116,24 +198,21 @@ func (s *PlanService) UpdateDevPlanWithDetails(ctx context.Context, devPlan *mod
 
 			delete(existingPlanIDs, devPlan.Plans[i].ID)
 		} else {
-			// 3.6 새로운 Plan 생성
+			// 3.6 새로운 Plan은 모아서 배치로 생성합니다
 			devPlan.Plans[i].ID = 0 // ID 초기화
-			if err := s.planRepo.CreatePlan(ctx, &devPlan.Plans[i]); err != nil {
-				return err
-			}
-
-			// 3.7 새로운 Plan의 Annotation들을 생성합니다
-			for j := range devPlan.Plans[i].Annotations {
-				annotation := &devPlan.Plans[i].Annotations[j]
-				annotation.PlanID = devPlan.Plans[i].ID
-				annotation.ID = 0 // ID 초기화
-				if err := s.annotationRepo.CreateAnnotation(ctx, annotation); err != nil {
-					return err
-				}
-			}
+			newPlans = append(newPlans, devPlan.Plans[i])
+			newPlanIndexes = append(newPlanIndexes, i)
 		}
 	}
 
+	// 3.7 새로운 Plan과 Annotation들을 배치로 생성하고 생성된 ID를 반영합니다
+	if err := s.createPlansWithAnnotations(ctx, newPlans); err != nil {
+		return err
+	}
+	for k, i := range newPlanIndexes {
+		devPlan.Plans[i] = newPlans[k]
+	}
+
 	// 4. 남은 Plan들과 관련된 모든 Annotation들을 삭제합니다
 	for planID := range existingPlanIDs {
 		// 4.1 먼저 Plan에 속한 모든 Annotation들을 삭제합니다
@@ -146,33 +225,19 @@ func (s *PlanService) UpdateDevPlanWithDetails(ctx context.Context, devPlan *mod
 		}
 	}
 
-	return nil
+	// 5. 의존 관계를 기록합니다
+	return s.saveDependencies(ctx, devPlan.Plans)
 }
 
 // GetDevPlanByID는 Plan 및 Annotation과 함께 DevPlan을 검색합니다.
 func (s *PlanService) GetDevPlanByID(ctx context.Context, id int64) (*model.DevPlan, error) {
-	// 1. DevPlan 가져오기
-	devPlan, err := s.devPlanRepo.GetDevPlanByID(ctx, id)
+	devPlan, err := s.devPlanRepo.GetDevPlanWithDetailsByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-
-	// 2. 이 DevPlan에 대한 Plan 가져오기
-	plans, err := s.planRepo.GetPlansByDevPlanID(ctx, id)
-	if err != nil {
+	if err := s.attachDependencies(ctx, devPlan); err != nil {
 		return nil, err
 	}
-
-	// 3. 각 Plan에 대한 Annotation을 가져와 전체 구조를 빌드합니다.
-	for i := range plans {
-		annotations, err := s.annotationRepo.GetAnnotationsByPlanID(ctx, plans[i].ID)
-		if err != nil {
-			return nil, err
-		}
-		plans[i].Annotations = annotations
-	}
-	devPlan.Plans = plans
-
 	return devPlan, nil
 }
 
@@ -185,26 +250,70 @@ func (s *PlanService) GetDevPlansByProjectID(ctx context.Context, projectID stri
 	return devPlans, nil
 }
 
-// DeleteDevPlan은 DevPlan 및 모든 관련 Plan 및 Annotation을 삭제합니다.
+// DeleteDevPlan은 DevPlan 및 모든 관련 Plan, Annotation, 리비전을 하나의 트랜잭션으로 삭제합니다.
 func (s *PlanService) DeleteDevPlan(ctx context.Context, id int64) error {
-	// 1. 이 DevPlan에 대한 Plan 가져오기
+	return s.transaction(ctx, func(txSvc *PlanService) error {
+		return txSvc.deleteDevPlan(ctx, id)
+	})
+}
+
+// DeleteDevPlansByBranch는 프로젝트 브랜치의 모든 DevPlan을 하나의 트랜잭션으로 삭제하고 삭제된 ID를 반환합니다.
+func (s *PlanService) DeleteDevPlansByBranch(ctx context.Context, projectID string, branch string) ([]int64, error) {
+	devPlans, err := s.devPlanRepo.GetDevPlansByProjectID(ctx, projectID, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(devPlans))
+	for i, devPlan := range devPlans {
+		ids[i] = devPlan.ID
+	}
+
+	err = s.transaction(ctx, func(txSvc *PlanService) error {
+		for _, id := range ids {
+			if err := txSvc.deleteDevPlan(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// deleteDevPlan은 자식 레코드부터 순서대로 DevPlan을 삭제합니다.
+func (s *PlanService) deleteDevPlan(ctx context.Context, id int64) error {
+	// 1. 존재하지 않는 DevPlan 삭제는 에러로 처리
+	if _, err := s.devPlanRepo.GetDevPlanByID(ctx, id); err != nil {
+		return err
+	}
+
+	// 2. 이 DevPlan에 대한 Plan 가져오기
 	plans, err := s.planRepo.GetPlansByDevPlanID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	// 2. 각 Plan에 대한 모든 Annotation 삭제
+	// 3. 의존 관계와 각 Plan에 대한 모든 Annotation 삭제
+	if err := s.dependencyRepo.DeleteDependenciesByDevPlanID(ctx, id); err != nil {
+		return err
+	}
 	for _, plan := range plans {
 		if err := s.annotationRepo.DeleteAnnotationsByPlanID(ctx, plan.ID); err != nil {
 			return err
 		}
 	}
 
-	// 3. 이 DevPlan에 대한 모든 Plan 삭제
+	// 4. 이 DevPlan에 대한 모든 Plan과 리비전 삭제
 	if err := s.planRepo.DeletePlansByDevPlanID(ctx, id); err != nil {
 		return err
 	}
+	if err := s.revisionRepo.DeleteRevisionsByDevPlanID(ctx, id); err != nil {
+		return err
+	}
 
-	// 4. DevPlan 삭제
+	// 5. DevPlan 삭제
 	return s.devPlanRepo.DeleteDevPlan(ctx, id)
 }
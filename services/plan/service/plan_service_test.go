@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"codev42-plan/model"
+	"codev42-plan/storage"
+	"codev42-plan/storage/repo"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingAnnotationRepo Annotation 생성이 항상 실패하는 저장소 (트랜잭션 안에서도 실패하도록 WithTx도 감쌈)
+type failingAnnotationRepo struct {
+	repo.AnnotationRepository
+}
+
+func (r failingAnnotationRepo) WithTx(tx *gorm.DB) repo.AnnotationRepository {
+	return failingAnnotationRepo{r.AnnotationRepository.WithTx(tx)}
+}
+
+func (r failingAnnotationRepo) CreateAnnotation(ctx context.Context, annotation *model.Annotation) error {
+	return errInjected
+}
+
+func (r failingAnnotationRepo) CreateAnnotations(ctx context.Context, annotations []*model.Annotation) error {
+	return errInjected
+}
+
+// failingDependencyRepo 의존 관계 생성이 항상 실패하는 저장소
+type failingDependencyRepo struct {
+	repo.PlanDependencyRepository
+}
+
+func (r failingDependencyRepo) WithTx(tx *gorm.DB) repo.PlanDependencyRepository {
+	return failingDependencyRepo{r.PlanDependencyRepository.WithTx(tx)}
+}
+
+func (r failingDependencyRepo) CreateDependencies(ctx context.Context, dependencies []model.PlanDependency) error {
+	return errInjected
+}
+
+// newSQLiteConnection MySQL 대신 SQLite 파일로 Plan 테이블을 만듦
+func newSQLiteConnection(t *testing.T) *storage.RDBConnection {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "plan.db")+"?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	conn := &storage.RDBConnection{DB: db}
+	t.Cleanup(func() { conn.Close() })
+
+	if err := db.AutoMigrate(&model.Project{}, &model.DevPlan{}, &model.Plan{}, &model.Annotation{}, &model.PlanDependency{}, &model.DevPlanRevision{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := db.Create(&model.Project{ID: "project", Branch: "main", Name: "project"}).Error; err != nil {
+		t.Fatalf("failed to create project: %v", err)
+	}
+	return conn
+}
+
+// newTestPlanService nil이 아닌 저장소는 그대로 사용하고 나머지는 SQLite 저장소로 채운 PlanService
+func newTestPlanService(conn *storage.RDBConnection, annotationRepo repo.AnnotationRepository, dependencyRepo repo.PlanDependencyRepository) *PlanService {
+	if annotationRepo == nil {
+		annotationRepo = repo.NewAnnotationRepository(conn)
+	}
+	if dependencyRepo == nil {
+		dependencyRepo = repo.NewPlanDependencyRepository(conn)
+	}
+	return NewPlanService(
+		conn,
+		repo.NewDevPlanRepository(conn),
+		repo.NewPlanRepository(conn),
+		annotationRepo,
+		repo.NewDevPlanRevisionRepository(conn),
+		dependencyRepo,
+	)
+}
+
+func newTestDevPlan() *model.DevPlan {
+	return &model.DevPlan{
+		ProjectID: "project",
+		Branch:    "main",
+		Language:  "go",
+		Plans: []model.Plan{
+			{ClassName: "Repository", Annotations: []model.Annotation{{Name: "Get", Params: "id", Returns: "Item"}}},
+			{ClassName: "Service", DependsOn: []string{"Repository"}, Annotations: []model.Annotation{{Name: "Run", Returns: "error"}}},
+		},
+	}
+}
+
+// rowCounts 각 테이블의 행 수
+type rowCounts struct {
+	devPlans, plans, annotations, dependencies, revisions int64
+}
+
+func countRows(t *testing.T, conn *storage.RDBConnection) rowCounts {
+	t.Helper()
+	var counts rowCounts
+	for _, table := range []struct {
+		model any
+		count *int64
+	}{
+		{&model.DevPlan{}, &counts.devPlans},
+		{&model.Plan{}, &counts.plans},
+		{&model.Annotation{}, &counts.annotations},
+		{&model.PlanDependency{}, &counts.dependencies},
+		{&model.DevPlanRevision{}, &counts.revisions},
+	} {
+		if err := conn.DB.Model(table.model).Count(table.count).Error; err != nil {
+			t.Fatalf("failed to count rows: %v", err)
+		}
+	}
+	return counts
+}
+
+func TestCreateDevPlanWithDetailsRollsBack(t *testing.T) {
+	tests := map[string]func(conn *storage.RDBConnection) *PlanService{
+		"annotation": func(conn *storage.RDBConnection) *PlanService {
+			return newTestPlanService(conn, failingAnnotationRepo{repo.NewAnnotationRepository(conn)}, nil)
+		},
+		"dependency": func(conn *storage.RDBConnection) *PlanService {
+			return newTestPlanService(conn, nil, failingDependencyRepo{repo.NewPlanDependencyRepository(conn)})
+		},
+	}
+	for name, newService := range tests {
+		t.Run(name, func(t *testing.T) {
+			conn := newSQLiteConnection(t)
+
+			err := newService(conn).CreateDevPlanWithDetails(context.Background(), newTestDevPlan())
+			if !errors.Is(err, errInjected) {
+				t.Fatalf("expected injected failure, got %v", err)
+			}
+			if counts := countRows(t, conn); counts != (rowCounts{}) {
+				t.Fatalf("failed create left rows behind: %+v", counts)
+			}
+		})
+	}
+}
+
+func TestCreateDevPlanWithDetails(t *testing.T) {
+	conn := newSQLiteConnection(t)
+	svc := newTestPlanService(conn, nil, nil)
+
+	devPlan := newTestDevPlan()
+	if err := svc.CreateDevPlanWithDetails(context.Background(), devPlan); err != nil {
+		t.Fatalf("CreateDevPlanWithDetails: %v", err)
+	}
+	want := rowCounts{devPlans: 1, plans: 2, annotations: 2, dependencies: 1, revisions: 1}
+	if counts := countRows(t, conn); counts != want {
+		t.Fatalf("expected %+v, got %+v", want, counts)
+	}
+}
+
+func TestUpdateDevPlanWithDetailsRollsBack(t *testing.T) {
+	tests := map[string]func(conn *storage.RDBConnection) *PlanService{
+		"annotation": func(conn *storage.RDBConnection) *PlanService {
+			return newTestPlanService(conn, failingAnnotationRepo{repo.NewAnnotationRepository(conn)}, nil)
+		},
+		"dependency": func(conn *storage.RDBConnection) *PlanService {
+			return newTestPlanService(conn, nil, failingDependencyRepo{repo.NewPlanDependencyRepository(conn)})
+		},
+	}
+	for name, newService := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			conn := newSQLiteConnection(t)
+			devPlan := newTestDevPlan()
+			if err := newTestPlanService(conn, nil, nil).CreateDevPlanWithDetails(ctx, devPlan); err != nil {
+				t.Fatalf("CreateDevPlanWithDetails: %v", err)
+			}
+			before := countRows(t, conn)
+
+			// Repository를 삭제하고 새 Plan과 Annotation을 추가하는 수정
+			update := &model.DevPlan{
+				ID:        devPlan.ID,
+				ProjectID: devPlan.ProjectID,
+				Branch:    devPlan.Branch,
+				Language:  "python",
+				Plans: []model.Plan{
+					{ID: devPlan.Plans[1].ID, ClassName: "Service", DependsOn: []string{"Cache"}, Annotations: []model.Annotation{{Name: "Stop"}}},
+					{ClassName: "Cache", Annotations: []model.Annotation{{Name: "Put"}}},
+				},
+			}
+			err := newService(conn).UpdateDevPlanWithDetails(ctx, update)
+			if !errors.Is(err, errInjected) {
+				t.Fatalf("expected injected failure, got %v", err)
+			}
+
+			if after := countRows(t, conn); after != before {
+				t.Fatalf("failed update changed rows: before %+v, after %+v", before, after)
+			}
+			stored, err := newTestPlanService(conn, nil, nil).GetDevPlanByID(ctx, devPlan.ID)
+			if err != nil {
+				t.Fatalf("GetDevPlanByID: %v", err)
+			}
+			if stored.Language != "go" || len(stored.Plans) != 2 || stored.Plans[0].ClassName != "Repository" || stored.Plans[1].Annotations[0].Name != "Run" {
+				t.Fatalf("failed update changed the dev plan: %+v", stored)
+			}
+		})
+	}
+}
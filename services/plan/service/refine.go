@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"codev42-plan/model"
+)
+
+// ToServiceDevPlan은 저장된 DevPlan을 MasterAgent 입력 형식으로 변환합니다.
+func ToServiceDevPlan(devPlan *model.DevPlan) *DevPlan {
+	plans := make([]Plan, len(devPlan.Plans))
+	for i, plan := range devPlan.Plans {
+		annotations := make([]Annotation, len(plan.Annotations))
+		for j, annotation := range plan.Annotations {
+			annotations[j] = Annotation{
+				Name:        annotation.Name,
+				Params:      annotation.Params,
+				Returns:     annotation.Returns,
+				Description: annotation.Description,
+			}
+		}
+		plans[i] = Plan{
+			ClassName:   plan.ClassName,
+			Annotations: annotations,
+			DependsOn:   plan.DependsOn,
+		}
+	}
+	return &DevPlan{
+		Language: devPlan.Language,
+		Plans:    plans,
+	}
+}
+
+// CurrentRevision은 DevPlan의 최신 리비전 번호를 반환합니다.
+// 리비전 기록 이전에 만들어진 DevPlan이면 현재 상태를 먼저 리비전으로 남깁니다.
+func (s *PlanService) CurrentRevision(ctx context.Context, devPlanID int64) (int32, error) {
+	var current int32
+	err := s.transaction(ctx, func(txSvc *PlanService) error {
+		if err := txSvc.ensureBaselineRevision(ctx, devPlanID); err != nil {
+			return err
+		}
+		latest, err := txSvc.revisionRepo.GetLatestRevision(ctx, devPlanID)
+		if err != nil {
+			return err
+		}
+		current = latest.Revision
+		return nil
+	})
+	return current, err
+}
+
+// ValidatePlanDelta는 delta가 DevPlan에 그대로 적용될 수 있는지 확인합니다.
+func ValidatePlanDelta(devPlan *model.DevPlan, delta *PlanDelta) error {
+	_, err := applyPlanDelta(devPlan.Plans, delta)
+	return err
+}
+
+// AcceptRefinement는 사용자가 수락한 delta를 DevPlan에 적용하고 새 리비전으로 남깁니다.
+// delta를 제안받은 이후 DevPlan이 변경되었다면(baseRevision 불일치) 적용하지 않습니다.
+func (s *PlanService) AcceptRefinement(ctx context.Context, devPlanID int64, baseRevision int32, delta *PlanDelta) (*model.DevPlanRevision, error) {
+	var accepted *model.DevPlanRevision
+	err := s.transaction(ctx, func(txSvc *PlanService) error {
+		latest, err := txSvc.revisionRepo.GetLatestRevision(ctx, devPlanID)
+		if err != nil {
+			return err
+		}
+		if latest == nil || latest.Revision != baseRevision {
+			return fmt.Errorf("dev plan %d has changed since revision %d, refine it again", devPlanID, baseRevision)
+		}
+
+		devPlan, err := txSvc.GetDevPlanByID(ctx, devPlanID)
+		if err != nil {
+			return err
+		}
+		plans, err := applyPlanDelta(devPlan.Plans, delta)
+		if err != nil {
+			return err
+		}
+		devPlan.Plans = plans
+
+		if err := txSvc.updateDevPlan(ctx, devPlan); err != nil {
+			return err
+		}
+		accepted, err = txSvc.recordRevision(ctx, devPlan, model.RevisionActionRefine)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accepted, nil
+}
+
+// planKey는 delta에서 계획을 가리키는 이름을 반환합니다. 함수 계획은 함수 이름을 사용합니다.
+func planKey(plan model.Plan) string {
+	if plan.ClassName == "" && len(plan.Annotations) > 0 {
+		return plan.Annotations[0].Name
+	}
+	return plan.ClassName
+}
+
+// applyPlanDelta는 기존 Plan 목록에 delta를 적용한 새 목록을 반환합니다.
+// 유지되거나 변경된 Plan/Annotation은 ID를 그대로 유지하므로 바뀐 행만 갱신됩니다.
+func applyPlanDelta(plans []model.Plan, delta *PlanDelta) ([]model.Plan, error) {
+	result := make([]model.Plan, len(plans))
+	for i, plan := range plans {
+		result[i] = plan
+		result[i].Annotations = append([]model.Annotation(nil), plan.Annotations...)
+		result[i].DependsOn = append([]string(nil), plan.DependsOn...)
+	}
+
+	indexOf := func(name string) int {
+		for i, plan := range result {
+			if planKey(plan) == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, change := range delta.ChangedPlans {
+		i := indexOf(change.Name)
+		if i < 0 {
+			return nil, fmt.Errorf("changed plan %q not found", change.Name)
+		}
+		annotations, err := applyAnnotationChange(result[i].Annotations, change)
+		if err != nil {
+			return nil, fmt.Errorf("plan %q: %v", change.Name, err)
+		}
+		result[i].Annotations = annotations
+	}
+
+	for _, name := range delta.RemovedPlans {
+		i := indexOf(name)
+		if i < 0 {
+			return nil, fmt.Errorf("removed plan %q not found", name)
+		}
+		result = append(result[:i], result[i+1:]...)
+
+		// 삭제된 계획에 대한 의존 관계도 제거
+		for j := range result {
+			result[j].DependsOn = removeName(result[j].DependsOn, name)
+		}
+	}
+
+	for _, added := range delta.AddedPlans {
+		plan := model.Plan{
+			ClassName:   added.ClassName,
+			Annotations: toModelAnnotations(added.Annotations),
+			DependsOn:   added.DependsOn,
+		}
+		if indexOf(planKey(plan)) >= 0 {
+			return nil, fmt.Errorf("added plan %q already exists", planKey(plan))
+		}
+		result = append(result, plan)
+	}
+
+	return result, nil
+}
+
+func applyAnnotationChange(annotations []model.Annotation, change PlanChange) ([]model.Annotation, error) {
+	indexOf := func(name string) int {
+		for i, annotation := range annotations {
+			if annotation.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, changed := range change.ChangedAnnotations {
+		i := indexOf(changed.Name)
+		if i < 0 {
+			return nil, fmt.Errorf("changed annotation %q not found", changed.Name)
+		}
+		annotations[i].Params = changed.Params
+		annotations[i].Returns = changed.Returns
+		annotations[i].Description = changed.Description
+	}
+
+	for _, name := range change.RemovedAnnotations {
+		i := indexOf(name)
+		if i < 0 {
+			return nil, fmt.Errorf("removed annotation %q not found", name)
+		}
+		annotations = append(annotations[:i], annotations[i+1:]...)
+	}
+
+	for _, added := range change.AddedAnnotations {
+		if indexOf(added.Name) >= 0 {
+			return nil, fmt.Errorf("added annotation %q already exists", added.Name)
+		}
+		annotations = append(annotations, toModelAnnotations([]Annotation{added})...)
+	}
+
+	return annotations, nil
+}
+
+// removeName은 names에서 name을 뺀 새 목록을 반환합니다.
+func removeName(names []string, name string) []string {
+	var result []string
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func toModelAnnotations(annotations []Annotation) []model.Annotation {
+	result := make([]model.Annotation, len(annotations))
+	for i, annotation := range annotations {
+		result[i] = model.Annotation{
+			Name:        annotation.Name,
+			Params:      annotation.Params,
+			Returns:     annotation.Returns,
+			Description: annotation.Description,
+		}
+	}
+	return result
+}
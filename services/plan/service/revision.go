@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"codev42-plan/model"
+)
+
+// 리비전 비교 결과의 변경 종류
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// AnnotationDiff 두 리비전 사이의 Annotation 변경
+type AnnotationDiff struct {
+	Name       string
+	ChangeType string
+	Before     *model.SnapshotAnnotation
+	After      *model.SnapshotAnnotation
+}
+
+// PlanDiff 두 리비전 사이의 Plan 변경 (ClassName 기준, 함수는 함수 이름 기준으로 매칭)
+type PlanDiff struct {
+	ClassName   string
+	ChangeType  string
+	Annotations []AnnotationDiff
+
+	// 의존 관계가 바뀐 경우에만 채워짐
+	DependsOnBefore []string
+	DependsOnAfter  []string
+}
+
+// RevisionDiff 두 리비전의 비교 결과
+type RevisionDiff struct {
+	FromRevision int32
+	ToRevision   int32
+	FromLanguage string
+	ToLanguage   string
+	Plans        []PlanDiff
+}
+
+// newRevisionSnapshot은 DevPlan의 현재 내용을 스냅샷으로 변환합니다.
+func newRevisionSnapshot(devPlan *model.DevPlan) *model.RevisionSnapshot {
+	snapshot := &model.RevisionSnapshot{
+		Language: devPlan.Language,
+		Plans:    make([]model.SnapshotPlan, len(devPlan.Plans)),
+	}
+	for i, plan := range devPlan.Plans {
+		annotations := make([]model.SnapshotAnnotation, len(plan.Annotations))
+		for j, annotation := range plan.Annotations {
+			annotations[j] = model.SnapshotAnnotation{
+				Name:        annotation.Name,
+				Params:      annotation.Params,
+				Returns:     annotation.Returns,
+				Description: annotation.Description,
+			}
+		}
+		snapshot.Plans[i] = model.SnapshotPlan{
+			ClassName:   plan.ClassName,
+			Annotations: annotations,
+			DependsOn:   plan.DependsOn,
+		}
+	}
+	return snapshot
+}
+
+// DecodeRevisionSnapshot은 리비전에 저장된 스냅샷을 읽습니다.
+func DecodeRevisionSnapshot(revision *model.DevPlanRevision) (*model.RevisionSnapshot, error) {
+	var snapshot model.RevisionSnapshot
+	if err := json.Unmarshal(revision.Snapshot, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %d of dev plan %d: %v", revision.Revision, revision.DevPlanID, err)
+	}
+	return &snapshot, nil
+}
+
+// recordRevision은 DevPlan의 현재 상태를 다음 번호의 리비전으로 저장합니다.
+func (s *PlanService) recordRevision(ctx context.Context, devPlan *model.DevPlan, action string) (*model.DevPlanRevision, error) {
+	snapshot, err := json.Marshal(newRevisionSnapshot(devPlan))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode revision snapshot: %v", err)
+	}
+
+	revision := &model.DevPlanRevision{
+		DevPlanID: devPlan.ID,
+		Action:    action,
+		Snapshot:  snapshot,
+	}
+	if err := s.revisionRepo.CreateRevision(ctx, revision); err != nil {
+		return nil, fmt.Errorf("failed to save revision: %v", err)
+	}
+	return revision, nil
+}
+
+// ensureBaselineRevision은 리비전 기록 이전에 만들어진 DevPlan을 수정하기 전에 현재 상태를 보존합니다.
+func (s *PlanService) ensureBaselineRevision(ctx context.Context, devPlanID int64) error {
+	latest, err := s.revisionRepo.GetLatestRevision(ctx, devPlanID)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		return nil
+	}
+
+	current, err := s.GetDevPlanByID(ctx, devPlanID)
+	if err != nil {
+		return err
+	}
+	_, err = s.recordRevision(ctx, current, model.RevisionActionCreate)
+	return err
+}
+
+// ListRevisions는 DevPlan의 리비전 목록을 조회합니다.
+func (s *PlanService) ListRevisions(ctx context.Context, devPlanID int64) ([]model.DevPlanRevision, error) {
+	return s.revisionRepo.ListRevisions(ctx, devPlanID)
+}
+
+// GetRevision은 리비전과 스냅샷을 함께 조회합니다.
+func (s *PlanService) GetRevision(ctx context.Context, devPlanID int64, revision int32) (*model.DevPlanRevision, *model.RevisionSnapshot, error) {
+	devPlanRevision, err := s.revisionRepo.GetRevision(ctx, devPlanID, revision)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, err := DecodeRevisionSnapshot(devPlanRevision)
+	if err != nil {
+		return nil, nil, err
+	}
+	return devPlanRevision, snapshot, nil
+}
+
+// DiffRevisions는 두 리비전의 Plan/Annotation 변경 사항을 계산합니다.
+func (s *PlanService) DiffRevisions(ctx context.Context, devPlanID int64, fromRevision int32, toRevision int32) (*RevisionDiff, error) {
+	_, from, err := s.GetRevision(ctx, devPlanID, fromRevision)
+	if err != nil {
+		return nil, err
+	}
+	_, to, err := s.GetRevision(ctx, devPlanID, toRevision)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		FromLanguage: from.Language,
+		ToLanguage:   to.Language,
+		Plans:        diffSnapshots(from, to),
+	}, nil
+}
+
+// RevertDevPlan은 DevPlan을 지정한 리비전의 내용으로 되돌리고, 되돌린 상태를 새 리비전으로 남깁니다.
+// 되돌리기와 리비전 기록은 하나의 트랜잭션으로 처리됩니다.
+func (s *PlanService) RevertDevPlan(ctx context.Context, devPlanID int64, revision int32) (*model.DevPlanRevision, error) {
+	_, snapshot, err := s.GetRevision(ctx, devPlanID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	var reverted *model.DevPlanRevision
+	err = s.transaction(ctx, func(txSvc *PlanService) error {
+		devPlan, err := txSvc.devPlanRepo.GetDevPlanByID(ctx, devPlanID)
+		if err != nil {
+			return err
+		}
+
+		// 스냅샷의 Plan은 ID가 없으므로 기존 Plan/Annotation은 모두 교체됨
+		devPlan.Language = snapshot.Language
+		devPlan.Plans = make([]model.Plan, len(snapshot.Plans))
+		for i, snapshotPlan := range snapshot.Plans {
+			annotations := make([]model.Annotation, len(snapshotPlan.Annotations))
+			for j, snapshotAnnotation := range snapshotPlan.Annotations {
+				annotations[j] = model.Annotation{
+					Name:        snapshotAnnotation.Name,
+					Params:      snapshotAnnotation.Params,
+					Returns:     snapshotAnnotation.Returns,
+					Description: snapshotAnnotation.Description,
+				}
+			}
+			devPlan.Plans[i] = model.Plan{
+				ClassName:   snapshotPlan.ClassName,
+				Annotations: annotations,
+				DependsOn:   snapshotPlan.DependsOn,
+			}
+		}
+
+		if err := txSvc.updateDevPlan(ctx, devPlan); err != nil {
+			return err
+		}
+		reverted, err = txSvc.recordRevision(ctx, devPlan, model.RevisionActionRevert)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverted, nil
+}
+
+// snapshotPlanKey는 리비전 간 Plan을 매칭할 키를 만듭니다. 함수(ClassName 없음)는 함수 이름으로 구분합니다.
+func snapshotPlanKey(plan model.SnapshotPlan) string {
+	if plan.ClassName == "" && len(plan.Annotations) > 0 {
+		return "func:" + plan.Annotations[0].Name
+	}
+	return "class:" + plan.ClassName
+}
+
+func diffSnapshots(from *model.RevisionSnapshot, to *model.RevisionSnapshot) []PlanDiff {
+	fromPlans := make(map[string]model.SnapshotPlan, len(from.Plans))
+	for _, plan := range from.Plans {
+		fromPlans[snapshotPlanKey(plan)] = plan
+	}
+
+	diffs := []PlanDiff{}
+	seen := make(map[string]bool, len(to.Plans))
+	for _, toPlan := range to.Plans {
+		key := snapshotPlanKey(toPlan)
+		seen[key] = true
+
+		fromPlan, ok := fromPlans[key]
+		if !ok {
+			diffs = append(diffs, PlanDiff{
+				ClassName:   toPlan.ClassName,
+				ChangeType:  ChangeAdded,
+				Annotations: diffAnnotations(nil, toPlan.Annotations),
+			})
+			continue
+		}
+
+		annotationDiffs := diffAnnotations(fromPlan.Annotations, toPlan.Annotations)
+		dependsOnChanged := !sameDependencies(fromPlan.DependsOn, toPlan.DependsOn)
+		if len(annotationDiffs) > 0 || dependsOnChanged {
+			planDiff := PlanDiff{
+				ClassName:   toPlan.ClassName,
+				ChangeType:  ChangeModified,
+				Annotations: annotationDiffs,
+			}
+			if dependsOnChanged {
+				planDiff.DependsOnBefore = fromPlan.DependsOn
+				planDiff.DependsOnAfter = toPlan.DependsOn
+			}
+			diffs = append(diffs, planDiff)
+		}
+	}
+
+	// 이전 리비전 순서대로 삭제된 Plan 추가
+	for _, fromPlan := range from.Plans {
+		if seen[snapshotPlanKey(fromPlan)] {
+			continue
+		}
+		diffs = append(diffs, PlanDiff{
+			ClassName:   fromPlan.ClassName,
+			ChangeType:  ChangeRemoved,
+			Annotations: diffAnnotations(fromPlan.Annotations, nil),
+		})
+	}
+
+	return diffs
+}
+
+// sameDependencies는 순서와 관계없이 두 의존 목록이 같은지 비교합니다.
+func sameDependencies(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, name := range a {
+		counts[name]++
+	}
+	for _, name := range b {
+		counts[name]--
+		if counts[name] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func diffAnnotations(from []model.SnapshotAnnotation, to []model.SnapshotAnnotation) []AnnotationDiff {
+	fromByName := make(map[string]model.SnapshotAnnotation, len(from))
+	for _, annotation := range from {
+		fromByName[annotation.Name] = annotation
+	}
+
+	diffs := []AnnotationDiff{}
+	seen := make(map[string]bool, len(to))
+	for i := range to {
+		after := to[i]
+		seen[after.Name] = true
+
+		before, ok := fromByName[after.Name]
+		switch {
+		case !ok:
+			diffs = append(diffs, AnnotationDiff{Name: after.Name, ChangeType: ChangeAdded, After: &after})
+		case before != after:
+			diffs = append(diffs, AnnotationDiff{Name: after.Name, ChangeType: ChangeModified, Before: &before, After: &after})
+		}
+	}
+
+	for i := range from {
+		before := from[i]
+		if seen[before.Name] {
+			continue
+		}
+		diffs = append(diffs, AnnotationDiff{Name: before.Name, ChangeType: ChangeRemoved, Before: &before})
+	}
+
+	return diffs
+}
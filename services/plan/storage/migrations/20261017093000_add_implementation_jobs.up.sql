@@ -0,0 +1,16 @@
+-- create "implementation_jobs" table
+CREATE TABLE `implementation_jobs` (
+  `id` varchar(36) NOT NULL,
+  `dev_plan_id` bigint NOT NULL,
+  `status` varchar(32) NOT NULL,
+  `progress` int NOT NULL DEFAULT 0,
+  `current_step` varchar(255) NULL,
+  `result` json NULL,
+  `error` text NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `completed_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementation_jobs_dev_plan_id` (`dev_plan_id`),
+  INDEX `idx_implementation_jobs_status` (`status`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
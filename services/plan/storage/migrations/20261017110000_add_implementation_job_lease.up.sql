@@ -0,0 +1,2 @@
+-- modify "implementation_jobs" table
+ALTER TABLE `implementation_jobs` ADD COLUMN `lease_owner` varchar(255) NULL, ADD COLUMN `lease_expires_at` datetime(3) NULL, ADD COLUMN `attempts` int NOT NULL DEFAULT 0, ADD INDEX `idx_implementation_jobs_lease` (`status`, `lease_expires_at`);
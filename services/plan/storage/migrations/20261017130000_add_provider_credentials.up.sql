@@ -0,0 +1,12 @@
+-- create "provider_credentials" table
+CREATE TABLE `provider_credentials` (
+  `tenant_id` varchar(255) NOT NULL,
+  `provider` varchar(32) NOT NULL,
+  `encrypted_api_key` text NOT NULL,
+  `base_url` varchar(512) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`tenant_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+-- modify "implementation_jobs" table
+ALTER TABLE `implementation_jobs` ADD COLUMN `tenant_id` varchar(255) NULL AFTER `dev_plan_id`;
@@ -0,0 +1,12 @@
+-- create "dev_plan_revisions" table
+CREATE TABLE `dev_plan_revisions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `dev_plan_id` bigint NOT NULL,
+  `revision` int NOT NULL,
+  `action` varchar(32) NOT NULL,
+  `snapshot` json NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_dev_plan_revisions_revision` (`dev_plan_id`, `revision`),
+  CONSTRAINT `fk_dev_plan_revisions_dev_plan` FOREIGN KEY (`dev_plan_id`) REFERENCES `dev_plans` (`id`) ON UPDATE RESTRICT ON DELETE RESTRICT
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
@@ -0,0 +1,12 @@
+-- create "plan_dependencies" table
+CREATE TABLE `plan_dependencies` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `plan_id` bigint NOT NULL,
+  `depends_on_plan_id` bigint NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_plan_dependencies_edge` (`plan_id`, `depends_on_plan_id`),
+  INDEX `idx_plan_dependencies_depends_on_plan_id` (`depends_on_plan_id`),
+  CONSTRAINT `fk_plan_dependencies_depends_on_plan` FOREIGN KEY (`depends_on_plan_id`) REFERENCES `plans` (`id`) ON UPDATE RESTRICT ON DELETE RESTRICT,
+  CONSTRAINT `fk_plan_dependencies_plan` FOREIGN KEY (`plan_id`) REFERENCES `plans` (`id`) ON UPDATE RESTRICT ON DELETE RESTRICT
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
@@ -0,0 +1,16 @@
+-- create "implementation_job_events" table
+CREATE TABLE `implementation_job_events` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `job_id` varchar(36) NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `stage` varchar(64) NULL,
+  `progress` int NOT NULL DEFAULT 0,
+  `message` text NULL,
+  `plan_index` int NOT NULL DEFAULT 0,
+  `plan_id` bigint NOT NULL DEFAULT 0,
+  `path` varchar(255) NULL,
+  `diagram` varchar(64) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementation_job_events_job` (`job_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
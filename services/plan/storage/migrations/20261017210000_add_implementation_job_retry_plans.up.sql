@@ -0,0 +1,2 @@
+-- modify "implementation_jobs" table
+ALTER TABLE `implementation_jobs` ADD COLUMN `retry_plan_ids` json NULL;
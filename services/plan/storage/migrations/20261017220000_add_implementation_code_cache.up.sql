@@ -0,0 +1,10 @@
+-- create "implementation_code_cache" table
+CREATE TABLE `implementation_code_cache` (
+  `tenant_id` varchar(255) NOT NULL,
+  `language` varchar(32) NOT NULL,
+  `content_hash` varchar(64) NOT NULL,
+  `file` json NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`tenant_id`, `language`, `content_hash`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
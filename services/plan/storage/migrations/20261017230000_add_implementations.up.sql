@@ -0,0 +1,18 @@
+-- create "implementations" table
+CREATE TABLE `implementations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `dev_plan_id` bigint NOT NULL,
+  `job_id` varchar(36) NOT NULL,
+  `tenant_id` varchar(255) NULL,
+  `status` varchar(32) NOT NULL,
+  `language` varchar(255) NULL,
+  `provider` varchar(32) NULL,
+  `model` varchar(255) NULL,
+  `file_count` int NOT NULL DEFAULT 0,
+  `result` json NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementations_dev_plan_id` (`dev_plan_id`),
+  INDEX `idx_implementations_job_id` (`job_id`),
+  CONSTRAINT `fk_implementations_dev_plan` FOREIGN KEY (`dev_plan_id`) REFERENCES `dev_plans` (`id`) ON UPDATE RESTRICT ON DELETE RESTRICT
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
@@ -0,0 +1,2 @@
+-- modify "implementation_jobs" table
+ALTER TABLE `implementation_jobs` ADD INDEX `idx_implementation_jobs_created_at` (`created_at`), ADD INDEX `idx_implementation_jobs_completed_at` (`completed_at`);
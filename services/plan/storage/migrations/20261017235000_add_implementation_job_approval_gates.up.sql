@@ -0,0 +1,2 @@
+-- modify "implementation_jobs" table
+ALTER TABLE `implementation_jobs` ADD COLUMN `approval_gates` json NULL, ADD COLUMN `passed_gates` json NULL, ADD COLUMN `pending_gate` varchar(32) NULL, ADD COLUMN `review_feedback` text NULL;
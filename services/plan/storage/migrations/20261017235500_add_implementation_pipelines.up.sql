@@ -0,0 +1,8 @@
+-- create "implementation_pipelines" table
+CREATE TABLE `implementation_pipelines` (
+  `project_id` varchar(255) NOT NULL,
+  `definition` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`project_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
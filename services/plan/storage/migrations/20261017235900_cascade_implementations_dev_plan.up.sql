@@ -0,0 +1,2 @@
+-- modify "implementations" table
+ALTER TABLE `implementations` DROP FOREIGN KEY `fk_implementations_dev_plan`, ADD CONSTRAINT `fk_implementations_dev_plan` FOREIGN KEY (`dev_plan_id`) REFERENCES `dev_plans` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE;
@@ -5,13 +5,21 @@ import (
 
 	"codev42-plan/model"
 	"codev42-plan/storage"
+
+	"gorm.io/gorm"
 )
 
 // AnnotationRepository는 Annotation 엔티티에 대한 작업을 정의합니다.
 type AnnotationRepository interface {
+	// WithTx는 주어진 트랜잭션에서 동작하는 AnnotationRepository를 반환합니다.
+	WithTx(tx *gorm.DB) AnnotationRepository
+
 	// CreateAnnotation는 새로운 Annotation을 생성합니다.
 	CreateAnnotation(ctx context.Context, annotation *model.Annotation) error
 
+	// CreateAnnotations는 여러 Annotation을 배치로 생성합니다.
+	CreateAnnotations(ctx context.Context, annotations []*model.Annotation) error
+
 	// UpdateAnnotation는 기존 Annotation을 업데이트합니다.
 	UpdateAnnotation(ctx context.Context, annotation *model.Annotation) error
 
@@ -38,11 +46,26 @@ func NewAnnotationRepository(dbConn *storage.RDBConnection) AnnotationRepository
 	return &AnnotationRepo{dbConn: dbConn}
 }
 
+// WithTx는 주어진 트랜잭션에서 동작하는 AnnotationRepository를 반환합니다.
+func (r *AnnotationRepo) WithTx(tx *gorm.DB) AnnotationRepository {
+	return &AnnotationRepo{dbConn: &storage.RDBConnection{DB: tx}}
+}
+
 // CreateAnnotation는 새로운 Annotation을 생성합니다.
 func (r *AnnotationRepo) CreateAnnotation(ctx context.Context, annotation *model.Annotation) error {
 	return r.dbConn.DB.WithContext(ctx).Omit("id").Create(annotation).Error
 }
 
+// CreateAnnotations는 여러 Annotation을 배치로 생성합니다.
+func (r *AnnotationRepo) CreateAnnotations(ctx context.Context, annotations []*model.Annotation) error {
+	if len(annotations) == 0 {
+		return nil
+	}
+	return r.dbConn.DB.WithContext(ctx).
+		Omit("id", "Plan").
+		CreateInBatches(annotations, createBatchSize).Error
+}
+
 // UpdateAnnotation는 기존 Annotation을 업데이트합니다.
 func (r *AnnotationRepo) UpdateAnnotation(ctx context.Context, annotation *model.Annotation) error {
 	return r.dbConn.DB.WithContext(ctx).Save(annotation).Error
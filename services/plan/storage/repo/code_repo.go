@@ -8,6 +8,14 @@ import (
 	"codev42-plan/storage"
 )
 
+// ProjectCode : 파일 경로가 포함된 코드 조각
+type ProjectCode struct {
+	ID              int64
+	FilePath        string
+	FuncDeclaration string
+	CodeChunk       string
+}
+
 // CodeRepo : Code 엔티티에 대한 MySQL Repo
 type CodeRepo struct {
 	dbConn *storage.RDBConnection
@@ -37,6 +45,25 @@ func (r *CodeRepo) GetCodeByFileIdAndName(ctx context.Context, fileID int64, fun
 	return &fn, nil
 }
 
+// GetProjectCodesByIDs : 프로젝트 브랜치에 속한 코드만 ID로 조회 (다른 프로젝트의 ID는 제외)
+func (r *CodeRepo) GetProjectCodesByIDs(ctx context.Context, ids []int64, projectID string, branch string) ([]ProjectCode, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var codes []ProjectCode
+	err := r.dbConn.DB.WithContext(ctx).
+		Model(&model.Code{}).
+		Select("codes.id, files.file_path, codes.func_declaration, codes.code_chunk").
+		Joins("JOIN files ON files.id = codes.file_id").
+		Where("codes.id IN ? AND files.project_id = ? AND files.project_branch = ?", ids, projectID, branch).
+		Scan(&codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
 // UpdateCode : 함수 수정
 func (r *CodeRepo) UpdateCode(ctx context.Context, fn *model.Code) error {
 	return r.dbConn.DB.WithContext(ctx).Save(fn).Error
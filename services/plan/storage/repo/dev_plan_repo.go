@@ -5,10 +5,16 @@ import (
 
 	"codev42-plan/model"
 	"codev42-plan/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // DevPlanRepository는 DevPlan 엔티티에 대한 작업을 정의합니다.
 type DevPlanRepository interface {
+	// WithTx는 주어진 트랜잭션에서 동작하는 DevPlanRepository를 반환합니다.
+	WithTx(tx *gorm.DB) DevPlanRepository
+
 	// CreateDevPlan는 새로운 DevPlan을 생성합니다.
 	CreateDevPlan(ctx context.Context, devPlan *model.DevPlan) error
 
@@ -18,6 +24,9 @@ type DevPlanRepository interface {
 	// GetDevPlanByID는 ID로 DevPlan을 조회합니다.
 	GetDevPlanByID(ctx context.Context, id int64) (*model.DevPlan, error)
 
+	// GetDevPlanWithDetailsByID는 Plan과 Annotation을 preload하여 DevPlan을 조회합니다.
+	GetDevPlanWithDetailsByID(ctx context.Context, id int64) (*model.DevPlan, error)
+
 	// GetDevPlansByProjectID는 프로젝트의 모든 DevPlan을 조회합니다.
 	GetDevPlansByProjectID(ctx context.Context, projectID string, branch string) ([]DevPlanListElement, error)
 
@@ -40,14 +49,19 @@ func NewDevPlanRepository(dbConn *storage.RDBConnection) DevPlanRepository {
 	return &DevPlanRepo{dbConn: dbConn}
 }
 
-// CreateDevPlan는 새로운 DevPlan을 생성합니다.
+// WithTx는 주어진 트랜잭션에서 동작하는 DevPlanRepository를 반환합니다.
+func (r *DevPlanRepo) WithTx(tx *gorm.DB) DevPlanRepository {
+	return &DevPlanRepo{dbConn: &storage.RDBConnection{DB: tx}}
+}
+
+// CreateDevPlan는 새로운 DevPlan을 생성합니다. Plan과 Annotation은 함께 저장되지 않습니다.
 func (r *DevPlanRepo) CreateDevPlan(ctx context.Context, devPlan *model.DevPlan) error {
-	return r.dbConn.DB.WithContext(ctx).Create(devPlan).Error
+	return r.dbConn.DB.WithContext(ctx).Omit(clause.Associations).Create(devPlan).Error
 }
 
-// UpdateDevPlan는 기존 DevPlan을 업데이트합니다.
+// UpdateDevPlan는 기존 DevPlan을 업데이트합니다. Plan과 Annotation은 함께 저장되지 않습니다.
 func (r *DevPlanRepo) UpdateDevPlan(ctx context.Context, devPlan *model.DevPlan) error {
-	return r.dbConn.DB.WithContext(ctx).Save(devPlan).Error
+	return r.dbConn.DB.WithContext(ctx).Omit(clause.Associations).Save(devPlan).Error
 }
 
 // GetDevPlanByID는 ID로 DevPlan을 조회합니다.
@@ -64,6 +78,26 @@ func (r *DevPlanRepo) GetDevPlanByID(ctx context.Context, id int64) (*model.DevP
 	return &devPlan, nil
 }
 
+// GetDevPlanWithDetailsByID는 Plan과 Annotation을 preload하여 DevPlan을 조회합니다.
+func (r *DevPlanRepo) GetDevPlanWithDetailsByID(ctx context.Context, id int64) (*model.DevPlan, error) {
+	var devPlan model.DevPlan
+	err := r.dbConn.DB.WithContext(ctx).
+		Preload("Plans", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("Plans.Annotations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Where("id = ?", id).
+		First(&devPlan).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &devPlan, nil
+}
+
 // GetDevPlansByProjectID는 프로젝트의 모든 DevPlan을 조회합니다.
 func (r *DevPlanRepo) GetDevPlansByProjectID(ctx context.Context, projectID string, branch string) ([]DevPlanListElement, error) {
 	var devPlanList []DevPlanListElement
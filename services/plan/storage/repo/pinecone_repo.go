@@ -101,27 +101,12 @@ func (r *PineconeRepo) DeleteByID(ctx context.Context, collectionName string, id
 }
 
 func (r *PineconeRepo) SearchByVector(ctx context.Context, collectionName string, searchVector []float32, topK int) ([]int64, error) {
-
-	metadataMap := map[string]interface{}{
-		"genre": map[string]interface{}{
-			"$eq": "documentary",
-		},
-		"year": 2019,
-	}
-
-	metadataFilter, err := structpb.NewStruct(metadataMap)
-	if err != nil {
-		log.Fatalf("메타데이터 필터 생성에 실패했습니다: %v", err)
-	}
-
 	res, err := r.idxConnection.QueryByVectorValues(ctx, &pinecone.QueryByVectorValuesRequest{
-		Vector:         searchVector,
-		TopK:           3,
-		MetadataFilter: metadataFilter,
-		IncludeValues:  true,
+		Vector: searchVector,
+		TopK:   uint32(topK),
 	})
 	if err != nil {
-		log.Fatalf("벡터로 쿼리하는 동안 오류가 발생했습니다: %v", err)
+		return nil, fmt.Errorf("벡터로 쿼리하는 동안 오류가 발생했습니다: %w", err)
 	}
 	var ids []int64
 	for _, match := range res.Matches {
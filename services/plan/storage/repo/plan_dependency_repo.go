@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"context"
+
+	"codev42-plan/model"
+	"codev42-plan/storage"
+
+	"gorm.io/gorm"
+)
+
+// PlanDependencyRepository는 PlanDependency 엔티티에 대한 작업을 정의합니다.
+type PlanDependencyRepository interface {
+	// WithTx는 주어진 트랜잭션에서 동작하는 PlanDependencyRepository를 반환합니다.
+	WithTx(tx *gorm.DB) PlanDependencyRepository
+
+	// CreateDependencies는 여러 의존 관계를 배치로 생성합니다.
+	CreateDependencies(ctx context.Context, dependencies []model.PlanDependency) error
+
+	// GetDependenciesByDevPlanID는 DevPlan에 속한 Plan들의 모든 의존 관계를 조회합니다.
+	GetDependenciesByDevPlanID(ctx context.Context, devPlanID int64) ([]model.PlanDependency, error)
+
+	// DeleteDependenciesByDevPlanID는 DevPlan에 속한 Plan들의 모든 의존 관계를 삭제합니다.
+	DeleteDependenciesByDevPlanID(ctx context.Context, devPlanID int64) error
+}
+
+// PlanDependencyRepo는 PlanDependencyRepository의 구현체입니다.
+type PlanDependencyRepo struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewPlanDependencyRepository는 새로운 PlanDependencyRepository를 생성합니다.
+func NewPlanDependencyRepository(dbConn *storage.RDBConnection) PlanDependencyRepository {
+	return &PlanDependencyRepo{dbConn: dbConn}
+}
+
+// WithTx는 주어진 트랜잭션에서 동작하는 PlanDependencyRepository를 반환합니다.
+func (r *PlanDependencyRepo) WithTx(tx *gorm.DB) PlanDependencyRepository {
+	return &PlanDependencyRepo{dbConn: &storage.RDBConnection{DB: tx}}
+}
+
+// CreateDependencies는 여러 의존 관계를 배치로 생성합니다.
+func (r *PlanDependencyRepo) CreateDependencies(ctx context.Context, dependencies []model.PlanDependency) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+	return r.dbConn.DB.WithContext(ctx).
+		Omit("id", "Plan", "DependsOnPlan").
+		CreateInBatches(dependencies, createBatchSize).Error
+}
+
+// GetDependenciesByDevPlanID는 DevPlan에 속한 Plan들의 모든 의존 관계를 조회합니다.
+func (r *PlanDependencyRepo) GetDependenciesByDevPlanID(ctx context.Context, devPlanID int64) ([]model.PlanDependency, error) {
+	var dependencies []model.PlanDependency
+	err := r.dbConn.DB.WithContext(ctx).
+		Where("plan_id IN (?)", r.planIDs(devPlanID)).
+		Order("id ASC").
+		Find(&dependencies).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+// DeleteDependenciesByDevPlanID는 DevPlan에 속한 Plan들의 모든 의존 관계를 삭제합니다.
+func (r *PlanDependencyRepo) DeleteDependenciesByDevPlanID(ctx context.Context, devPlanID int64) error {
+	return r.dbConn.DB.WithContext(ctx).
+		Where("plan_id IN (?)", r.planIDs(devPlanID)).
+		Delete(&model.PlanDependency{}).Error
+}
+
+// planIDs는 DevPlan에 속한 Plan ID 서브쿼리를 만듭니다.
+func (r *PlanDependencyRepo) planIDs(devPlanID int64) *gorm.DB {
+	return r.dbConn.DB.Model(&model.Plan{}).Select("id").Where("dev_plan_id = ?", devPlanID)
+}
@@ -5,13 +5,22 @@ import (
 
 	"codev42-plan/model"
 	"codev42-plan/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PlanRepository는 Plan 엔티티에 대한 작업을 정의합니다.
 type PlanRepository interface {
+	// WithTx는 주어진 트랜잭션에서 동작하는 PlanRepository를 반환합니다.
+	WithTx(tx *gorm.DB) PlanRepository
+
 	// CreatePlan는 새로운 Plan을 생성합니다.
 	CreatePlan(ctx context.Context, plan *model.Plan) error
 
+	// CreatePlans는 여러 Plan을 배치로 생성합니다. Annotation은 함께 저장되지 않습니다.
+	CreatePlans(ctx context.Context, plans []model.Plan) error
+
 	// UpdatePlan는 기존 Plan을 업데이트합니다.
 	UpdatePlan(ctx context.Context, plan *model.Plan) error
 
@@ -28,6 +37,9 @@ type PlanRepository interface {
 	DeletePlansByDevPlanID(ctx context.Context, devPlanID int64) error
 }
 
+// createBatchSize는 배치 INSERT 한 번에 넣는 최대 행 수입니다.
+const createBatchSize = 100
+
 // PlanEntityRepo는 PlanRepository의 구현체입니다.
 type PlanEntityRepo struct {
 	dbConn *storage.RDBConnection
@@ -38,14 +50,29 @@ func NewPlanRepository(dbConn *storage.RDBConnection) PlanRepository {
 	return &PlanEntityRepo{dbConn: dbConn}
 }
 
+// WithTx는 주어진 트랜잭션에서 동작하는 PlanRepository를 반환합니다.
+func (r *PlanEntityRepo) WithTx(tx *gorm.DB) PlanRepository {
+	return &PlanEntityRepo{dbConn: &storage.RDBConnection{DB: tx}}
+}
+
 // CreatePlan는 새로운 Plan을 생성합니다.
 func (r *PlanEntityRepo) CreatePlan(ctx context.Context, plan *model.Plan) error {
 	return r.dbConn.DB.WithContext(ctx).Omit("id").Create(plan).Error
 }
 
-// UpdatePlan는 기존 Plan을 업데이트합니다.
+// CreatePlans는 여러 Plan을 배치로 생성합니다. Annotation은 함께 저장되지 않습니다.
+func (r *PlanEntityRepo) CreatePlans(ctx context.Context, plans []model.Plan) error {
+	if len(plans) == 0 {
+		return nil
+	}
+	return r.dbConn.DB.WithContext(ctx).
+		Omit("id", "DevPlan", "Annotations").
+		CreateInBatches(plans, createBatchSize).Error
+}
+
+// UpdatePlan는 기존 Plan을 업데이트합니다. Annotation은 함께 저장되지 않습니다.
 func (r *PlanEntityRepo) UpdatePlan(ctx context.Context, plan *model.Plan) error {
-	return r.dbConn.DB.WithContext(ctx).Save(plan).Error
+	return r.dbConn.DB.WithContext(ctx).Omit(clause.Associations).Save(plan).Error
 }
 
 // GetPlanByID는 ID로 Plan을 조회합니다.
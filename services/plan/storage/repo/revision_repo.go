@@ -0,0 +1,119 @@
+package repo
+
+import (
+	"context"
+
+	"codev42-plan/model"
+	"codev42-plan/storage"
+
+	"gorm.io/gorm"
+)
+
+// DevPlanRevisionRepository는 DevPlanRevision 엔티티에 대한 작업을 정의합니다.
+type DevPlanRevisionRepository interface {
+	// WithTx는 주어진 트랜잭션에서 동작하는 DevPlanRevisionRepository를 반환합니다.
+	WithTx(tx *gorm.DB) DevPlanRevisionRepository
+
+	// CreateRevision는 DevPlan의 다음 번호로 리비전을 저장합니다.
+	CreateRevision(ctx context.Context, revision *model.DevPlanRevision) error
+
+	// GetRevision는 DevPlan의 특정 리비전을 조회합니다.
+	GetRevision(ctx context.Context, devPlanID int64, revision int32) (*model.DevPlanRevision, error)
+
+	// GetLatestRevision는 DevPlan의 최신 리비전을 조회합니다. 리비전이 없으면 nil을 반환합니다.
+	GetLatestRevision(ctx context.Context, devPlanID int64) (*model.DevPlanRevision, error)
+
+	// ListRevisions는 DevPlan의 리비전 목록을 스냅샷 없이 조회합니다.
+	ListRevisions(ctx context.Context, devPlanID int64) ([]model.DevPlanRevision, error)
+
+	// DeleteRevisionsByDevPlanID는 DevPlan의 모든 리비전을 삭제합니다.
+	DeleteRevisionsByDevPlanID(ctx context.Context, devPlanID int64) error
+}
+
+// DevPlanRevisionRepo는 DevPlanRevisionRepository의 구현체입니다.
+type DevPlanRevisionRepo struct {
+	dbConn *storage.RDBConnection
+}
+
+// NewDevPlanRevisionRepository는 새로운 DevPlanRevisionRepository를 생성합니다.
+func NewDevPlanRevisionRepository(dbConn *storage.RDBConnection) DevPlanRevisionRepository {
+	return &DevPlanRevisionRepo{dbConn: dbConn}
+}
+
+// WithTx는 주어진 트랜잭션에서 동작하는 DevPlanRevisionRepository를 반환합니다.
+func (r *DevPlanRevisionRepo) WithTx(tx *gorm.DB) DevPlanRevisionRepository {
+	return &DevPlanRevisionRepo{dbConn: &storage.RDBConnection{DB: tx}}
+}
+
+// CreateRevision는 DevPlan의 다음 번호로 리비전을 저장합니다.
+// 동시에 같은 번호가 할당되면 (dev_plan_id, revision) unique index로 실패합니다.
+func (r *DevPlanRevisionRepo) CreateRevision(ctx context.Context, revision *model.DevPlanRevision) error {
+	var latest int32
+	err := r.dbConn.DB.WithContext(ctx).
+		Model(&model.DevPlanRevision{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Where("dev_plan_id = ?", revision.DevPlanID).
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+
+	revision.Revision = latest + 1
+	return r.dbConn.DB.WithContext(ctx).Omit("id", "DevPlan").Create(revision).Error
+}
+
+// GetRevision는 DevPlan의 특정 리비전을 조회합니다.
+func (r *DevPlanRevisionRepo) GetRevision(ctx context.Context, devPlanID int64, revision int32) (*model.DevPlanRevision, error) {
+	var devPlanRevision model.DevPlanRevision
+	err := r.dbConn.DB.WithContext(ctx).
+		Where("dev_plan_id = ? AND revision = ?", devPlanID, revision).
+		First(&devPlanRevision).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &devPlanRevision, nil
+}
+
+// GetLatestRevision는 DevPlan의 최신 리비전을 조회합니다. 리비전이 없으면 nil을 반환합니다.
+func (r *DevPlanRevisionRepo) GetLatestRevision(ctx context.Context, devPlanID int64) (*model.DevPlanRevision, error) {
+	var revisions []model.DevPlanRevision
+	err := r.dbConn.DB.WithContext(ctx).
+		Where("dev_plan_id = ?", devPlanID).
+		Order("revision DESC").
+		Limit(1).
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	return &revisions[0], nil
+}
+
+// ListRevisions는 DevPlan의 리비전 목록을 스냅샷 없이 조회합니다.
+func (r *DevPlanRevisionRepo) ListRevisions(ctx context.Context, devPlanID int64) ([]model.DevPlanRevision, error) {
+	var revisions []model.DevPlanRevision
+	err := r.dbConn.DB.WithContext(ctx).
+		Select("id", "dev_plan_id", "revision", "action", "created_at").
+		Where("dev_plan_id = ?", devPlanID).
+		Order("revision ASC").
+		Find(&revisions).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// DeleteRevisionsByDevPlanID는 DevPlan의 모든 리비전을 삭제합니다.
+func (r *DevPlanRevisionRepo) DeleteRevisionsByDevPlanID(ctx context.Context, devPlanID int64) error {
+	return r.dbConn.DB.WithContext(ctx).
+		Where("dev_plan_id = ?", devPlanID).
+		Delete(&model.DevPlanRevision{}).Error
+}
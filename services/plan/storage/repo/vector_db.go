@@ -0,0 +1,12 @@
+package repo
+
+import "context"
+
+// VectorDB는 코드 embedding을 저장하고 검색하는 벡터 저장소(Pinecone, Milvus)에 대한 작업을 정의합니다.
+type VectorDB interface {
+	InitCollection(ctx context.Context, collectionName string, vectorDim int32) error
+	InsertEmbedding(ctx context.Context, collectionName string, id string, embedding []float32) error
+	SearchByVector(ctx context.Context, collectionName string, searchVector []float32, topK int) ([]int64, error)
+	DeleteByID(ctx context.Context, collectionName string, id string) error
+	Close() error
+}
@@ -0,0 +1,95 @@
+-- 다른 서비스가 사용하는 테이블의 목표 스키마 (Plan 서비스의 model에는 없으므로 atlas.hcl의 composite_schema에서 gorm 모델 뒤에 불러옴).
+-- 마이그레이션(storage/migrations)을 모두 적용한 상태와 같아야 하며, 테이블을 바꿀 때는 이 파일과 레코드 구조체를 함께 고친 뒤 `make db-diff`로 마이그레이션을 생성
+--   implementation_*: Implementation 서비스 (services/implementation/queue의 mysql_*.go)
+--   provider_credentials: 테넌트 LLM 자격 증명 (services/llm/client/credential_store.go)
+
+CREATE TABLE `implementation_jobs` (
+  `id` varchar(36) NOT NULL,
+  `dev_plan_id` bigint NOT NULL,
+  `tenant_id` varchar(255) NULL,
+  `status` varchar(32) NOT NULL,
+  `progress` int NOT NULL DEFAULT 0,
+  `current_step` varchar(255) NULL,
+  `result` json NULL,
+  `error` text NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `completed_at` datetime(3) NULL,
+  `lease_owner` varchar(255) NULL,
+  `lease_expires_at` datetime(3) NULL,
+  `attempts` int NOT NULL DEFAULT 0,
+  `retry_plan_ids` json NULL,
+  `approval_gates` json NULL,
+  `passed_gates` json NULL,
+  `pending_gate` varchar(32) NULL,
+  `review_feedback` text NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementation_jobs_dev_plan_id` (`dev_plan_id`),
+  INDEX `idx_implementation_jobs_status` (`status`),
+  INDEX `idx_implementation_jobs_lease` (`status`, `lease_expires_at`),
+  INDEX `idx_implementation_jobs_created_at` (`created_at`),
+  INDEX `idx_implementation_jobs_completed_at` (`completed_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `implementation_job_events` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `job_id` varchar(36) NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `stage` varchar(64) NULL,
+  `progress` int NOT NULL DEFAULT 0,
+  `message` text NULL,
+  `plan_index` int NOT NULL DEFAULT 0,
+  `plan_id` bigint NOT NULL DEFAULT 0,
+  `path` varchar(255) NULL,
+  `diagram` varchar(64) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementation_job_events_job` (`job_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `implementation_code_cache` (
+  `tenant_id` varchar(255) NOT NULL,
+  `language` varchar(32) NOT NULL,
+  `content_hash` varchar(64) NOT NULL,
+  `file` json NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`tenant_id`, `language`, `content_hash`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+
+-- 개발 계획을 삭제하면 구현 기록도 함께 삭제
+CREATE TABLE `implementations` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `dev_plan_id` bigint NOT NULL,
+  `job_id` varchar(36) NOT NULL,
+  `tenant_id` varchar(255) NULL,
+  `status` varchar(32) NOT NULL,
+  `language` varchar(255) NULL,
+  `provider` varchar(32) NULL,
+  `model` varchar(255) NULL,
+  `file_count` int NOT NULL DEFAULT 0,
+  `result` json NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_implementations_dev_plan_id` (`dev_plan_id`),
+  INDEX `idx_implementations_job_id` (`job_id`),
+  CONSTRAINT `fk_implementations_dev_plan` FOREIGN KEY (`dev_plan_id`) REFERENCES `dev_plans` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `implementation_pipelines` (
+  `project_id` varchar(255) NOT NULL,
+  `definition` text NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`project_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;
+
+CREATE TABLE `provider_credentials` (
+  `tenant_id` varchar(255) NOT NULL,
+  `provider` varchar(32) NOT NULL,
+  `encrypted_api_key` text NOT NULL,
+  `base_url` varchar(512) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`tenant_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_general_ci;